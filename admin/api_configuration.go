@@ -0,0 +1,133 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+NSSF Admin
+
+Operator management API of the NSSF. It is served on a listener of its own and is
+not part of the 3GPP service based interface.
+*/
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/producer"
+	"github.com/omec-project/openapi/v2"
+	"github.com/omec-project/openapi/v2/utils"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+// Get /{table}
+// Lists all entries of a configuration table
+func HTTPConfigList(table string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.AdminLog.Infof("Handle Get /%s", table)
+		req := httpwrapper.NewRequest(c.Request, nil)
+		req.Params["table"] = table
+
+		rsp := producer.HandleAdminConfigList(req)
+
+		writeResponse(c, rsp)
+	}
+}
+
+// Get /{table}/:key
+// Reads one entry of a configuration table
+func HTTPConfigGet(table string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.AdminLog.Infof("Handle Get /%s/:key", table)
+		req := httpwrapper.NewRequest(c.Request, nil)
+		req.Params["table"] = table
+		req.Params["key"] = c.Params.ByName("key")
+
+		rsp := producer.HandleAdminConfigGet(req)
+
+		writeResponse(c, rsp)
+	}
+}
+
+// Post /{table}
+// Adds a new entry to a configuration table
+func HTTPConfigCreate(table string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.AdminLog.Infof("Handle Post /%s", table)
+		requestBody, err := c.GetRawData()
+		if err != nil {
+			problemDetail := utils.ProblemDetailsSystemFailure(err.Error())
+			logger.HandlerLog.Errorf("get Request Body error: %+v", err)
+			c.JSON(http.StatusInternalServerError, problemDetail)
+			return
+		}
+
+		req := httpwrapper.NewRequest(c.Request, requestBody)
+		req.Params["table"] = table
+
+		rsp := producer.HandleAdminConfigCreate(req)
+
+		writeResponse(c, rsp)
+	}
+}
+
+// Put /{table}/:key
+// Replaces an existing entry of a configuration table
+func HTTPConfigReplace(table string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.AdminLog.Infof("Handle Put /%s/:key", table)
+		requestBody, err := c.GetRawData()
+		if err != nil {
+			problemDetail := utils.ProblemDetailsSystemFailure(err.Error())
+			logger.HandlerLog.Errorf("get Request Body error: %+v", err)
+			c.JSON(http.StatusInternalServerError, problemDetail)
+			return
+		}
+
+		req := httpwrapper.NewRequest(c.Request, requestBody)
+		req.Params["table"] = table
+		req.Params["key"] = c.Params.ByName("key")
+
+		rsp := producer.HandleAdminConfigReplace(req)
+
+		writeResponse(c, rsp)
+	}
+}
+
+// Delete /{table}/:key
+// Removes an entry from a configuration table
+func HTTPConfigDelete(table string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.AdminLog.Infof("Handle Delete /%s/:key", table)
+		req := httpwrapper.NewRequest(c.Request, nil)
+		req.Params["table"] = table
+		req.Params["key"] = c.Params.ByName("key")
+
+		rsp := producer.HandleAdminConfigDelete(req)
+
+		writeResponse(c, rsp)
+	}
+}
+
+func writeResponse(c *gin.Context, rsp *httpwrapper.Response) {
+	for key, values := range rsp.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
+
+	if rsp.Body == nil {
+		c.Status(rsp.Status)
+		return
+	}
+
+	responseBody, err := openapi.SetBody(rsp.Body, "application/json")
+	if err != nil {
+		logger.HandlerLog.Errorln(err)
+		problemDetails := utils.ProblemDetailsSystemFailure(err.Error())
+		c.JSON(http.StatusInternalServerError, problemDetails)
+	} else {
+		c.Data(rsp.Status, "application/json", responseBody.Bytes())
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+NSSF Admin
+
+Operator management API of the NSSF. It is served on a listener of its own and is
+not part of the 3GPP service based interface.
+*/
+
+package admin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/producer"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+// Get /state/amf-availability
+// Shows the S-NSSAI availability learned from the AMFs
+func HTTPStateAmfAvailability(c *gin.Context) {
+	logger.AdminLog.Infoln("Handle Get /state/amf-availability")
+	req := httpwrapper.NewRequest(c.Request, nil)
+
+	rsp := producer.HandleAdminAmfAvailability(req)
+
+	writeResponse(c, rsp)
+}
+
+// Get /state/subscriptions
+// Shows the active NSSAI availability subscriptions
+func HTTPStateSubscriptions(c *gin.Context) {
+	logger.AdminLog.Infoln("Handle Get /state/subscriptions")
+	req := httpwrapper.NewRequest(c.Request, nil)
+
+	rsp := producer.HandleAdminSubscriptions(req)
+
+	writeResponse(c, rsp)
+}
+
+// Get /state/supported-nssai-in-plmn
+// Shows the supported NSSAI per PLMN currently in use
+func HTTPStateSupportedNssaiInPlmn(c *gin.Context) {
+	logger.AdminLog.Infoln("Handle Get /state/supported-nssai-in-plmn")
+	req := httpwrapper.NewRequest(c.Request, nil)
+
+	rsp := producer.HandleAdminSupportedNssaiInPlmn(req)
+
+	writeResponse(c, rsp)
+}
+
+// Get /state/nrf-registration
+// Shows the registration state of the NSSF at the NRF
+func HTTPStateNrfRegistration(c *gin.Context) {
+	logger.AdminLog.Infoln("Handle Get /state/nrf-registration")
+	req := httpwrapper.NewRequest(c.Request, nil)
+
+	rsp := producer.HandleAdminNrfRegistration(req)
+
+	writeResponse(c, rsp)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+NSSF Admin
+
+Operator management API of the NSSF. It is served on a listener of its own and is
+not part of the 3GPP service based interface.
+*/
+
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/producer"
+	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/openapi/v2/utils"
+	utilLogger "github.com/omec-project/util/logger"
+)
+
+// Route is the information for every URI.
+type Route struct {
+	// Name is the name of this Route.
+	Name string
+	// Method is the string for the HTTP method (e.g., GET, POST, etc.)
+	Method string
+	// Pattern is the pattern of the URI.
+	Pattern string
+	// HandlerFunc is the handler function of this route.
+	HandlerFunc gin.HandlerFunc
+}
+
+// NewRouter returns a new router which only accepts requests carrying the given bearer token.
+func NewRouter(token string) *gin.Engine {
+	router := utilLogger.NewGinWithZap(logger.GinLog)
+	AddService(router, token)
+	return router
+}
+
+// AddService adds routes to an existing gin engine.
+func AddService(engine *gin.Engine, token string) *gin.RouterGroup {
+	group := engine.Group("/nssf-admin/v1", authorize(token))
+	for _, route := range getRoutes() {
+		switch route.Method {
+		case http.MethodGet:
+			group.GET(route.Pattern, route.HandlerFunc)
+		case http.MethodPost:
+			group.POST(route.Pattern, route.HandlerFunc)
+		case http.MethodPut:
+			group.PUT(route.Pattern, route.HandlerFunc)
+		case http.MethodDelete:
+			group.DELETE(route.Pattern, route.HandlerFunc)
+		}
+	}
+
+	return group
+}
+
+// authorize rejects requests which do not carry the configured bearer token
+func authorize(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !found || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			logger.AdminLog.Warnf("rejected unauthorized admin request %s %s from %s",
+				c.Request.Method, c.Request.URL.Path, c.ClientIP())
+			problemDetails := utils.ProblemDetails(util.UNAUTHORIZED_CONSUMER, http.StatusUnauthorized,
+				"missing or invalid bearer token")
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, problemDetails)
+			return
+		}
+		c.Next()
+	}
+}
+
+func getRoutes() []Route {
+	tables := []string{
+		producer.AdminTableNsiList,
+		producer.AdminTableTaList,
+		producer.AdminTableAmfSetList,
+		producer.AdminTableMappingListFromPlmn,
+		producer.AdminTableSupportedNssaiInPlmnList,
+		producer.AdminTableNsagList,
+	}
+	routes := []Route{
+		{"StateAmfAvailability", http.MethodGet, "/state/amf-availability", HTTPStateAmfAvailability},
+		{"StateSubscriptions", http.MethodGet, "/state/subscriptions", HTTPStateSubscriptions},
+		{"StateSupportedNssaiInPlmn", http.MethodGet, "/state/supported-nssai-in-plmn", HTTPStateSupportedNssaiInPlmn},
+		{"StateNrfRegistration", http.MethodGet, "/state/nrf-registration", HTTPStateNrfRegistration},
+	}
+	for _, table := range tables {
+		routes = append(routes,
+			Route{"ConfigList", http.MethodGet, "/" + table, HTTPConfigList(table)},
+			Route{"ConfigCreate", http.MethodPost, "/" + table, HTTPConfigCreate(table)},
+			Route{"ConfigGet", http.MethodGet, "/" + table + "/:key", HTTPConfigGet(table)},
+			Route{"ConfigReplace", http.MethodPut, "/" + table + "/:key", HTTPConfigReplace(table)},
+			Route{"ConfigDelete", http.MethodDelete, "/" + table + "/:key", HTTPConfigDelete(table)},
+		)
+	}
+	return routes
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/logger"
+)
+
+const (
+	defaultBindingIPv4 = "0.0.0.0"
+	defaultPort        = 8090
+	tokenEnv           = "NSSF_ADMIN_TOKEN"
+)
+
+// StartServer serves the admin API until the listener fails.
+// The admin API is not started if no bearer token is configured.
+func StartServer(adminConfig *factory.Admin) {
+	token := adminConfig.Token
+	if envToken := os.Getenv(tokenEnv); envToken != "" {
+		token = envToken
+	}
+	if token == "" {
+		logger.AdminLog.Errorf("admin API is enabled but no token is configured (set `admin.token` or %s). Admin API not started", tokenEnv)
+		return
+	}
+
+	bindingIPv4 := adminConfig.BindingIPv4
+	if bindingIPv4 == "" {
+		bindingIPv4 = defaultBindingIPv4
+	}
+	port := adminConfig.Port
+	if port == 0 {
+		port = defaultPort
+	}
+	server := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", bindingIPv4, port),
+		Handler:           NewRouter(token),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	logger.AdminLog.Infof("admin API listening on %s", server.Addr)
+	var err error
+	if tls := adminConfig.TLS; tls != nil && tls.PEM != "" && tls.Key != "" {
+		err = server.ListenAndServeTLS(tls.PEM, tls.Key)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.AdminLog.Errorf("admin API server failed: %+v", err)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Callback
+ *
+ * Notifications the NSSF receives from other NFs
+ */
+
+package callback
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/producer"
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/openapi/v2"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/openapi/v2/utils"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+// Post /nf-status-notify
+// Notifies the NSSF about a change of the status of an AMF it subscribed to at the NRF
+func HTTPNfStatusNotify(c *gin.Context) {
+	logger.Nssaiavailability.Infoln("Handle Post /nf-status-notify")
+	var notificationData models.NotificationData
+
+	requestBody, err := c.GetRawData()
+	if err != nil {
+		problemDetail := sbi.ProblemDetailsRequestBody(err)
+		logger.HandlerLog.Errorf("get Request Body error: %+v", err)
+		c.JSON(int(problemDetail.GetStatus()), problemDetail)
+		return
+	}
+
+	err = openapi.Decode(&notificationData, requestBody, "application/json")
+	if err != nil {
+		problemDetail := "[Request Body] " + err.Error()
+		rsp := utils.ProblemDetailsMalformedRequestSyntax(problemDetail)
+		logger.HandlerLog.Errorln(problemDetail)
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	req := httpwrapper.NewRequest(c.Request, notificationData)
+
+	rsp := producer.HandleNfStatusNotify(req)
+
+	c.Status(rsp.Status)
+}
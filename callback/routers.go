@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Callback
+ *
+ * Notifications the NSSF receives from other NFs
+ */
+
+package callback
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	nssfContext "github.com/omec-project/nssf/context"
+	"github.com/omec-project/nssf/logger"
+	utilLogger "github.com/omec-project/util/logger"
+)
+
+// Route is the information for every URI.
+type Route struct {
+	// Name is the name of this Route.
+	Name string
+	// Method is the string for the HTTP method (e.g., GET, POST, etc.)
+	Method string
+	// Pattern is the pattern of the URI.
+	Pattern string
+	// HandlerFunc is the handler function of this route.
+	HandlerFunc gin.HandlerFunc
+}
+
+// NewRouter returns a new router.
+func NewRouter() *gin.Engine {
+	router := utilLogger.NewGinWithZap(logger.GinLog)
+	AddService(router)
+	return router
+}
+
+// AddService adds routes to an existing gin engine.
+func AddService(engine *gin.Engine) *gin.RouterGroup {
+	group := engine.Group(nssfContext.CallbackUriPrefix)
+	for _, route := range getRoutes() {
+		switch route.Method {
+		case http.MethodPost:
+			group.POST(route.Pattern, route.HandlerFunc)
+		}
+	}
+	return group
+}
+
+func getRoutes() []Route {
+	return []Route{
+		{
+			"NfStatusNotify",
+			http.MethodPost,
+			nssfContext.NfStatusNotifyPath,
+			HTTPNfStatusNotify,
+		},
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Consumer
+ *
+ * Network Function Discovery
+ */
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	nssfContext "github.com/omec-project/nssf/context"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// SendSearchAmfInstance discovers the AMF with the NF instance ID at the NRF, TS 29.510 clause 5.3.2.2.2
+// It reports whether the AMF is registered, i.e. whether the NRF returns it, or answers 404 Not Found.
+// The AMF is searched for by its NF instance ID, so that the number of AMFs the NRF returns is not limited.
+// The search is abandoned when ctx is cancelled.
+var SendSearchAmfInstance = func(ctx context.Context, nfId string) (bool, error) {
+	logger.ConsumerLog.Debugln("send Search NFInstances")
+
+	nssfSelf := nssfContext.NSSF_Self()
+	var searchResult *models.SearchResult
+	res, err := sendToNrf(func(nrfUri string) (res *http.Response, err error) {
+		client := newNrfDiscoveryClient(nrfUri)
+		apiSearchNFInstancesRequest := client.NFInstancesStoreAPI.SearchNFInstances(nrfRequestContextOf(ctx, models.SERVICENAME_NNRF_DISC))
+		apiSearchNFInstancesRequest = apiSearchNFInstancesRequest.TargetNfType(models.NFTYPE_AMF).
+			RequesterNfType(models.NFTYPE_NSSF).RequesterNfInstanceId(nssfSelf.NfId).TargetNfInstanceId(nfId)
+		searchResult, res, err = client.NFInstancesStoreAPI.SearchNFInstancesExecute(apiSearchNFInstancesRequest)
+		return res, err
+	})
+	if res != nil && res.Body != nil {
+		defer func() {
+			if bodyCloseErr := res.Body.Close(); bodyCloseErr != nil {
+				logger.AppLog.Errorf("SearchNFInstances response body cannot close: %+v", bodyCloseErr)
+			}
+		}()
+	}
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if res == nil {
+		return false, fmt.Errorf("no response from server")
+	}
+	if res.StatusCode != http.StatusOK || searchResult == nil {
+		return false, fmt.Errorf("unexpected status code returned by the NRF %d", res.StatusCode)
+	}
+	for _, nfProfile := range searchResult.NfInstances {
+		if nfProfile.NfInstanceId == nfId &&
+			(nfProfile.NfStatus == "" || nfProfile.NfStatus == models.NFSTATUS_REGISTERED) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
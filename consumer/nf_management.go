@@ -13,15 +13,15 @@
 package consumer
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	nssfContext "github.com/omec-project/nssf/context"
 	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/sbi"
 	"github.com/omec-project/openapi/v2"
-	"github.com/omec-project/openapi/v2/Nnrf_NFManagement"
 	"github.com/omec-project/openapi/v2/models"
 )
 
@@ -39,6 +39,10 @@ func getNfProfile(currentNssfContext *nssfContext.NSSFContext, plmnConfig []mode
 		profile.SetPlmnList(plmnCopy)
 	}
 	profile.SetIpv4Addresses([]string{currentNssfContext.RegisterIPv4})
+	if load, enabled := sbi.CurrentLoad(); enabled {
+		profile.SetLoad(load)
+		profile.SetLoadTimeStamp(time.Now())
+	}
 	var services []models.NFService
 	for _, nfService := range currentNssfContext.NfService {
 		services = append(services, nfService)
@@ -55,17 +59,15 @@ var SendRegisterNFInstance = func(plmnConfig []models.PlmnId) (prof *models.NFPr
 	if err != nil {
 		return &models.NFProfile{}, "", err
 	}
-	configuration := Nnrf_NFManagement.NewConfiguration()
-	serverConfig := &configuration.Servers[0]
-	if apiRootVar, exists := serverConfig.Variables["apiRoot"]; exists {
-		apiRootVar.DefaultValue = nssfSelf.NrfUri
-		serverConfig.Variables["apiRoot"] = apiRootVar
-	}
-	apiClient := Nnrf_NFManagement.NewAPIClient(configuration)
 
-	apiRegisterNFInstanceRequest := apiClient.NFInstanceIDDocumentAPI.RegisterNFInstance(context.TODO(), nfProfile.NfInstanceId)
-	apiRegisterNFInstanceRequest = apiRegisterNFInstanceRequest.NFProfile(*nfProfile)
-	receivedNfProfile, res, err := apiClient.NFInstanceIDDocumentAPI.RegisterNFInstanceExecute(apiRegisterNFInstanceRequest)
+	var receivedNfProfile *models.NFProfile
+	res, err := sendToNrf(func(nrfUri string) (res *http.Response, err error) {
+		apiClient := newNrfManagementClient(nrfUri)
+		apiRegisterNFInstanceRequest := apiClient.NFInstanceIDDocumentAPI.RegisterNFInstance(nrfRequestContext(models.SERVICENAME_NNRF_NFM), nfProfile.NfInstanceId)
+		apiRegisterNFInstanceRequest = apiRegisterNFInstanceRequest.NFProfile(*nfProfile)
+		receivedNfProfile, res, err = apiClient.NFInstanceIDDocumentAPI.RegisterNFInstanceExecute(apiRegisterNFInstanceRequest)
+		return res, err
+	})
 	if err != nil {
 		return &models.NFProfile{}, "", err
 	}
@@ -100,18 +102,15 @@ var SendUpdateNFInstance = func(patchItem []models.PatchItem) (*models.NFProfile
 	logger.ConsumerLog.Debugln("send Update NFInstance")
 
 	nssfSelf := nssfContext.NSSF_Self()
-	configuration := Nnrf_NFManagement.NewConfiguration()
-	serverConfig := &configuration.Servers[0]
-	if apiRootVar, exists := serverConfig.Variables["apiRoot"]; exists {
-		apiRootVar.DefaultValue = nssfSelf.NrfUri
-		serverConfig.Variables["apiRoot"] = apiRootVar
-	}
-	client := Nnrf_NFManagement.NewAPIClient(configuration)
-
-	var res *http.Response
-	apiUpdateNFInstanceRequest := client.NFInstanceIDDocumentAPI.UpdateNFInstance(context.Background(), nssfSelf.NfId)
-	apiUpdateNFInstanceRequest = apiUpdateNFInstanceRequest.PatchItem(patchItem)
-	receivedNfProfile, res, err := client.NFInstanceIDDocumentAPI.UpdateNFInstanceExecute(apiUpdateNFInstanceRequest)
+
+	var receivedNfProfile *models.NFProfile
+	res, err := sendToNrf(func(nrfUri string) (res *http.Response, err error) {
+		client := newNrfManagementClient(nrfUri)
+		apiUpdateNFInstanceRequest := client.NFInstanceIDDocumentAPI.UpdateNFInstance(nrfRequestContext(models.SERVICENAME_NNRF_NFM), nssfSelf.NfId)
+		apiUpdateNFInstanceRequest = apiUpdateNFInstanceRequest.PatchItem(patchItem)
+		receivedNfProfile, res, err = client.NFInstanceIDDocumentAPI.UpdateNFInstanceExecute(apiUpdateNFInstanceRequest)
+		return res, err
+	})
 	if res != nil && res.Body != nil {
 		defer func() {
 			if bodyCloseErr := res.Body.Close(); bodyCloseErr != nil {
@@ -146,16 +145,88 @@ var SendDeregisterNFInstance = func() error {
 	logger.AppLog.Infoln("send Deregister NFInstance")
 
 	nssfSelf := nssfContext.NSSF_Self()
-	// Set client and set url
-	configuration := Nnrf_NFManagement.NewConfiguration()
-	serverConfig := &configuration.Servers[0]
-	if apiRootVar, exists := serverConfig.Variables["apiRoot"]; exists {
-		apiRootVar.DefaultValue = nssfSelf.NrfUri
-		serverConfig.Variables["apiRoot"] = apiRootVar
-	}
-	client := Nnrf_NFManagement.NewAPIClient(configuration)
-	apiDeregisterNFInstanceRequest := client.NFInstanceIDDocumentAPI.DeregisterNFInstance(context.Background(), nssfSelf.NfId)
-	res, err := client.NFInstanceIDDocumentAPI.DeregisterNFInstanceExecute(apiDeregisterNFInstanceRequest)
+	res, err := sendToNrf(func(nrfUri string) (*http.Response, error) {
+		client := newNrfManagementClient(nrfUri)
+		apiDeregisterNFInstanceRequest := client.NFInstanceIDDocumentAPI.DeregisterNFInstance(nrfRequestContext(models.SERVICENAME_NNRF_NFM), nssfSelf.NfId)
+		return client.NFInstanceIDDocumentAPI.DeregisterNFInstanceExecute(apiDeregisterNFInstanceRequest)
+	})
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return fmt.Errorf("no response from server")
+	}
+	if res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return fmt.Errorf("unexpected response code")
+}
+
+// SendCreateSubscription subscribes to the status of the AMFs at the NRF, TS 29.510 clause 5.2.2.5.2
+// It returns the subscription ID, and the time until which the subscription is valid, if limited.
+var SendCreateSubscription = func(nfStatusNotificationUri string) (subscriptionId string, validityTime *time.Time, err error) {
+	logger.ConsumerLog.Debugln("send Create Subscription")
+
+	nssfSelf := nssfContext.NSSF_Self()
+
+	amfType := models.NFTYPE_AMF
+	nssfType := models.NFTYPE_NSSF
+	subscrCond := models.NfTypeCondAsSubscrCond(&models.NfTypeCond{NfType: &amfType})
+	subscriptionData := models.SubscriptionData{
+		NfStatusNotificationUri: nfStatusNotificationUri,
+		ReqNfInstanceId:         openapi.PtrString(nssfSelf.NfId),
+		SubscrCond:              &subscrCond,
+		ReqNotifEvents: []models.NotificationEventType{
+			models.NOTIFICATIONEVENTTYPE_NF_REGISTERED,
+			models.NOTIFICATIONEVENTTYPE_NF_DEREGISTERED,
+			models.NOTIFICATIONEVENTTYPE_NF_PROFILE_CHANGED,
+		},
+		ReqNfType: &nssfType,
+	}
+	var receivedSubscriptionData *models.SubscriptionData
+	res, err := sendToNrf(func(nrfUri string) (res *http.Response, err error) {
+		client := newNrfManagementClient(nrfUri)
+		apiCreateSubscriptionRequest := client.SubscriptionsCollectionAPI.CreateSubscription(nrfRequestContext(models.SERVICENAME_NNRF_NFM))
+		apiCreateSubscriptionRequest = apiCreateSubscriptionRequest.SubscriptionData(subscriptionData)
+		receivedSubscriptionData, res, err = client.SubscriptionsCollectionAPI.CreateSubscriptionExecute(apiCreateSubscriptionRequest)
+		return res, err
+	})
+	if res != nil && res.Body != nil {
+		defer func() {
+			if bodyCloseErr := res.Body.Close(); bodyCloseErr != nil {
+				logger.AppLog.Errorf("CreateSubscription response body cannot close: %+v", bodyCloseErr)
+			}
+		}()
+	}
+	if res == nil {
+		if err == nil {
+			err = fmt.Errorf("no response from server")
+		}
+		return "", nil, err
+	}
+	if res.StatusCode != http.StatusCreated {
+		return "", nil, fmt.Errorf("unexpected status code returned by the NRF %d", res.StatusCode)
+	}
+	// The subscription is created even if its representation cannot be decoded, its ID is also in the Location
+	if err == nil && receivedSubscriptionData.GetSubscriptionId() != "" {
+		return receivedSubscriptionData.GetSubscriptionId(), receivedSubscriptionData.ValidityTime, nil
+	}
+	resourceUri := res.Header.Get("Location")
+	if resourceUri == "" {
+		return "", nil, fmt.Errorf("no subscription ID returned by the NRF: %+v", err)
+	}
+	return resourceUri[strings.LastIndex(resourceUri, "/")+1:], nil, nil
+}
+
+// SendRemoveSubscription removes the subscription to the status of the AMFs at the NRF
+var SendRemoveSubscription = func(subscriptionId string) error {
+	logger.ConsumerLog.Debugln("send Remove Subscription")
+
+	res, err := sendToNrf(func(nrfUri string) (*http.Response, error) {
+		client := newNrfManagementClient(nrfUri)
+		apiRemoveSubscriptionRequest := client.SubscriptionIDDocumentAPI.RemoveSubscription(nrfRequestContext(models.SERVICENAME_NNRF_NFM), subscriptionId)
+		return client.SubscriptionIDDocumentAPI.RemoveSubscriptionExecute(apiRemoveSubscriptionRequest)
+	})
 	if err != nil {
 		return err
 	}
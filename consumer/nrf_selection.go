@@ -0,0 +1,170 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Consumer
+ *
+ * Selection of the NRF among the configured NRFs
+ */
+
+package consumer
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	nssfContext "github.com/omec-project/nssf/context"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/openapi/v2/Nnrf_NFDiscovery"
+	"github.com/omec-project/openapi/v2/Nnrf_NFManagement"
+)
+
+type nrfHealth struct {
+	uri string
+	// The NRF is not used until then, unless no other NRF is available
+	failedUntil time.Time
+}
+
+// nrfSelector tracks the health of the NRFs, given in the order of their priority
+type nrfSelector struct {
+	mutex      sync.Mutex
+	configured []string
+	nrfs       []nrfHealth
+	active     string
+}
+
+var nrfSelection nrfSelector
+
+// refreshLocked restarts the tracking when the configured NRFs change
+func (s *nrfSelector) refreshLocked(uris []string) {
+	if slices.Equal(s.configured, uris) {
+		return
+	}
+	s.configured = slices.Clone(uris)
+	s.nrfs = make([]nrfHealth, len(uris))
+	for i, uri := range uris {
+		s.nrfs[i] = nrfHealth{uri: uri}
+	}
+}
+
+// selectNrf returns the NRF of the highest priority which has not failed recently. If all have, the NRF
+// which is available again first is retried.
+func (s *nrfSelector) selectNrf(uris []string, now time.Time) string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.refreshLocked(uris)
+	if len(s.nrfs) == 0 {
+		return ""
+	}
+	selected := -1
+	for i, nrf := range s.nrfs {
+		if !now.Before(nrf.failedUntil) {
+			selected = i
+			break
+		}
+	}
+	if selected == -1 {
+		selected = 0
+		for i, nrf := range s.nrfs {
+			if nrf.failedUntil.Before(s.nrfs[selected].failedUntil) {
+				selected = i
+			}
+		}
+	}
+	if uri := s.nrfs[selected].uri; uri != s.active {
+		if s.active != "" {
+			logger.ConsumerLog.Warnf("NRF %s is used instead of NRF %s", uri, s.active)
+		}
+		s.active = uri
+	}
+	return s.active
+}
+
+// reportResult records whether the NRF answered the request
+func (s *nrfSelector) reportResult(uri string, failed bool, now time.Time, retryInterval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i := range s.nrfs {
+		if s.nrfs[i].uri != uri {
+			continue
+		}
+		if !failed {
+			s.nrfs[i].failedUntil = time.Time{}
+		} else {
+			s.nrfs[i].failedUntil = now.Add(retryInterval)
+			logger.ConsumerLog.Warnf("NRF %s failed, it is not used for %s", uri, retryInterval)
+		}
+		return
+	}
+}
+
+// NrfUri returns the URI of the NRF the next request is sent to
+func NrfUri() string {
+	return nrfSelection.selectNrf(nssfContext.NSSF_Self().NrfUriList, time.Now())
+}
+
+// nrfFailed reports whether the NRF did not answer, or could not handle the request
+func nrfFailed(res *http.Response) bool {
+	return res == nil || res.StatusCode >= http.StatusInternalServerError
+}
+
+func newNrfManagementClient(nrfUri string) *Nnrf_NFManagement.APIClient {
+	configuration := Nnrf_NFManagement.NewConfiguration()
+	configuration.HTTPClient = SbiClient()
+	serverConfig := &configuration.Servers[0]
+	if apiRootVar, exists := serverConfig.Variables["apiRoot"]; exists {
+		apiRootVar.DefaultValue = nrfUri
+		serverConfig.Variables["apiRoot"] = apiRootVar
+	}
+	return Nnrf_NFManagement.NewAPIClient(configuration)
+}
+
+func newNrfDiscoveryClient(nrfUri string) *Nnrf_NFDiscovery.APIClient {
+	configuration := Nnrf_NFDiscovery.NewConfiguration()
+	configuration.HTTPClient = SbiClient()
+	serverConfig := &configuration.Servers[0]
+	if apiRootVar, exists := serverConfig.Variables["apiRoot"]; exists {
+		apiRootVar.DefaultValue = nrfUri
+		serverConfig.Variables["apiRoot"] = apiRootVar
+	}
+	return Nnrf_NFDiscovery.NewAPIClient(configuration)
+}
+
+// sendToNrf sends the request to the selected NRF. If the NRF fails, the request is sent to the
+// next NRF, until an NRF answers or all NRFs have been tried.
+func sendToNrf(send func(nrfUri string) (*http.Response, error)) (*http.Response, error) {
+	retryInterval := secondsOrDefault(sbiClientConfig().NrfRetryInterval, defaultNrfRetryInterval)
+	var tried []string
+	var lastErr error
+	for {
+		nrfUri := NrfUri()
+		if nrfUri == "" {
+			return nil, fmt.Errorf("no NRF configured")
+		}
+		if slices.Contains(tried, nrfUri) {
+			return nil, fmt.Errorf("no NRF available, tried %v: %w", tried, lastErr)
+		}
+		tried = append(tried, nrfUri)
+		res, err := send(nrfUri)
+		failed := nrfFailed(res)
+		nrfSelection.reportResult(nrfUri, failed, time.Now(), retryInterval)
+		if !failed {
+			return res, err
+		}
+		if res != nil && res.Body != nil {
+			if bodyCloseErr := res.Body.Close(); bodyCloseErr != nil {
+				logger.ConsumerLog.Errorf("NRF response body cannot close: %+v", bodyCloseErr)
+			}
+		}
+		if err == nil && res == nil {
+			err = fmt.Errorf("no response from server")
+		} else if err == nil {
+			err = fmt.Errorf("unexpected status code returned by the NRF %d", res.StatusCode)
+		}
+		logger.ConsumerLog.Warnf("request to NRF %s failed: %+v", nrfUri, err)
+		lastErr = err
+	}
+}
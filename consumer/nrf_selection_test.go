@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	nssfContext "github.com/omec-project/nssf/context"
+)
+
+func TestSelectNrfFollowsPriorityAndHealth(t *testing.T) {
+	selector := &nrfSelector{}
+	uris := []string{"http://nrf-1", "http://nrf-2", "http://nrf-3"}
+	now := time.Now()
+	retryInterval := 30 * time.Second
+
+	if nrfUri := selector.selectNrf(uris, now); nrfUri != "http://nrf-1" {
+		t.Fatalf("expected the NRF of the highest priority, got %s", nrfUri)
+	}
+
+	selector.reportResult("http://nrf-1", true, now, retryInterval)
+	if nrfUri := selector.selectNrf(uris, now); nrfUri != "http://nrf-2" {
+		t.Fatalf("expected a failover to the next NRF, got %s", nrfUri)
+	}
+
+	selector.reportResult("http://nrf-2", true, now.Add(time.Second), retryInterval)
+	selector.reportResult("http://nrf-3", true, now.Add(2*time.Second), retryInterval)
+	if nrfUri := selector.selectNrf(uris, now.Add(3*time.Second)); nrfUri != "http://nrf-1" {
+		t.Fatalf("expected the NRF which failed first to be retried when all failed, got %s", nrfUri)
+	}
+
+	selector.reportResult("http://nrf-2", false, now.Add(4*time.Second), retryInterval)
+	if nrfUri := selector.selectNrf(uris, now.Add(4*time.Second)); nrfUri != "http://nrf-2" {
+		t.Fatalf("expected the NRF which answered to be used, got %s", nrfUri)
+	}
+
+	if nrfUri := selector.selectNrf(uris, now.Add(retryInterval)); nrfUri != "http://nrf-1" {
+		t.Fatalf("expected the NRF of the highest priority to be used again after the retry interval, got %s", nrfUri)
+	}
+}
+
+func newH2cServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSendToNrfFailsOverToNextNrf(t *testing.T) {
+	var primaryRequests, secondaryRequests atomic.Int32
+	primary := newH2cServer(t, func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	secondary := newH2cServer(t, func(w http.ResponseWriter, r *http.Request) {
+		secondaryRequests.Add(1)
+		if r.ProtoMajor != 2 {
+			t.Errorf("expected an HTTP/2 request, got %s", r.Proto)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	nssfSelf := nssfContext.NSSF_Self()
+	originalNrfUriList := nssfSelf.NrfUriList
+	t.Cleanup(func() { nssfSelf.NrfUriList = originalNrfUriList })
+	nssfSelf.NrfUriList = []string{primary.URL, secondary.URL}
+
+	if err := SendDeregisterNFInstance(); err != nil {
+		t.Fatalf("expected the request to be answered by the secondary NRF, got %+v", err)
+	}
+	if err := SendRemoveSubscription("1"); err != nil {
+		t.Fatalf("expected the request to be answered by the secondary NRF, got %+v", err)
+	}
+	if primaryRequests.Load() != 1 || secondaryRequests.Load() != 2 {
+		t.Fatalf("expected the failed NRF not to be used again, primary got %d and secondary %d requests",
+			primaryRequests.Load(), secondaryRequests.Load())
+	}
+	if nrfUri := NrfUri(); nrfUri != secondary.URL {
+		t.Fatalf("expected the secondary NRF to be used, got %s", nrfUri)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Consumer
+ *
+ * HTTP/2 client towards the NRF and the NF service consumers
+ */
+
+package consumer
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/omec-project/nssf/factory"
+)
+
+// Default values of the sbiClient configuration
+const (
+	defaultRequestTimeout   = 10 * time.Second
+	defaultConnectTimeout   = 3 * time.Second
+	defaultIdleConnTimeout  = 90 * time.Second
+	defaultNrfRetryInterval = 30 * time.Second
+)
+
+var (
+	sbiClient     *http.Client
+	sbiClientOnce sync.Once
+)
+
+// SbiClient returns the HTTP/2 client of the NSSF, built once from the configuration so that all
+// requests share its connections
+func SbiClient() *http.Client {
+	sbiClientOnce.Do(func() {
+		sbiClient = newSbiClient(sbiClientConfig(), scpConfig())
+	})
+	return sbiClient
+}
+
+func sbiClientConfig() factory.SbiClient {
+	if configuration := factory.NssfConfig.Configuration; configuration != nil && configuration.SbiClient != nil {
+		return *configuration.SbiClient
+	}
+	return factory.SbiClient{}
+}
+
+func scpConfig() *factory.ScpConfig {
+	if configuration := factory.NssfConfig.Configuration; configuration != nil {
+		return configuration.Scp
+	}
+	return nil
+}
+
+func secondsOrDefault(seconds int, defaultValue time.Duration) time.Duration {
+	if seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultValue
+}
+
+// newSbiClient builds an HTTP/2 only client, TS 29.500 clause 5.2.2. Without TLS, HTTP/2 is used with
+// prior knowledge. Requests are sent through the SCP, if one is configured.
+func newSbiClient(config factory.SbiClient, scp *factory.ScpConfig) *http.Client {
+	connectTimeout := secondsOrDefault(config.ConnectTimeout, defaultConnectTimeout)
+	protocols := new(http.Protocols)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         (&net.Dialer{Timeout: connectTimeout}).DialContext,
+		TLSHandshakeTimeout: connectTimeout,
+		IdleConnTimeout:     secondsOrDefault(config.IdleConnTimeout, defaultIdleConnTimeout),
+		Protocols:           protocols,
+	}
+	return &http.Client{
+		Transport: newScpRoundTripper(scp, transport),
+		Timeout:   secondsOrDefault(config.RequestTimeout, defaultRequestTimeout),
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Consumer
+ *
+ * Indirect communication through an SCP, TS 29.500 clause 6.10
+ */
+
+package consumer
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// SbiRouting tells the SCP how to route a request in indirect communication with delegated discovery
+type SbiRouting struct {
+	TargetNfType models.NFType
+	ServiceNames []models.ServiceName
+	// Binding indication of the target, e.g. the one received when a subscription was created
+	RoutingBinding string
+	// Type of the notification or callback, if the request is one
+	Callback string
+}
+
+type sbiRoutingKey struct{}
+
+// WithSbiRouting returns a context for a request which is routed as given through the SCP
+func WithSbiRouting(ctx context.Context, routing SbiRouting) context.Context {
+	return context.WithValue(ctx, sbiRoutingKey{}, routing)
+}
+
+func sbiRoutingOf(ctx context.Context) SbiRouting {
+	routing, _ := ctx.Value(sbiRoutingKey{}).(SbiRouting)
+	return routing
+}
+
+// nrfRequestContext returns the context of a request to a service of the NRF
+func nrfRequestContext(serviceName models.ServiceName) context.Context {
+	return nrfRequestContextOf(context.Background(), serviceName)
+}
+
+// nrfRequestContextOf returns the context of a request to a service of the NRF, which is cancelled with ctx
+func nrfRequestContextOf(ctx context.Context, serviceName models.ServiceName) context.Context {
+	return WithSbiRouting(ctx, SbiRouting{
+		TargetNfType: models.NFTYPE_NRF,
+		ServiceNames: []models.ServiceName{serviceName},
+	})
+}
+
+// scpRoundTripper sends the requests to the SCP instead of their target, whose apiRoot is given in the
+// 3gpp-Sbi-Target-apiRoot header. With delegated discovery, the SCP selects the target itself from the
+// discovery parameters and the binding of the target, which are given instead.
+type scpRoundTripper struct {
+	scp  *url.URL
+	mode string
+	next http.RoundTripper
+}
+
+// newScpRoundTripper returns the round tripper of the configured SCP, or the given one if no SCP is configured
+func newScpRoundTripper(scp *factory.ScpConfig, next http.RoundTripper) http.RoundTripper {
+	if scp == nil {
+		return next
+	}
+	scpUrl, err := url.Parse(scp.Uri)
+	if err != nil {
+		logger.ConsumerLog.Errorf("SCP uri %s cannot be parsed, requests are sent directly: %+v", scp.Uri, err)
+		return next
+	}
+	mode := scp.Mode
+	if mode == "" {
+		mode = factory.ScpModeIndirect
+	}
+	return &scpRoundTripper{scp: scpUrl, mode: mode, next: next}
+}
+
+func (t *scpRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	routing := sbiRoutingOf(req.Context())
+	scpReq := req.Clone(req.Context())
+	if t.mode != factory.ScpModeDelegatedDiscovery {
+		scpReq.Header.Set(sbi.HeaderTargetApiRoot, req.URL.Scheme+"://"+req.URL.Host)
+	} else {
+		if routing.TargetNfType != "" {
+			scpReq.Header.Set(sbi.HeaderDiscoveryTargetNfType, string(routing.TargetNfType))
+			scpReq.Header.Set(sbi.HeaderDiscoveryRequesterNfType, string(models.NFTYPE_NSSF))
+		}
+		if len(routing.ServiceNames) != 0 {
+			serviceNames := make([]string, len(routing.ServiceNames))
+			for i, serviceName := range routing.ServiceNames {
+				serviceNames[i] = string(serviceName)
+			}
+			scpReq.Header.Set(sbi.HeaderDiscoveryServiceNames, strings.Join(serviceNames, ","))
+		}
+		if routing.RoutingBinding != "" {
+			scpReq.Header.Set(sbi.HeaderRoutingBinding, routing.RoutingBinding)
+		}
+	}
+	if routing.Callback != "" {
+		scpReq.Header.Set(sbi.HeaderCallback, routing.Callback)
+	}
+	scpReq.URL.Scheme = t.scp.Scheme
+	scpReq.URL.Host = t.scp.Host
+	scpReq.URL.Path = strings.TrimSuffix(t.scp.Path, "/") + req.URL.Path
+	scpReq.URL.RawPath = ""
+	scpReq.Host = ""
+	return t.next.RoundTrip(scpReq)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+func TestScpRoundTripperRoutesThroughScp(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	scp := newH2cServer(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		mode             string
+		discoveryHeaders bool
+	}{
+		{mode: factory.ScpModeIndirect},
+		{mode: factory.ScpModeDelegatedDiscovery, discoveryHeaders: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			client := newSbiClient(factory.SbiClient{}, &factory.ScpConfig{Uri: scp.URL + "/scp/", Mode: tt.mode})
+			ctx := WithSbiRouting(context.Background(), SbiRouting{
+				TargetNfType:   models.NFTYPE_AMF,
+				RoutingBinding: "bl=nfinstance; nfinst=amf-1",
+				Callback:       sbi.CallbackNssaiAvailabilityNotification,
+			})
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://amf-1:29518/notify/1", http.NoBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("expected the request to be sent to the SCP, got %+v", err)
+			}
+			if err = res.Body.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			scpReq := <-received
+			targetApiRoot := "http://amf-1:29518"
+			if tt.discoveryHeaders {
+				// The SCP selects the target with delegated discovery
+				targetApiRoot = ""
+			}
+			if scpReq.URL.Path != "/scp/notify/1" || scpReq.Header.Get(sbi.HeaderTargetApiRoot) != targetApiRoot {
+				t.Fatalf("expected the request to the target to be sent to the SCP, got %s with target %s",
+					scpReq.URL.Path, scpReq.Header.Get(sbi.HeaderTargetApiRoot))
+			}
+			if scpReq.Header.Get(sbi.HeaderCallback) != sbi.CallbackNssaiAvailabilityNotification {
+				t.Errorf("expected the callback type, got %q", scpReq.Header.Get(sbi.HeaderCallback))
+			}
+			discoveryHeaders := scpReq.Header.Get(sbi.HeaderDiscoveryTargetNfType) == string(models.NFTYPE_AMF) &&
+				scpReq.Header.Get(sbi.HeaderRoutingBinding) == "bl=nfinstance; nfinst=amf-1"
+			if discoveryHeaders != tt.discoveryHeaders {
+				t.Errorf("expected discovery headers %v, got %+v", tt.discoveryHeaders, scpReq.Header)
+			}
+		})
+	}
+}
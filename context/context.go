@@ -13,8 +13,10 @@
 package context
 
 import (
+	"cmp"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -36,6 +38,12 @@ const (
 	nssaiAvailabilityAPIVersionInURI = "v1"
 )
 
+// Callbacks of the NSSF, which are not part of its NF services
+const (
+	CallbackUriPrefix  = "/nnssf-callback/v1"
+	NfStatusNotifyPath = "/nf-status-notify"
+)
+
 // Initialize NSSF context with default value
 func init() {
 	nssfContext.NfId = uuid.New().String()
@@ -53,6 +61,7 @@ func init() {
 	nssfContext.NfService = initNfService(serviceName)
 
 	nssfContext.NrfUri = fmt.Sprintf("%s://%s:%d", models.URISCHEME_HTTPS, nssfContext.RegisterIPv4, port)
+	nssfContext.NrfUriList = []string{nssfContext.NrfUri}
 }
 
 type NSSFContext struct {
@@ -64,8 +73,11 @@ type NSSFContext struct {
 	Key          string
 	PEM          string
 	NfService    map[models.ServiceName]models.NFService
-	NrfUri       string
-	SBIPort      int
+	// NRF of the highest priority
+	NrfUri string
+	// NRFs in the order of their priority
+	NrfUriList []string
+	SBIPort    int
 }
 
 // Initialize NSSF context with configuration factory
@@ -105,12 +117,35 @@ func InitNssfContext() {
 	// NF service API versions must track the served SBI routes, not the config schema version.
 	nssfContext.NfService = initNfService(nssfConfig.Configuration.ServiceNameList)
 
-	if nssfConfig.Configuration.NrfUri != "" {
-		nssfContext.NrfUri = nssfConfig.Configuration.NrfUri
-	} else {
+	switch {
+	case len(nssfConfig.Configuration.NrfList) != 0:
+		if nssfConfig.Configuration.NrfUri != "" {
+			logger.InitLog.Warnln("nrfList is configured, nrfUri is ignored")
+		}
+		nssfContext.NrfUriList = nrfUrisByPriority(nssfConfig.Configuration.NrfList)
+	case nssfConfig.Configuration.NrfUri != "":
+		nssfContext.NrfUriList = []string{nssfConfig.Configuration.NrfUri}
+	default:
 		logger.InitLog.Warnln("NRF Uri is empty. Using localhost as NRF IPv4 address")
-		nssfContext.NrfUri = fmt.Sprintf("%s://%s:%d", nssfContext.UriScheme, "127.0.0.1", port)
+		nssfContext.NrfUriList = []string{fmt.Sprintf("%s://%s:%d", nssfContext.UriScheme, "127.0.0.1", port)}
 	}
+	nssfContext.NrfUri = nssfContext.NrfUriList[0]
+}
+
+// nrfUrisByPriority returns the URIs of the NRFs, lowest priority value first. NRFs with the same
+// priority keep the order of the configuration
+func nrfUrisByPriority(nrfList []factory.NrfConfig) []string {
+	sorted := slices.Clone(nrfList)
+	slices.SortStableFunc(sorted, func(a, b factory.NrfConfig) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+	uris := make([]string, 0, len(sorted))
+	for _, nrf := range sorted {
+		if !slices.Contains(uris, nrf.Uri) {
+			uris = append(uris, nrf.Uri)
+		}
+	}
+	return uris
 }
 
 func initNfService(serviceName []models.ServiceName) (
@@ -157,6 +192,11 @@ func GetIpv4Uri() string {
 	return fmt.Sprintf("%s://%s:%d", nssfContext.UriScheme, nssfContext.RegisterIPv4, nssfContext.SBIPort)
 }
 
+// GetNfStatusNotifyUri returns the URI at which the NRF notifies the NSSF about the status of AMFs
+func GetNfStatusNotifyUri() string {
+	return GetIpv4Uri() + CallbackUriPrefix + NfStatusNotifyPath
+}
+
 func NSSF_Self() *NSSFContext {
 	return &nssfContext
 }
@@ -11,6 +11,10 @@
 package factory
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/omec-project/openapi/v2/models"
 	utilLogger "github.com/omec-project/util/logger"
 )
@@ -49,6 +53,158 @@ type Configuration struct {
 	AmfList                  []AmfConfig             `yaml:"amfList"`
 	TaList                   []TaConfig              `yaml:"taList"`
 	MappingListFromPlmn      []MappingFromPlmnConfig `yaml:"mappingListFromPlmn"`
+	NsagList                 []NsagConfig            `yaml:"nsagList,omitempty"`
+	AccessTypeWithoutTai     string                  `yaml:"accessTypeWithoutTai,omitempty"`
+	AllowedNssai             *AllowedNssaiConfig     `yaml:"allowedNssai,omitempty"`
+	NssaiAvailabilityPolicy  []NssaiAvailabilityRule `yaml:"nssaiAvailabilityPolicy,omitempty"`
+	Admin                    *Admin                  `yaml:"admin,omitempty"`
+	// Seconds after which the NSSAI availability of an AMF which has not contacted the NSSF is suspended,
+	// while the NSSF is not subscribed to the status of AMFs at the NRF. The fallback is disabled if 0
+	AmfAvailabilityTtl int `yaml:"amfAvailabilityTtl,omitempty"`
+	// AMF Sets of the AMFs whose NSSAI availability was removed, e.g. because they deregistered. The NSSAI
+	// availability of the configuration file no longer applies to them, as their members provided their own
+	RemovedAmfSetIds []string `yaml:"-"`
+	// Supported NSSAI per PLMN as last polled from the webconsole. SupportedNssaiInPlmnList is this list,
+	// overridden per PLMN by the entries provisioned through the admin API
+	PolledSupportedNssaiInPlmnList SupportedNssaiInPlmn `yaml:"-"`
+	// Supported NSSAI per PLMN provisioned through the admin API, which polling does not overwrite
+	ProvisionedSupportedNssaiInPlmnList []SupportedNssaiInPlmnEntry `yaml:"-"`
+	// PLMNs of SupportedNssaiInPlmnList, as last handed over for the registration of the NSSF at the NRF
+	PlmnList []models.PlmnId `yaml:"-"`
+	// NRFs of the NSSF, used instead of `nrfUri` when given
+	NrfList []NrfConfig `yaml:"nrfList,omitempty"`
+	// HTTP client of the NSSF towards the NRF and the NF service consumers
+	SbiClient *SbiClient `yaml:"sbiClient,omitempty"`
+	// SCP through which the NSSF sends its requests and notifications, TS 29.500 clause 6.10
+	Scp *ScpConfig `yaml:"scp,omitempty"`
+	// Load and overload control of the SBI, TS 29.500 clauses 6.3 and 6.4. Disabled if not configured
+	LoadControl *LoadControlConfig `yaml:"loadControl,omitempty"`
+	// Limits of the request rates of classes of NF service consumers. Requests are not limited if not configured
+	RateLimits []RateLimitConfig `yaml:"rateLimits,omitempty"`
+	// Limits of the size of the SBI requests. Default values apply if not configured
+	RequestLimits *RequestLimitsConfig `yaml:"requestLimits,omitempty"`
+}
+
+// RequestLimitsConfig sets the maximum size of the SBI requests and of the lists they contain. Default values apply if 0
+type RequestLimitsConfig struct {
+	// Length in bytes of the request URI, 8192 by default
+	MaxUriLength int `yaml:"maxUriLength,omitempty"`
+	// Number of query parameters, 256 by default
+	MaxQueryParameters int `yaml:"maxQueryParameters,omitempty"`
+	// Size in bytes of the request body, 1 MiB by default
+	MaxBodySize int64 `yaml:"maxBodySize,omitempty"`
+	// S-NSSAIs of a list, e.g. the requested NSSAI or the S-NSSAIs supported in a TA, 64 by default
+	MaxSnssaiListSize int `yaml:"maxSnssaiListSize,omitempty"`
+	// TAs of a list, e.g. the TAs of the NSSAI availability of an AMF or of a subscription, 1024 by default
+	MaxTaiListSize int `yaml:"maxTaiListSize,omitempty"`
+	// Operations of a JSON patch, 256 by default
+	MaxPatchItems int `yaml:"maxPatchItems,omitempty"`
+}
+
+// RateLimitConfig is the token-bucket limit of a class of NF service consumers. A consumer belongs to the
+// first class it matches, and has its own bucket, keyed by its NF type and NF instance ID
+type RateLimitConfig struct {
+	// Name of the class, in the logs and metrics
+	Name string `yaml:"name"`
+	// NF type of the consumers of the class, any NF type if empty
+	NfType models.NFType `yaml:"nfType,omitempty"`
+	// NF instance IDs of the consumers of the class, any NF instance if empty
+	NfIdList []string `yaml:"nfIdList,omitempty"`
+	// Requests per second each consumer of the class may send
+	Rate float64 `yaml:"rate"`
+	// Requests each consumer of the class may send at once, the rate rounded up by default
+	Burst int `yaml:"burst,omitempty"`
+}
+
+// LoadControlConfig sets the load of the NSSF and the thresholds of the overload control. Loads are in
+// % of the capacity, and times in seconds. Default values apply if 0
+type LoadControlConfig struct {
+	// Requests per second the NSSF handles at a load of 100%
+	Capacity int `yaml:"capacity"`
+	// Load from which the NSSF signals an overload, and rejects part of the requests, 80 by default
+	OverloadThreshold int `yaml:"overloadThreshold,omitempty"`
+	// Load from which the NSSF rejects all requests, 100 by default
+	RejectThreshold int `yaml:"rejectThreshold,omitempty"`
+	// Retry-After of the rejected requests, 1 by default
+	RetryAfter int `yaml:"retryAfter,omitempty"`
+	// Period of validity of the overload signalling, 10 by default
+	OverloadValidity int `yaml:"overloadValidity,omitempty"`
+}
+
+// Communication models through an SCP, TS 23.501 clause 7.1.1
+const (
+	// Model C, indirect communication without delegated discovery
+	ScpModeIndirect = "C"
+	// Model D, indirect communication with delegated discovery
+	ScpModeDelegatedDiscovery = "D"
+)
+
+// ScpConfig is the SCP of the indirect communication. The NSSF communicates directly if no SCP is configured
+type ScpConfig struct {
+	// apiRoot of the SCP
+	Uri string `yaml:"uri"`
+	// ScpModeIndirect or ScpModeDelegatedDiscovery, ScpModeIndirect by default
+	Mode string `yaml:"mode,omitempty"`
+}
+
+// NrfConfig is an NRF at which the NSSF may register. The NSSF uses the healthy NRF with the lowest
+// priority value, and NRFs with the same priority in the order they are configured.
+type NrfConfig struct {
+	Uri      string `yaml:"uri"`
+	Priority int    `yaml:"priority,omitempty"`
+}
+
+// SbiClient configures the timeouts of the HTTP/2 client, in seconds. Default values apply if 0
+type SbiClient struct {
+	// Time limit of a request, including reading the response body
+	RequestTimeout int `yaml:"requestTimeout,omitempty"`
+	// Time limit to establish a connection, including the TLS handshake
+	ConnectTimeout int `yaml:"connectTimeout,omitempty"`
+	// Time after which an idle connection is closed
+	IdleConnTimeout int `yaml:"idleConnTimeout,omitempty"`
+	// Time during which an NRF which failed is not used, unless no other NRF is available
+	NrfRetryInterval int `yaml:"nrfRetryInterval,omitempty"`
+}
+
+// Policies for the Access Types of the Allowed NSSAI when the consumer provides no TAI,
+// e.g. for UEs registering through an N3IWF. The default is 3GPP Access only.
+const (
+	AccessTypeWithoutTai3gpp    = "3GPP_ACCESS"
+	AccessTypeWithoutTaiNon3gpp = "NON_3GPP_ACCESS"
+	AccessTypeWithoutTaiAll     = "ALL"
+)
+
+// Maximum number of S-NSSAIs in the Allowed NSSAI of an Access Type, TS 24.501 clause 9.11.3.37
+const MAX_ALLOWED_SNSSAI_NUM = 8
+
+// AllowedNssaiConfig controls which S-NSSAIs are kept when more S-NSSAIs could be allowed than
+// the Allowed NSSAI may hold
+type AllowedNssaiConfig struct {
+	// Maximum number of S-NSSAIs in the Allowed NSSAI of an Access Type, MAX_ALLOWED_SNSSAI_NUM by default
+	Limit int `yaml:"limit,omitempty"`
+	// Priorities of the S-NSSAIs of the serving PLMN. S-NSSAIs without priority come after the others.
+	PriorityList []SnssaiPriorityConfig `yaml:"priorityList,omitempty"`
+}
+
+// SnssaiPriorityConfig is the priority of an S-NSSAI for the Allowed NSSAI, lower values take precedence
+type SnssaiPriorityConfig struct {
+	Snssai   models.Snssai `yaml:"snssai"`
+	Priority int32         `yaml:"priority"`
+}
+
+// NssaiAvailabilityRule permits AMFs to provide the availability of S-NSSAIs in TAs through the
+// NSSAIAvailability service. When rules are configured, the availability of an S-NSSAI in a TA is only
+// authorized if a rule applies to the AMF, the TA and the S-NSSAI.
+type NssaiAvailabilityRule struct {
+	// AMFs the rule applies to, by NF instance ID or by AMF Set, with the AMFs of the set listed in
+	// `amfSetList`. The rule applies to all AMFs if both are empty
+	AmfIdList    []string `yaml:"amfIdList,omitempty"`
+	AmfSetIdList []string `yaml:"amfSetIdList,omitempty"`
+	// TAs the rule applies to. The rule applies to all TAs if both are empty
+	TaiList      []models.Tai      `yaml:"taiList,omitempty"`
+	TaiRangeList []models.TaiRange `yaml:"taiRangeList,omitempty"`
+	// S-NSSAIs the rule applies to. The rule applies to all S-NSSAIs if empty
+	SnssaiList []models.Snssai `yaml:"snssaiList,omitempty"`
 }
 
 type Sbi struct {
@@ -66,16 +222,80 @@ type TLS struct {
 	Key string `yaml:"key,omitempty"`
 }
 
+// Admin configures the operator management API, which is served on its own listener
+// and is separate from the 3GPP SBI routes
+type Admin struct {
+	Enable      bool   `yaml:"enable"`
+	BindingIPv4 string `yaml:"bindingIPv4,omitempty"`
+	Port        int    `yaml:"port"`
+	TLS         *TLS   `yaml:"tls,omitempty"`
+	// Bearer token required in the `Authorization` header of every admin request.
+	// It may be overridden with the NSSF_ADMIN_TOKEN environment variable.
+	Token string `yaml:"token,omitempty"`
+	// If set, provisioned tables are written to this file after every change and
+	// loaded from it on start-up
+	PersistFile string `yaml:"persistFile,omitempty"`
+}
+
 type AmfConfig struct {
-	NfId                           string                                  `yaml:"nfId"`
-	SupportedNssaiAvailabilityData []models.SupportedNssaiAvailabilityData `yaml:"supportedNssaiAvailabilityData"`
+	NfId                           string                                  `yaml:"nfId" json:"nfId"`
+	SupportedNssaiAvailabilityData []models.SupportedNssaiAvailabilityData `yaml:"supportedNssaiAvailabilityData" json:"supportedNssaiAvailabilityData"`
+	// AMF Set for which the AMF provided its NSSAI availability through the NSSAIAvailability service
+	AmfSetId string `yaml:"amfSetId,omitempty" json:"amfSetId,omitempty"`
+	// Version of the NSSAI availability resource of the AMF, changed by every update through the
+	// NSSAIAvailability service. It is 0 for AMFs of the configuration file
+	Version uint64 `yaml:"-" json:"-"`
+	// Whether the AMF is unavailable, as reported by the NRF or because it has not contacted the NSSF for
+	// too long. The NSSAI availability of a suspended AMF is kept, but the AMF is not selected
+	Suspended bool `yaml:"-" json:"suspended,omitempty"`
+	// Why the AMF is suspended, AmfSuspendedByNrf or AmfSuspendedByTtl
+	SuspensionCause string `yaml:"-" json:"suspensionCause,omitempty"`
 }
 
+// Causes of the suspension of the NSSAI availability of an AMF
+const (
+	// The NRF reported that the AMF is suspended or undiscoverable, until it reports the AMF registered again
+	AmfSuspendedByNrf = "NRF_STATUS"
+	// The AMF has not contacted the NSSF for `amfAvailabilityTtl`, until it does
+	AmfSuspendedByTtl = "TTL_EXPIRED"
+)
+
+// TaConfig is the configuration of a TA, of the TAs of a TAI range or of all TAs of a PLMN. Exactly one
+// of `tai`, `taiRange` and `plmnId` is set. The most specific entry applies to a TAI: its TA, then the
+// first TAI range including it, then its PLMN.
 type TaConfig struct {
-	Tai                  *models.Tai               `yaml:"tai"`
-	AccessType           *models.AccessType        `yaml:"accessType"`
-	SupportedSnssaiList  []models.Snssai           `yaml:"supportedSnssaiList"`
-	RestrictedSnssaiList []models.RestrictedSnssai `yaml:"restrictedSnssaiList,omitempty"`
+	Tai                  *models.Tai               `yaml:"tai,omitempty" json:"tai,omitempty"`
+	TaiRange             *models.TaiRange          `yaml:"taiRange,omitempty" json:"taiRange,omitempty"`
+	PlmnId               *models.PlmnId            `yaml:"plmnId,omitempty" json:"plmnId,omitempty"`
+	AccessType           *models.AccessType        `yaml:"accessType,omitempty" json:"accessType,omitempty"`
+	SupportedSnssaiList  []models.Snssai           `yaml:"supportedSnssaiList,omitempty" json:"supportedSnssaiList,omitempty"`
+	RestrictedSnssaiList []models.RestrictedSnssai `yaml:"restrictedSnssaiList,omitempty" json:"restrictedSnssaiList,omitempty"`
+	// Access Types serving the TA, each with its own S-NSSAIs. It is used instead of `accessType`,
+	// `supportedSnssaiList` and `restrictedSnssaiList` for a TA served by more than one Access Type.
+	AccessTypeList []TaAccessTypeConfig `yaml:"accessTypeList,omitempty" json:"accessTypeList,omitempty"`
+}
+
+// TaAccessTypeConfig is the S-NSSAIs of a TA in one Access Type
+type TaAccessTypeConfig struct {
+	AccessType           models.AccessType         `yaml:"accessType" json:"accessType"`
+	SupportedSnssaiList  []models.Snssai           `yaml:"supportedSnssaiList" json:"supportedSnssaiList"`
+	RestrictedSnssaiList []models.RestrictedSnssai `yaml:"restrictedSnssaiList,omitempty" json:"restrictedSnssaiList,omitempty"`
+}
+
+// AccessTypeConfigs returns the S-NSSAIs of the TA per Access Type, whichever form the TA is configured in
+func (t TaConfig) AccessTypeConfigs() []TaAccessTypeConfig {
+	if len(t.AccessTypeList) != 0 {
+		return t.AccessTypeList
+	}
+	accessType := models.ACCESSTYPE__3_GPP_ACCESS
+	if t.AccessType != nil {
+		accessType = *t.AccessType
+	}
+	return []TaAccessTypeConfig{{
+		AccessType:           accessType,
+		SupportedSnssaiList:  t.SupportedSnssaiList,
+		RestrictedSnssaiList: t.RestrictedSnssaiList,
+	}}
 }
 
 // SnssaiKey is used to avoid using models.Snssai as map key directly due to pointer field issue
@@ -86,27 +306,46 @@ type SnssaiKey struct {
 
 type SupportedNssaiInPlmn map[models.PlmnId]map[SnssaiKey]struct{}
 
+// SupportedNssaiInPlmnEntry is the list form of one SupportedNssaiInPlmn entry,
+// used where the map form cannot be serialised (admin API, persisted tables)
+type SupportedNssaiInPlmnEntry struct {
+	PlmnId     models.PlmnId   `yaml:"plmnId" json:"plmnId"`
+	SNssaiList []models.Snssai `yaml:"sNssaiList" json:"sNssaiList"`
+}
+
 type NsiConfig struct {
-	Snssai             *models.Snssai          `yaml:"snssai"`
-	NsiInformationList []models.NsiInformation `yaml:"nsiInformationList"`
+	Snssai             *models.Snssai          `yaml:"snssai" json:"snssai"`
+	NsiInformationList []models.NsiInformation `yaml:"nsiInformationList" json:"nsiInformationList"`
 }
 
 type AmfSetConfig struct {
-	AmfSetId                       string                                  `yaml:"amfSetId"`
-	AmfList                        []string                                `yaml:"amfList,omitempty"`
-	NrfAmfSet                      string                                  `yaml:"nrfAmfSet,omitempty"`
-	SupportedNssaiAvailabilityData []models.SupportedNssaiAvailabilityData `yaml:"supportedNssaiAvailabilityData"`
+	AmfSetId                       string                                  `yaml:"amfSetId" json:"amfSetId"`
+	AmfList                        []string                                `yaml:"amfList,omitempty" json:"amfList,omitempty"`
+	NrfAmfSet                      string                                  `yaml:"nrfAmfSet,omitempty" json:"nrfAmfSet,omitempty"`
+	SupportedNssaiAvailabilityData []models.SupportedNssaiAvailabilityData `yaml:"supportedNssaiAvailabilityData" json:"supportedNssaiAvailabilityData"`
+}
+
+// NsagConfig is a Network Slice AS Group, TS 23.501 clause 5.15.14
+type NsagConfig struct {
+	NsagId     int32           `yaml:"nsagId" json:"nsagId"`
+	SnssaiList []models.Snssai `yaml:"snssaiList" json:"snssaiList"`
+	// Priority of the NSAG for cell reselection and random access, lower values take precedence
+	Priority *int32 `yaml:"priority,omitempty" json:"priority,omitempty"`
+	// TAs in which the NSAG is valid. The NSAG is valid in all TAs if empty
+	TaiList []models.Tai `yaml:"taiList,omitempty" json:"taiList,omitempty"`
 }
 
 type MappingFromPlmnConfig struct {
-	OperatorName    string                   `yaml:"operatorName,omitempty"`
-	HomePlmnId      *models.PlmnId           `yaml:"homePlmnId"`
-	MappingOfSnssai []models.MappingOfSnssai `yaml:"mappingOfSnssai"`
+	OperatorName    string                   `yaml:"operatorName,omitempty" json:"operatorName,omitempty"`
+	HomePlmnId      *models.PlmnId           `yaml:"homePlmnId" json:"homePlmnId"`
+	MappingOfSnssai []models.MappingOfSnssai `yaml:"mappingOfSnssai" json:"mappingOfSnssai"`
 }
 
 type Subscription struct {
-	SubscriptionData *models.NssfEventSubscriptionCreateData `yaml:"subscriptionData"`
-	SubscriptionId   string                                  `yaml:"subscriptionId"`
+	SubscriptionData *models.NssfEventSubscriptionCreateData `yaml:"subscriptionData" json:"subscriptionData"`
+	SubscriptionId   string                                  `yaml:"subscriptionId" json:"subscriptionId"`
+	// 3gpp-Sbi-Routing-Binding of the notifications, from the binding indication of the subscriber
+	RoutingBinding string `yaml:"routingBinding,omitempty" json:"routingBinding,omitempty"`
 }
 
 // Helper function to convert models.Snssai to SnssaiKey
@@ -117,6 +356,86 @@ func SnssaiToKey(s models.Snssai) SnssaiKey {
 	}
 }
 
+// SnssaiKeyString formats the key as "sst" or "sst-sd", as in TS 29.571 for S-NSSAIs used as map keys
+func SnssaiKeyString(key SnssaiKey) string {
+	if key.Sd == "" {
+		return strconv.Itoa(int(key.Sst))
+	}
+	return fmt.Sprintf("%d-%s", key.Sst, key.Sd)
+}
+
+// ParseSnssaiKeyString is the reverse of SnssaiKeyString
+func ParseSnssaiKeyString(s string) (models.Snssai, error) {
+	sstString, sd, _ := strings.Cut(s, "-")
+	sst, err := strconv.ParseInt(sstString, 10, 32)
+	if err != nil {
+		return models.Snssai{}, fmt.Errorf("invalid S-NSSAI %q: %w", s, err)
+	}
+	snssai := models.NewSnssai(int32(sst))
+	if sd != "" {
+		snssai.SetSd(sd)
+	}
+	return *snssai, nil
+}
+
+// PlmnIdString formats the PLMN ID as "mcc-mnc"
+func PlmnIdString(plmnId models.PlmnId) string {
+	return plmnId.GetMcc() + "-" + plmnId.GetMnc()
+}
+
+// ParsePlmnIdString is the reverse of PlmnIdString
+func ParsePlmnIdString(s string) (models.PlmnId, error) {
+	mcc, mnc, found := strings.Cut(s, "-")
+	if !found || mcc == "" || mnc == "" {
+		return models.PlmnId{}, fmt.Errorf("invalid PLMN ID %q, expected mcc-mnc", s)
+	}
+	return models.PlmnId{Mcc: mcc, Mnc: mnc}, nil
+}
+
+// TaiString formats the TAI as "mcc-mnc-tac"
+func TaiString(tai models.Tai) string {
+	return PlmnIdString(tai.PlmnId) + "-" + tai.GetTac()
+}
+
+// ParseTaiString is the reverse of TaiString
+func ParseTaiString(s string) (models.Tai, error) {
+	idx := strings.LastIndex(s, "-")
+	if idx < 0 {
+		return models.Tai{}, fmt.Errorf("invalid TAI %q, expected mcc-mnc-tac", s)
+	}
+	plmnId, err := ParsePlmnIdString(s[:idx])
+	if err != nil || s[idx+1:] == "" {
+		return models.Tai{}, fmt.Errorf("invalid TAI %q, expected mcc-mnc-tac", s)
+	}
+	return models.Tai{PlmnId: plmnId, Tac: s[idx+1:]}, nil
+}
+
+// TacRangeString formats the TAC range as "start..end", or as its pattern
+func TacRangeString(tacRange models.TacRange) string {
+	if tacRange.Pattern != nil {
+		return tacRange.GetPattern()
+	}
+	return tacRange.GetStart() + ".." + tacRange.GetEnd()
+}
+
+// TaConfigKey identifies a TA list entry as "mcc-mnc-tac" for a TA, "mcc-mnc" for a PLMN,
+// or "mcc-mnc-" followed by its TAC ranges separated by "," for a TAI range
+func TaConfigKey(taConfig TaConfig) string {
+	switch {
+	case taConfig.Tai != nil:
+		return TaiString(*taConfig.Tai)
+	case taConfig.TaiRange != nil:
+		tacRanges := make([]string, 0, len(taConfig.TaiRange.TacRangeList))
+		for _, tacRange := range taConfig.TaiRange.TacRangeList {
+			tacRanges = append(tacRanges, TacRangeString(tacRange))
+		}
+		return PlmnIdString(taConfig.TaiRange.PlmnId) + "-" + strings.Join(tacRanges, ",")
+	case taConfig.PlmnId != nil:
+		return PlmnIdString(*taConfig.PlmnId)
+	}
+	return ""
+}
+
 func (c *Config) GetVersion() string {
 	if c.Info != nil && c.Info.Version != "" {
 		return c.Info.Version
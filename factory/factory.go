@@ -42,13 +42,61 @@ func InitConfigFactory(f string) error {
 		return err
 	}
 
+	if err = loadProvisionedTables(); err != nil {
+		return err
+	}
+	NssfConfig.Configuration.MergeSupportedNssaiInPlmnList()
+	NssfConfig.Configuration.UpdatePlmnList()
+
+	if err = validateAccessTypeWithoutTai(NssfConfig.Configuration.AccessTypeWithoutTai); err != nil {
+		return err
+	}
+
+	if err = validateAllowedNssaiConfig(NssfConfig.Configuration.AllowedNssai); err != nil {
+		return err
+	}
+
+	if err = validateNssaiAvailabilityPolicy(NssfConfig.Configuration.NssaiAvailabilityPolicy); err != nil {
+		return err
+	}
+
+	if NssfConfig.Configuration.AmfAvailabilityTtl < 0 {
+		return fmt.Errorf("amfAvailabilityTtl must not be negative, got %d", NssfConfig.Configuration.AmfAvailabilityTtl)
+	}
+
+	if err = validateNrfList(NssfConfig.Configuration.NrfList); err != nil {
+		return err
+	}
+
+	if err = validateSbiClient(NssfConfig.Configuration.SbiClient); err != nil {
+		return err
+	}
+
+	if err = validateScpConfig(NssfConfig.Configuration.Scp); err != nil {
+		return err
+	}
+
+	if err = validateRateLimits(NssfConfig.Configuration.RateLimits); err != nil {
+		return err
+	}
+
+	if err = validateLoadControlConfig(NssfConfig.Configuration.LoadControl); err != nil {
+		return err
+	}
+
+	if err = validateRequestLimits(NssfConfig.Configuration.RequestLimits); err != nil {
+		return err
+	}
+
 	if NssfConfig.Configuration.WebuiUri == "" {
 		NssfConfig.Configuration.WebuiUri = "http://webui:5001"
 		logger.CfgLog.Infof("webuiUri not set in configuration file. Using %v", NssfConfig.Configuration.WebuiUri)
-		return nil
+	} else if err = validateWebuiUri(NssfConfig.Configuration.WebuiUri); err != nil {
+		return err
 	}
-	err = validateWebuiUri(NssfConfig.Configuration.WebuiUri)
-	return err
+
+	PublishSnapshotLocked()
+	return nil
 }
 
 func CheckConfigVersion() error {
@@ -77,3 +125,145 @@ func validateWebuiUri(uri string) error {
 	}
 	return nil
 }
+
+func validateNrfList(nrfList []NrfConfig) error {
+	for i, nrf := range nrfList {
+		parsedUrl, err := url.ParseRequestURI(nrf.Uri)
+		if err != nil {
+			return fmt.Errorf("nrfList[%d]: %w", i, err)
+		}
+		if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+			return fmt.Errorf("nrfList[%d]: unsupported scheme for uri: %s", i, parsedUrl.Scheme)
+		}
+		if parsedUrl.Hostname() == "" {
+			return fmt.Errorf("nrfList[%d]: missing host in uri", i)
+		}
+	}
+	return nil
+}
+
+func validateScpConfig(scp *ScpConfig) error {
+	if scp == nil {
+		return nil
+	}
+	parsedUrl, err := url.ParseRequestURI(scp.Uri)
+	if err != nil {
+		return fmt.Errorf("scp: %w", err)
+	}
+	if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+		return fmt.Errorf("scp: unsupported scheme for uri: %s", parsedUrl.Scheme)
+	}
+	if parsedUrl.Hostname() == "" {
+		return fmt.Errorf("scp: missing host in uri")
+	}
+	switch scp.Mode {
+	case "", ScpModeIndirect, ScpModeDelegatedDiscovery:
+		return nil
+	default:
+		return fmt.Errorf("scp: unsupported mode %q, must be %q or %q", scp.Mode, ScpModeIndirect, ScpModeDelegatedDiscovery)
+	}
+}
+
+func validateLoadControlConfig(loadControl *LoadControlConfig) error {
+	if loadControl == nil {
+		return nil
+	}
+	if loadControl.Capacity <= 0 {
+		return fmt.Errorf("loadControl capacity must be positive, got %d", loadControl.Capacity)
+	}
+	if loadControl.OverloadThreshold < 0 || loadControl.RejectThreshold < 0 || loadControl.RetryAfter < 0 ||
+		loadControl.OverloadValidity < 0 {
+		return fmt.Errorf("loadControl thresholds and times must not be negative, got %+v", *loadControl)
+	}
+	if loadControl.OverloadThreshold != 0 && loadControl.RejectThreshold != 0 &&
+		loadControl.OverloadThreshold > loadControl.RejectThreshold {
+		return fmt.Errorf("loadControl overloadThreshold %d must not exceed rejectThreshold %d",
+			loadControl.OverloadThreshold, loadControl.RejectThreshold)
+	}
+	return nil
+}
+
+func validateRateLimits(rateLimits []RateLimitConfig) error {
+	names := map[string]bool{}
+	for _, rateLimit := range rateLimits {
+		if rateLimit.Name == "" || names[rateLimit.Name] {
+			return fmt.Errorf("rateLimits: name %q must be unique and not empty", rateLimit.Name)
+		}
+		names[rateLimit.Name] = true
+		if rateLimit.NfType != "" && !rateLimit.NfType.IsValid() {
+			return fmt.Errorf("rateLimits %s: invalid nfType %q", rateLimit.Name, rateLimit.NfType)
+		}
+		if rateLimit.Rate <= 0 || rateLimit.Burst < 0 {
+			return fmt.Errorf("rateLimits %s: rate must be positive and burst not negative, got %v and %d",
+				rateLimit.Name, rateLimit.Rate, rateLimit.Burst)
+		}
+	}
+	return nil
+}
+
+func validateRequestLimits(requestLimits *RequestLimitsConfig) error {
+	if requestLimits == nil {
+		return nil
+	}
+	if requestLimits.MaxUriLength < 0 || requestLimits.MaxQueryParameters < 0 || requestLimits.MaxBodySize < 0 ||
+		requestLimits.MaxSnssaiListSize < 0 || requestLimits.MaxTaiListSize < 0 || requestLimits.MaxPatchItems < 0 {
+		return fmt.Errorf("requestLimits must not be negative, got %+v", *requestLimits)
+	}
+	return nil
+}
+
+func validateSbiClient(sbiClient *SbiClient) error {
+	if sbiClient == nil {
+		return nil
+	}
+	if sbiClient.RequestTimeout < 0 || sbiClient.ConnectTimeout < 0 || sbiClient.IdleConnTimeout < 0 ||
+		sbiClient.NrfRetryInterval < 0 {
+		return fmt.Errorf("sbiClient timeouts must not be negative, got %+v", *sbiClient)
+	}
+	return nil
+}
+
+func validateAccessTypeWithoutTai(policy string) error {
+	switch policy {
+	case "", AccessTypeWithoutTai3gpp, AccessTypeWithoutTaiNon3gpp, AccessTypeWithoutTaiAll:
+		return nil
+	default:
+		return fmt.Errorf("unsupported accessTypeWithoutTai: %s, expected %s, %s or %s", policy,
+			AccessTypeWithoutTai3gpp, AccessTypeWithoutTaiNon3gpp, AccessTypeWithoutTaiAll)
+	}
+}
+
+func validateAllowedNssaiConfig(allowedNssai *AllowedNssaiConfig) error {
+	if allowedNssai == nil {
+		return nil
+	}
+	if allowedNssai.Limit < 0 || allowedNssai.Limit > MAX_ALLOWED_SNSSAI_NUM {
+		return fmt.Errorf("allowedNssai.limit must be between 1 and %d, got %d", MAX_ALLOWED_SNSSAI_NUM, allowedNssai.Limit)
+	}
+	var v validator
+	seen := make(map[SnssaiKey]bool, len(allowedNssai.PriorityList))
+	for i, item := range allowedNssai.PriorityList {
+		itemField := fmt.Sprintf("allowedNssai.priorityList[%d].snssai", i)
+		v.snssai(itemField, item.Snssai)
+		if seen[SnssaiToKey(item.Snssai)] {
+			v.add(itemField, "duplicate S-NSSAI")
+		}
+		seen[SnssaiToKey(item.Snssai)] = true
+	}
+	return v.err()
+}
+
+func validateNssaiAvailabilityPolicy(policy []NssaiAvailabilityRule) error {
+	var v validator
+	for i, rule := range policy {
+		ruleField := fmt.Sprintf("nssaiAvailabilityPolicy[%d]", i)
+		for j, tai := range rule.TaiList {
+			v.tai(fmt.Sprintf("%s.taiList[%d]", ruleField, j), tai)
+		}
+		for j, taiRange := range rule.TaiRangeList {
+			v.taiRange(fmt.Sprintf("%s.taiRangeList[%d]", ruleField, j), taiRange)
+		}
+		v.snssaiList(ruleField+".snssaiList", rule.SnssaiList)
+	}
+	return v.err()
+}
@@ -7,7 +7,13 @@
 package factory
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/omec-project/openapi/v2"
+	"github.com/omec-project/openapi/v2/models"
 )
 
 func TestCheckConfigVersion(t *testing.T) {
@@ -135,3 +141,315 @@ func TestValidateWebuiUri(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAccessTypeWithoutTai(t *testing.T) {
+	for _, policy := range []string{"", AccessTypeWithoutTai3gpp, AccessTypeWithoutTaiNon3gpp, AccessTypeWithoutTaiAll} {
+		if err := validateAccessTypeWithoutTai(policy); err != nil {
+			t.Errorf("expected policy %q to be valid, got %v", policy, err)
+		}
+	}
+	if err := validateAccessTypeWithoutTai("WLAN"); err == nil {
+		t.Error("expected an unsupported policy to be rejected")
+	}
+}
+
+func TestValidateNrfList(t *testing.T) {
+	if err := validateNrfList([]NrfConfig{{Uri: "https://nrf-1:29510", Priority: 1}, {Uri: "http://nrf-2:29510"}}); err != nil {
+		t.Errorf("expected the NRFs to be valid, got %v", err)
+	}
+	for _, uri := range []string{"", "nrf:29510", "ftp://nrf:29510", "http://"} {
+		if err := validateNrfList([]NrfConfig{{Uri: uri}}); err == nil {
+			t.Errorf("expected NRF URI %q to be rejected", uri)
+		}
+	}
+}
+
+func TestValidateScpConfig(t *testing.T) {
+	for _, scp := range []*ScpConfig{
+		nil,
+		{Uri: "https://scp:443"},
+		{Uri: "http://scp:80/prefix", Mode: ScpModeDelegatedDiscovery},
+	} {
+		if err := validateScpConfig(scp); err != nil {
+			t.Errorf("expected SCP %+v to be valid, got %v", scp, err)
+		}
+	}
+	for _, scp := range []*ScpConfig{{}, {Uri: "scp:443"}, {Uri: "https://scp:443", Mode: "B"}} {
+		if err := validateScpConfig(scp); err == nil {
+			t.Errorf("expected SCP %+v to be rejected", scp)
+		}
+	}
+}
+
+func TestValidateRateLimits(t *testing.T) {
+	if err := validateRateLimits([]RateLimitConfig{
+		{Name: "amf", NfType: models.NFTYPE_AMF, Rate: 0.5, Burst: 5},
+		{Name: "default", Rate: 100},
+	}); err != nil {
+		t.Errorf("expected the rate limits to be valid, got %v", err)
+	}
+	for _, rateLimits := range [][]RateLimitConfig{
+		{{Rate: 1}},
+		{{Name: "amf", Rate: 1}, {Name: "amf", Rate: 2}},
+		{{Name: "amf", NfType: "NOT_AN_NF", Rate: 1}},
+		{{Name: "amf", Rate: 0}},
+		{{Name: "amf", Rate: 1, Burst: -1}},
+	} {
+		if err := validateRateLimits(rateLimits); err == nil {
+			t.Errorf("expected rate limits %+v to be rejected", rateLimits)
+		}
+	}
+}
+
+func TestValidateRequestLimits(t *testing.T) {
+	for _, requestLimits := range []*RequestLimitsConfig{nil, {}, {MaxUriLength: 4096, MaxBodySize: 65536}} {
+		if err := validateRequestLimits(requestLimits); err != nil {
+			t.Errorf("expected request limits %+v to be valid, got %v", requestLimits, err)
+		}
+	}
+	for _, requestLimits := range []*RequestLimitsConfig{{MaxBodySize: -1}, {MaxPatchItems: -1}} {
+		if err := validateRequestLimits(requestLimits); err == nil {
+			t.Errorf("expected request limits %+v to be rejected", requestLimits)
+		}
+	}
+}
+
+func TestValidateAllowedNssaiConfig(t *testing.T) {
+	snssai := models.Snssai{Sst: 1}
+	tests := []struct {
+		name         string
+		allowedNssai *AllowedNssaiConfig
+		valid        bool
+	}{
+		{name: "not configured", valid: true},
+		{name: "default limit", allowedNssai: &AllowedNssaiConfig{}, valid: true},
+		{
+			name: "limit and priorities",
+			allowedNssai: &AllowedNssaiConfig{
+				Limit:        4,
+				PriorityList: []SnssaiPriorityConfig{{Snssai: snssai, Priority: 1}},
+			},
+			valid: true,
+		},
+		{name: "limit above eight", allowedNssai: &AllowedNssaiConfig{Limit: 9}},
+		{
+			name: "duplicate S-NSSAI",
+			allowedNssai: &AllowedNssaiConfig{
+				PriorityList: []SnssaiPriorityConfig{{Snssai: snssai, Priority: 1}, {Snssai: snssai, Priority: 2}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAllowedNssaiConfig(tt.allowedNssai); (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestValidateTaConfigTaiRange(t *testing.T) {
+	plmnId := models.PlmnId{Mcc: "208", Mnc: "93"}
+	accessType := models.ACCESSTYPE__3_GPP_ACCESS
+	start, end, pattern, invalidPattern := "000001", "0000ff", "00[0-9]{4}", "00[0-9"
+	tests := []struct {
+		name     string
+		taConfig TaConfig
+		valid    bool
+	}{
+		{
+			name:     "PLMN",
+			taConfig: TaConfig{PlmnId: &plmnId, AccessType: &accessType},
+			valid:    true,
+		},
+		{
+			name: "TAC ranges",
+			taConfig: TaConfig{
+				TaiRange: &models.TaiRange{
+					PlmnId:       plmnId,
+					TacRangeList: []models.TacRange{{Start: &start, End: &end}, {Pattern: &pattern}},
+				},
+				AccessType: &accessType,
+			},
+			valid: true,
+		},
+		{
+			name: "start greater than end",
+			taConfig: TaConfig{
+				TaiRange:   &models.TaiRange{PlmnId: plmnId, TacRangeList: []models.TacRange{{Start: &end, End: &start}}},
+				AccessType: &accessType,
+			},
+		},
+		{
+			name: "invalid pattern",
+			taConfig: TaConfig{
+				TaiRange:   &models.TaiRange{PlmnId: plmnId, TacRangeList: []models.TacRange{{Pattern: &invalidPattern}}},
+				AccessType: &accessType,
+			},
+		},
+		{
+			name:     "PLMN and TAI range",
+			taConfig: TaConfig{PlmnId: &plmnId, TaiRange: &models.TaiRange{PlmnId: plmnId}, AccessType: &accessType},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTaConfig(tt.taConfig); (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got %v", tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestSnapshotFindTa(t *testing.T) {
+	plmnId := models.PlmnId{Mcc: "208", Mnc: "93"}
+	configuration := &Configuration{
+		TaList: []TaConfig{
+			{PlmnId: &plmnId, SupportedSnssaiList: []models.Snssai{{Sst: 1}}},
+			{
+				TaiRange: &models.TaiRange{PlmnId: plmnId, TacRangeList: []models.TacRange{
+					{Start: openapi.PtrString("000100"), End: openapi.PtrString("0001ff")},
+					{Pattern: openapi.PtrString("02[0-9]{4}")},
+				}},
+				SupportedSnssaiList: []models.Snssai{{Sst: 2}},
+			},
+			{Tai: &models.Tai{PlmnId: plmnId, Tac: "000150"}, SupportedSnssaiList: []models.Snssai{{Sst: 3}}},
+		},
+	}
+	snapshot := NewSnapshot(configuration)
+
+	tests := []struct {
+		name    string
+		tai     models.Tai
+		found   bool
+		wantSst int32
+	}{
+		{name: "TA entry takes precedence", tai: models.Tai{PlmnId: plmnId, Tac: "000150"}, found: true, wantSst: 3},
+		{name: "TAC in range", tai: models.Tai{PlmnId: plmnId, Tac: "0001a0"}, found: true, wantSst: 2},
+		{name: "TAC matching pattern", tai: models.Tai{PlmnId: plmnId, Tac: "021234"}, found: true, wantSst: 2},
+		{name: "PLMN entry", tai: models.Tai{PlmnId: plmnId, Tac: "000001"}, found: true, wantSst: 1},
+		{name: "other PLMN", tai: models.Tai{PlmnId: models.PlmnId{Mcc: "001", Mnc: "01"}, Tac: "000001"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taConfig, found := snapshot.FindTa(tt.tai)
+			if found != tt.found {
+				t.Fatalf("expected found %v, got %v", tt.found, found)
+			}
+			if found && taConfig.SupportedSnssaiList[0].Sst != tt.wantSst {
+				t.Errorf("expected TA entry with SST %d, got %+v", tt.wantSst, taConfig)
+			}
+		})
+	}
+	if tais := snapshot.KnownTais(); len(tais) != 1 || tais[0].Tac != "000150" {
+		t.Errorf("expected the configured TA to be known, got %+v", tais)
+	}
+}
+
+func TestSnapshotIsNotAffectedByConfigurationChanges(t *testing.T) {
+	originalNssfConfig := NssfConfig
+	defer func() {
+		NssfConfig = originalNssfConfig
+		PublishSnapshotLocked()
+	}()
+	NssfConfig = Config{Configuration: &Configuration{
+		AmfList: []AmfConfig{{NfId: "amf-1"}},
+	}}
+	PublishSnapshotLocked()
+	snapshot := CurrentSnapshot()
+
+	NssfConfig.Configuration.AmfList = append([]AmfConfig(nil), AmfConfig{NfId: "amf-2"})
+	if _, found := snapshot.Amf("amf-1"); !found {
+		t.Fatal("expected the AMF to remain in the snapshot")
+	}
+	if _, found := snapshot.Amf("amf-2"); found {
+		t.Fatal("expected the snapshot not to see the change before it is published")
+	}
+
+	PublishSnapshotLocked()
+	if _, found := CurrentSnapshot().Amf("amf-2"); !found {
+		t.Fatal("expected the published snapshot to see the change")
+	}
+}
+
+func TestLoadProvisionedTables(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantErr      string
+		wantNsiCount int
+	}{
+		{
+			name:         "tables absent from the file are kept",
+			content:      "nsiList: []\n",
+			wantNsiCount: 0,
+		},
+		{
+			name:         "entries of the file replace the configured ones",
+			content:      "nsiList:\n  - snssai: {sst: 2}\n    nsiInformationList: [{nrfId: http://nrf:29510, nsiId: \"2\"}]\n",
+			wantNsiCount: 1,
+		},
+		{
+			name: "invalid entries are rejected",
+			content: "nsiList:\n  - snssai: {sst: 1, sd: xyz}\n    nsiInformationList: []\n" +
+				"supportedNssaiInPlmnList:\n  - plmnId: {mcc: \"001\", mnc: \"1\"}\n    sNssaiList: [{sst: 1}]\n",
+			wantErr: "nsiList[0].snssai.sd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := NssfConfig
+			defer func() { NssfConfig = orig }()
+
+			persistFile := filepath.Join(t.TempDir(), "provisioned.yaml")
+			if err := os.WriteFile(persistFile, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			NssfConfig = Config{Configuration: &Configuration{
+				NsiList: []NsiConfig{
+					{Snssai: &models.Snssai{Sst: 1}, NsiInformationList: []models.NsiInformation{{NrfId: "http://nrf:29510"}}},
+					{Snssai: &models.Snssai{Sst: 3}, NsiInformationList: []models.NsiInformation{{NrfId: "http://nrf:29510"}}},
+				},
+				NsagList: []NsagConfig{{NsagId: 1, SnssaiList: []models.Snssai{{Sst: 1}}}},
+				Admin:    &Admin{PersistFile: persistFile},
+			}}
+
+			err := loadProvisionedTables()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) ||
+					!strings.Contains(err.Error(), "supportedNssaiInPlmnList[0].plmnId.mnc") {
+					t.Fatalf("expected every violation to be reported, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadProvisionedTables() error = %v", err)
+			}
+			if len(NssfConfig.Configuration.NsiList) != tt.wantNsiCount {
+				t.Errorf("expected %d NSI entries, got %+v", tt.wantNsiCount, NssfConfig.Configuration.NsiList)
+			}
+			if len(NssfConfig.Configuration.NsagList) != 1 {
+				t.Errorf("expected the NSAG list absent from the file to be kept, got %+v", NssfConfig.Configuration.NsagList)
+			}
+		})
+	}
+}
+
+func TestPersistedEmptyTableStaysEmpty(t *testing.T) {
+	orig := NssfConfig
+	defer func() { NssfConfig = orig }()
+
+	persistFile := filepath.Join(t.TempDir(), "provisioned.yaml")
+	NssfConfig = Config{Configuration: &Configuration{Admin: &Admin{PersistFile: persistFile}}}
+	if err := PersistProvisionedTablesLocked(ProvisionedTablesOf(NssfConfig.Configuration)); err != nil {
+		t.Fatalf("PersistProvisionedTablesLocked() error = %v", err)
+	}
+
+	NssfConfig.Configuration.NsagList = []NsagConfig{{NsagId: 1, SnssaiList: []models.Snssai{{Sst: 1}}}}
+	if err := loadProvisionedTables(); err != nil {
+		t.Fatalf("loadProvisionedTables() error = %v", err)
+	}
+	if len(NssfConfig.Configuration.NsagList) != 0 {
+		t.Errorf("expected the persisted empty NSAG list to apply, got %+v", NssfConfig.Configuration.NsagList)
+	}
+}
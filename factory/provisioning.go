@@ -0,0 +1,222 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Provisioned Configuration
+ *
+ * Tables that can be changed at runtime through the admin API
+ */
+
+package factory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/openapi/v2/models"
+	"go.yaml.in/yaml/v4"
+)
+
+// ProvisionedTables is the part of the configuration which is managed through the admin API. Every table is
+// written, even if it is empty, so that a table emptied through the admin API stays empty after a restart
+type ProvisionedTables struct {
+	NsiList                  []NsiConfig                 `yaml:"nsiList"`
+	TaList                   []TaConfig                  `yaml:"taList"`
+	AmfSetList               []AmfSetConfig              `yaml:"amfSetList"`
+	MappingListFromPlmn      []MappingFromPlmnConfig     `yaml:"mappingListFromPlmn"`
+	SupportedNssaiInPlmnList []SupportedNssaiInPlmnEntry `yaml:"supportedNssaiInPlmnList"`
+	NsagList                 []NsagConfig                `yaml:"nsagList"`
+}
+
+// persistedTables is ProvisionedTables as read from the persist file, where the tables absent from it are nil,
+// e.g. because they were added after the file was written
+type persistedTables struct {
+	NsiList                  *[]NsiConfig                 `yaml:"nsiList"`
+	TaList                   *[]TaConfig                  `yaml:"taList"`
+	AmfSetList               *[]AmfSetConfig              `yaml:"amfSetList"`
+	MappingListFromPlmn      *[]MappingFromPlmnConfig     `yaml:"mappingListFromPlmn"`
+	SupportedNssaiInPlmnList *[]SupportedNssaiInPlmnEntry `yaml:"supportedNssaiInPlmnList"`
+	NsagList                 *[]NsagConfig                `yaml:"nsagList"`
+}
+
+// Entries returns the list form of the supported NSSAI, sorted by PLMN ID
+func (s SupportedNssaiInPlmn) Entries() []SupportedNssaiInPlmnEntry {
+	entries := make([]SupportedNssaiInPlmnEntry, 0, len(s))
+	for plmnId, snssaiSet := range s {
+		entries = append(entries, SupportedNssaiInPlmnEntry{
+			PlmnId:     plmnId,
+			SNssaiList: snssaiSetToList(snssaiSet),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return PlmnIdString(entries[i].PlmnId) < PlmnIdString(entries[j].PlmnId)
+	})
+	return entries
+}
+
+// SupportedNssaiInPlmnFromEntries builds the map form of the supported NSSAI from its list form
+func SupportedNssaiInPlmnFromEntries(entries []SupportedNssaiInPlmnEntry) SupportedNssaiInPlmn {
+	supportedNssaiInPlmn := make(SupportedNssaiInPlmn, len(entries))
+	for _, entry := range entries {
+		snssaiSet := make(map[SnssaiKey]struct{}, len(entry.SNssaiList))
+		for _, snssai := range entry.SNssaiList {
+			snssaiSet[SnssaiToKey(snssai)] = struct{}{}
+		}
+		supportedNssaiInPlmn[entry.PlmnId] = snssaiSet
+	}
+	return supportedNssaiInPlmn
+}
+
+// MergeSupportedNssaiInPlmnList sets the supported NSSAI in effect to the one polled from the webconsole, where
+// the entries provisioned through the admin API replace the polled ones of the same PLMN
+func (c *Configuration) MergeSupportedNssaiInPlmnList() {
+	supportedNssaiInPlmn := make(SupportedNssaiInPlmn, len(c.PolledSupportedNssaiInPlmnList)+
+		len(c.ProvisionedSupportedNssaiInPlmnList))
+	for plmnId, snssaiSet := range c.PolledSupportedNssaiInPlmnList {
+		supportedNssaiInPlmn[plmnId] = snssaiSet
+	}
+	for plmnId, snssaiSet := range SupportedNssaiInPlmnFromEntries(c.ProvisionedSupportedNssaiInPlmnList) {
+		supportedNssaiInPlmn[plmnId] = snssaiSet
+	}
+	c.SupportedNssaiInPlmnList = supportedNssaiInPlmn
+}
+
+// UpdatePlmnList sets PlmnList to the PLMNs of the supported NSSAI in effect, sorted by PLMN ID, and
+// reports whether they changed, in which case the NSSF registers them at the NRF
+func (c *Configuration) UpdatePlmnList() bool {
+	plmnList := make([]models.PlmnId, 0, len(c.SupportedNssaiInPlmnList))
+	for plmnId := range c.SupportedNssaiInPlmnList {
+		plmnList = append(plmnList, plmnId)
+	}
+	sort.Slice(plmnList, func(i, j int) bool {
+		return PlmnIdString(plmnList[i]) < PlmnIdString(plmnList[j])
+	})
+	if slices.Equal(plmnList, c.PlmnList) {
+		return false
+	}
+	c.PlmnList = plmnList
+	return true
+}
+
+func snssaiSetToList(snssaiSet map[SnssaiKey]struct{}) []models.Snssai {
+	keys := make([]SnssaiKey, 0, len(snssaiSet))
+	for key := range snssaiSet {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Sst != keys[j].Sst {
+			return keys[i].Sst < keys[j].Sst
+		}
+		return keys[i].Sd < keys[j].Sd
+	})
+	snssaiList := make([]models.Snssai, 0, len(keys))
+	for _, key := range keys {
+		snssai := models.NewSnssai(key.Sst)
+		if key.Sd != "" {
+			snssai.SetSd(key.Sd)
+		}
+		snssaiList = append(snssaiList, *snssai)
+	}
+	return snssaiList
+}
+
+// ProvisionedTablesOf returns the provisioned tables of the given configuration
+func ProvisionedTablesOf(configuration *Configuration) ProvisionedTables {
+	return ProvisionedTables{
+		NsiList:                  configuration.NsiList,
+		TaList:                   configuration.TaList,
+		AmfSetList:               configuration.AmfSetList,
+		MappingListFromPlmn:      configuration.MappingListFromPlmn,
+		SupportedNssaiInPlmnList: configuration.ProvisionedSupportedNssaiInPlmnList,
+		NsagList:                 configuration.NsagList,
+	}
+}
+
+// PersistProvisionedTablesLocked writes the provisioned tables to the admin persist file, if one is configured
+// The caller shall hold ConfigLock
+func PersistProvisionedTablesLocked(tables ProvisionedTables) error {
+	admin := NssfConfig.Configuration.Admin
+	if admin == nil || admin.PersistFile == "" {
+		return nil
+	}
+	content, err := yaml.Marshal(&tables)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so that a crash never leaves a truncated file behind
+	tmpFile, err := os.CreateTemp(filepath.Dir(admin.PersistFile), filepath.Base(admin.PersistFile)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err = tmpFile.Write(content); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), admin.PersistFile)
+}
+
+// loadProvisionedTables overlays the tables persisted by the admin API on top of the configuration file
+func loadProvisionedTables() error {
+	admin := NssfConfig.Configuration.Admin
+	if admin == nil || admin.PersistFile == "" {
+		return nil
+	}
+	content, err := os.ReadFile(admin.PersistFile)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.CfgLog.Infof("no provisioned tables in %s yet", admin.PersistFile)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var tables persistedTables
+	if err = yaml.Unmarshal(content, &tables); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", admin.PersistFile, err)
+	}
+
+	// The persisted entries are checked as the admin API checks them, and override only the tables they belong to
+	var v validator
+	configuration := NssfConfig.Configuration
+	overlayProvisionedTable(&v, "nsiList", tables.NsiList, ValidateNsiConfig, &configuration.NsiList)
+	overlayProvisionedTable(&v, "taList", tables.TaList, ValidateTaConfig, &configuration.TaList)
+	overlayProvisionedTable(&v, "amfSetList", tables.AmfSetList, ValidateAmfSetConfig, &configuration.AmfSetList)
+	overlayProvisionedTable(&v, "mappingListFromPlmn", tables.MappingListFromPlmn, ValidateMappingFromPlmnConfig,
+		&configuration.MappingListFromPlmn)
+	overlayProvisionedTable(&v, "supportedNssaiInPlmnList", tables.SupportedNssaiInPlmnList,
+		ValidateSupportedNssaiInPlmnEntry, &configuration.ProvisionedSupportedNssaiInPlmnList)
+	overlayProvisionedTable(&v, "nsagList", tables.NsagList, ValidateNsagConfig, &configuration.NsagList)
+	if err = v.err(); err != nil {
+		return fmt.Errorf("invalid provisioned tables in %s: %w", admin.PersistFile, err)
+	}
+	logger.CfgLog.Infof("loaded provisioned tables from %s", admin.PersistFile)
+	return nil
+}
+
+// overlayProvisionedTable replaces the table of the configuration file with the persisted one, if it was persisted,
+// and records the violations of its entries
+func overlayProvisionedTable[T any](v *validator, field string, persisted *[]T, validate func(T) error, table *[]T) {
+	if persisted == nil {
+		return
+	}
+	for i, entry := range *persisted {
+		entryField := fmt.Sprintf("%s[%d]", field, i)
+		var validationErr *ValidationError
+		if err := validate(entry); errors.As(err, &validationErr) {
+			for _, violation := range validationErr.Violations {
+				v.add(entryField+"."+violation.Field, "%s", violation.Reason)
+			}
+		} else if err != nil {
+			v.add(entryField, "%s", err.Error())
+		}
+	}
+	*table = *persisted
+}
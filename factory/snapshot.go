@@ -0,0 +1,471 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Configuration Snapshot
+ *
+ * Immutable and indexed view of the configuration used to serve requests
+ */
+
+package factory
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// TaiKey is used to avoid using models.Tai as map key directly due to pointer field issue
+type TaiKey struct {
+	Mcc string
+	Mnc string
+	Tac string
+	Nid string
+}
+
+// Helper function to convert models.Tai to TaiKey
+func TaiToKey(tai models.Tai) TaiKey {
+	return TaiKey{
+		Mcc: tai.PlmnId.GetMcc(),
+		Mnc: tai.PlmnId.GetMnc(),
+		Tac: tai.GetTac(),
+		Nid: tai.GetNid(),
+	}
+}
+
+// TaiRangeMatcher checks whether TAIs are in a TAI range, TS 29.571 clause 5.4.4.32
+// A TAC range is either a range of TACs from start to end, or a regular expression matching the TAC.
+type TaiRangeMatcher struct {
+	taiRange models.TaiRange
+	// Compiled pattern of each TAC range, nil for a range from start to end
+	patterns []*regexp.Regexp
+}
+
+func NewTaiRangeMatcher(taiRange models.TaiRange) TaiRangeMatcher {
+	matcher := TaiRangeMatcher{
+		taiRange: taiRange,
+		patterns: make([]*regexp.Regexp, len(taiRange.TacRangeList)),
+	}
+	for i, tacRange := range taiRange.TacRangeList {
+		if tacRange.Pattern == nil {
+			continue
+		}
+		pattern, err := regexp.Compile("^(?:" + tacRange.GetPattern() + ")$")
+		if err != nil {
+			logger.CfgLog.Errorf("invalid TAC pattern %q: %+v", tacRange.GetPattern(), err)
+			continue
+		}
+		matcher.patterns[i] = pattern
+	}
+	return matcher
+}
+
+// Includes reports whether the TAI is in the TAI range
+func (m TaiRangeMatcher) Includes(tai models.Tai) bool {
+	if m.taiRange.PlmnId != tai.PlmnId || m.taiRange.GetNid() != tai.GetNid() {
+		return false
+	}
+	for i, tacRange := range m.taiRange.TacRangeList {
+		if tacRange.Pattern != nil {
+			if m.patterns[i] != nil && m.patterns[i].MatchString(tai.GetTac()) {
+				return true
+			}
+			continue
+		}
+		if tacInRange(tai.GetTac(), tacRange.GetStart(), tacRange.GetEnd()) {
+			return true
+		}
+	}
+	return false
+}
+
+func tacInRange(tac, start, end string) bool {
+	if len(tac) != len(start) || len(tac) != len(end) {
+		return false
+	}
+	value, err := strconv.ParseUint(tac, 16, 32)
+	if err != nil {
+		return false
+	}
+	startValue, errStart := strconv.ParseUint(start, 16, 32)
+	endValue, errEnd := strconv.ParseUint(end, 16, 32)
+	return errStart == nil && errEnd == nil && startValue <= value && value <= endValue
+}
+
+type taRangeEntry struct {
+	matcher  TaiRangeMatcher
+	taConfig *TaConfig
+}
+
+// Snapshot is an immutable copy of the configuration, indexed for the lookups made while serving requests.
+// A request uses a single snapshot, so that it sees a consistent configuration, and a new snapshot is
+// published whenever the configuration changes. Slices and maps returned by a snapshot shall not be modified.
+type Snapshot struct {
+	taList              []TaConfig
+	taByTai             map[TaiKey]*TaConfig
+	taRanges            []taRangeEntry
+	taByPlmn            map[models.PlmnId]*TaConfig
+	knownTais           []models.Tai
+	supportedNssai      SupportedNssaiInPlmn
+	mappingByHomePlmn   map[models.PlmnId][]models.MappingOfSnssai
+	nsiBySnssai         map[SnssaiKey][]models.NsiInformation
+	amfList             []AmfConfig
+	amfByNfId           map[string]*AmfConfig
+	amfSetList          []AmfSetConfig
+	amfSetById          map[string]*AmfSetConfig
+	reportedAmfSets     map[string]bool
+	nsagList            []NsagConfig
+	accessTypeWithoutTa string
+	allowedNssaiLimit   int
+	snssaiPriorities    map[SnssaiKey]int32
+	amfSetIdsByAmf      map[string][]string
+	availabilityPolicy  []NssaiAvailabilityRule
+}
+
+var currentSnapshot atomic.Pointer[Snapshot]
+
+// NewSnapshot builds a snapshot of the configuration
+// Writers replace the lists of the configuration rather than modifying their entries, so copying the
+// lists is enough for the snapshot not to change afterwards.
+func NewSnapshot(configuration *Configuration) *Snapshot {
+	s := &Snapshot{
+		taByTai:           make(map[TaiKey]*TaConfig),
+		taByPlmn:          make(map[models.PlmnId]*TaConfig),
+		mappingByHomePlmn: make(map[models.PlmnId][]models.MappingOfSnssai),
+		nsiBySnssai:       make(map[SnssaiKey][]models.NsiInformation),
+		amfByNfId:         make(map[string]*AmfConfig),
+		snssaiPriorities:  make(map[SnssaiKey]int32),
+		amfSetIdsByAmf:    make(map[string][]string),
+		amfSetById:        make(map[string]*AmfSetConfig),
+		reportedAmfSets:   make(map[string]bool),
+		allowedNssaiLimit: MAX_ALLOWED_SNSSAI_NUM,
+	}
+	if configuration == nil {
+		return s
+	}
+
+	knownTais := make(map[TaiKey]bool)
+	addKnownTai := func(tai models.Tai) {
+		if key := TaiToKey(tai); !knownTais[key] {
+			knownTais[key] = true
+			s.knownTais = append(s.knownTais, tai)
+		}
+	}
+
+	// The first entry of a TA or a PLMN applies, as when the lists were searched linearly
+	s.taList = append([]TaConfig(nil), configuration.TaList...)
+	for i := range s.taList {
+		taConfig := &s.taList[i]
+		switch {
+		case taConfig.Tai != nil:
+			if _, found := s.taByTai[TaiToKey(*taConfig.Tai)]; !found {
+				s.taByTai[TaiToKey(*taConfig.Tai)] = taConfig
+			}
+			addKnownTai(*taConfig.Tai)
+		case taConfig.TaiRange != nil:
+			s.taRanges = append(s.taRanges, taRangeEntry{matcher: NewTaiRangeMatcher(*taConfig.TaiRange), taConfig: taConfig})
+		case taConfig.PlmnId != nil:
+			if _, found := s.taByPlmn[*taConfig.PlmnId]; !found {
+				s.taByPlmn[*taConfig.PlmnId] = taConfig
+			}
+		}
+	}
+
+	s.supportedNssai = make(SupportedNssaiInPlmn, len(configuration.SupportedNssaiInPlmnList))
+	for plmnId, snssaiSet := range configuration.SupportedNssaiInPlmnList {
+		s.supportedNssai[plmnId] = snssaiSet
+	}
+	for _, mappingFromPlmn := range configuration.MappingListFromPlmn {
+		if mappingFromPlmn.HomePlmnId == nil {
+			continue
+		}
+		if _, found := s.mappingByHomePlmn[*mappingFromPlmn.HomePlmnId]; !found {
+			s.mappingByHomePlmn[*mappingFromPlmn.HomePlmnId] = mappingFromPlmn.MappingOfSnssai
+		}
+	}
+	for _, nsiConfig := range configuration.NsiList {
+		if nsiConfig.Snssai == nil {
+			continue
+		}
+		if _, found := s.nsiBySnssai[SnssaiToKey(*nsiConfig.Snssai)]; !found {
+			s.nsiBySnssai[SnssaiToKey(*nsiConfig.Snssai)] = nsiConfig.NsiInformationList
+		}
+	}
+
+	for _, amfSetConfig := range configuration.AmfSetList {
+		for _, nfId := range amfSetConfig.AmfList {
+			s.amfSetIdsByAmf[nfId] = append(s.amfSetIdsByAmf[nfId], amfSetConfig.AmfSetId)
+		}
+	}
+	s.amfSetList = s.aggregateAmfSets(configuration.AmfSetList, configuration.AmfList, configuration.RemovedAmfSetIds)
+	for i := range s.amfSetList {
+		amfSetConfig := &s.amfSetList[i]
+		if _, found := s.amfSetById[amfSetConfig.AmfSetId]; !found {
+			s.amfSetById[amfSetConfig.AmfSetId] = amfSetConfig
+		}
+		for _, data := range amfSetConfig.SupportedNssaiAvailabilityData {
+			addKnownTai(data.Tai)
+			for _, tai := range data.TaiList {
+				addKnownTai(tai)
+			}
+		}
+	}
+	s.amfList = append([]AmfConfig(nil), configuration.AmfList...)
+	for i := range s.amfList {
+		amfConfig := &s.amfList[i]
+		if _, found := s.amfByNfId[amfConfig.NfId]; !found {
+			s.amfByNfId[amfConfig.NfId] = amfConfig
+		}
+		for _, data := range amfConfig.SupportedNssaiAvailabilityData {
+			addKnownTai(data.Tai)
+			for _, tai := range data.TaiList {
+				addKnownTai(tai)
+			}
+		}
+	}
+	s.nsagList = append([]NsagConfig(nil), configuration.NsagList...)
+
+	s.availabilityPolicy = append([]NssaiAvailabilityRule(nil), configuration.NssaiAvailabilityPolicy...)
+	s.accessTypeWithoutTa = configuration.AccessTypeWithoutTai
+	if allowedNssai := configuration.AllowedNssai; allowedNssai != nil {
+		if allowedNssai.Limit > 0 {
+			s.allowedNssaiLimit = allowedNssai.Limit
+		}
+		for _, item := range allowedNssai.PriorityList {
+			s.snssaiPriorities[SnssaiToKey(item.Snssai)] = item.Priority
+		}
+	}
+	return s
+}
+
+// aggregateAmfSets returns the AMF Sets with the NSSAI availability of their members
+// The NSSAI availability of an AMF Set is aggregated from the NSSAI availability its available members provided
+// through the NSSAIAvailability service: an S-NSSAI is available in a TA if a member supports it there. The NSSAI
+// availability of the configuration file only applies to AMF Sets none of whose members ever provided any, so
+// an AMF Set whose members are all suspended or removed is not available.
+// The members of an AMF Set configured with an `amfList` are the AMFs of the list, and those of other AMF Sets
+// are the AMFs which provided their NSSAI availability for it. AMF Sets which are not configured are appended
+// in the order their first member provided its NSSAI availability.
+func (s *Snapshot) aggregateAmfSets(amfSetList []AmfSetConfig, amfList []AmfConfig, removedAmfSetIds []string) []AmfSetConfig {
+	amfSets := append([]AmfSetConfig(nil), amfSetList...)
+	indexById := make(map[string]int)
+	for i, amfSetConfig := range amfSets {
+		if _, found := indexById[amfSetConfig.AmfSetId]; !found {
+			indexById[amfSetConfig.AmfSetId] = i
+		}
+	}
+
+	reported := make(map[int]bool)
+	for _, amfSetId := range removedAmfSetIds {
+		if i, found := indexById[amfSetId]; found {
+			reported[i] = true
+		}
+	}
+	reports := make(map[int][][]models.SupportedNssaiAvailabilityData)
+	for _, amfConfig := range amfList {
+		// AMFs of the configuration file have not provided their NSSAI availability
+		if amfConfig.Version == 0 {
+			continue
+		}
+		amfSetIds := slices.Clone(s.amfSetIdsByAmf[amfConfig.NfId])
+		if amfSetId := amfConfig.AmfSetId; amfSetId != "" && !slices.Contains(amfSetIds, amfSetId) {
+			i, found := indexById[amfSetId]
+			switch {
+			case !found:
+				indexById[amfSetId] = len(amfSets)
+				amfSets = append(amfSets, AmfSetConfig{AmfSetId: amfSetId})
+				amfSetIds = append(amfSetIds, amfSetId)
+			case len(amfSets[i].AmfList) == 0:
+				amfSetIds = append(amfSetIds, amfSetId)
+			default:
+				logger.CfgLog.Warnf("AMF %s is not a member of AMF Set %s", amfConfig.NfId, amfSetId)
+			}
+		}
+		for _, amfSetId := range amfSetIds {
+			i := indexById[amfSetId]
+			reported[i] = true
+			// The NSSAI availability of a suspended AMF is kept, but not available
+			if !amfConfig.Suspended {
+				reports[i] = append(reports[i], amfConfig.SupportedNssaiAvailabilityData)
+			}
+		}
+	}
+	for i := range reported {
+		amfSets[i].SupportedNssaiAvailabilityData = mergeNssaiAvailabilityData(reports[i])
+		s.reportedAmfSets[amfSets[i].AmfSetId] = true
+	}
+	return amfSets
+}
+
+// mergeNssaiAvailabilityData returns the union of the NSSAI availability of several AMFs
+// The S-NSSAIs are listed per TA, followed by the entries with TAI ranges, whose S-NSSAIs are also added to
+// the TAs in their ranges, as the entry of a TA takes precedence over those of TAI ranges including it.
+func mergeNssaiAvailabilityData(reports [][]models.SupportedNssaiAvailabilityData) []models.SupportedNssaiAvailabilityData {
+	var merged, rangeEntries []models.SupportedNssaiAvailabilityData
+	indexByTai := make(map[TaiKey]int)
+	snssaisByTai := make(map[TaiKey]map[SnssaiKey]bool)
+	addSnssais := func(tai models.Tai, snssaiList []models.Snssai) {
+		key := TaiToKey(tai)
+		i, found := indexByTai[key]
+		if !found {
+			i = len(merged)
+			indexByTai[key] = i
+			snssaisByTai[key] = make(map[SnssaiKey]bool)
+			merged = append(merged, models.SupportedNssaiAvailabilityData{Tai: tai})
+		}
+		for _, snssai := range snssaiList {
+			if !snssaisByTai[key][SnssaiToKey(snssai)] {
+				snssaisByTai[key][SnssaiToKey(snssai)] = true
+				merged[i].SupportedSnssaiList = append(merged[i].SupportedSnssaiList, snssai)
+			}
+		}
+	}
+
+	for _, report := range reports {
+		for _, data := range report {
+			addSnssais(data.Tai, data.SupportedSnssaiList)
+			for _, tai := range data.TaiList {
+				addSnssais(tai, data.SupportedSnssaiList)
+			}
+			if len(data.TaiRangeList) != 0 {
+				rangeEntries = append(rangeEntries, models.SupportedNssaiAvailabilityData{
+					Tai:                 data.Tai,
+					SupportedSnssaiList: data.SupportedSnssaiList,
+					TaiRangeList:        data.TaiRangeList,
+				})
+			}
+		}
+	}
+	for _, rangeEntry := range rangeEntries {
+		for _, taiRange := range rangeEntry.TaiRangeList {
+			matcher := NewTaiRangeMatcher(taiRange)
+			for i := range merged {
+				if matcher.Includes(merged[i].Tai) {
+					addSnssais(merged[i].Tai, rangeEntry.SupportedSnssaiList)
+				}
+			}
+		}
+	}
+	return append(merged, rangeEntries...)
+}
+
+// PublishSnapshotLocked rebuilds the snapshot after a change of the configuration, and returns it
+// The caller shall hold ConfigLock
+func PublishSnapshotLocked() *Snapshot {
+	s := NewSnapshot(NssfConfig.Configuration)
+	currentSnapshot.Store(s)
+	return s
+}
+
+// CurrentSnapshot returns the snapshot of the latest configuration
+func CurrentSnapshot() *Snapshot {
+	if s := currentSnapshot.Load(); s != nil {
+		return s
+	}
+	return NewSnapshot(nil)
+}
+
+// FindTa returns the most specific TA list entry of the TAI: the entry of the TA, then the first
+// TAI range including it, and finally the entry of its PLMN
+func (s *Snapshot) FindTa(tai models.Tai) (TaConfig, bool) {
+	if taConfig, found := s.taByTai[TaiToKey(tai)]; found {
+		return *taConfig, true
+	}
+	for _, entry := range s.taRanges {
+		if entry.matcher.Includes(tai) {
+			return *entry.taConfig, true
+		}
+	}
+	if taConfig, found := s.taByPlmn[tai.PlmnId]; found {
+		return *taConfig, true
+	}
+	return TaConfig{}, false
+}
+
+// KnownTais returns the TAs known to the NSSF, i.e. the TAs configured in the TA list and the TAs for
+// which AMFs or AMF Sets provided NSSAI availability, in the order they are configured
+func (s *Snapshot) KnownTais() []models.Tai {
+	return s.knownTais
+}
+
+// SupportedNssaiInPlmn returns the set of S-NSSAIs supported in the PLMN
+func (s *Snapshot) SupportedNssaiInPlmn(plmnId models.PlmnId) (map[SnssaiKey]struct{}, bool) {
+	snssaiSet, found := s.supportedNssai[plmnId]
+	return snssaiSet, found
+}
+
+// MappingOfPlmn returns the S-NSSAI mappings of the Home PLMN
+func (s *Snapshot) MappingOfPlmn(homePlmnId models.PlmnId) ([]models.MappingOfSnssai, bool) {
+	mappingOfSnssai, found := s.mappingByHomePlmn[homePlmnId]
+	return mappingOfSnssai, found
+}
+
+// NsiInformationList returns the NSI information of the S-NSSAI
+func (s *Snapshot) NsiInformationList(snssai models.Snssai) []models.NsiInformation {
+	return s.nsiBySnssai[SnssaiToKey(snssai)]
+}
+
+// Amf returns the NSSAI availability of the AMF
+func (s *Snapshot) Amf(nfId string) (AmfConfig, bool) {
+	if amfConfig, found := s.amfByNfId[nfId]; found {
+		return *amfConfig, true
+	}
+	return AmfConfig{}, false
+}
+
+func (s *Snapshot) AmfList() []AmfConfig {
+	return s.amfList
+}
+
+// AmfSetIdsOfAmf returns the AMF Sets whose `amfList` in the configuration lists the AMF as a member
+func (s *Snapshot) AmfSetIdsOfAmf(nfId string) []string {
+	return s.amfSetIdsByAmf[nfId]
+}
+
+// AmfSetReported reports whether a member of the AMF Set provided its NSSAI availability, in which case the
+// NSSAI availability and the members of the configuration file no longer apply to it
+func (s *Snapshot) AmfSetReported(amfSetId string) bool {
+	return s.reportedAmfSets[amfSetId]
+}
+
+// AmfSetList returns the AMF Sets, with the NSSAI availability aggregated from their members
+func (s *Snapshot) AmfSetList() []AmfSetConfig {
+	return s.amfSetList
+}
+
+// AmfSet returns the AMF Set, with the NSSAI availability aggregated from its members
+func (s *Snapshot) AmfSet(amfSetId string) (AmfSetConfig, bool) {
+	if amfSetConfig, found := s.amfSetById[amfSetId]; found {
+		return *amfSetConfig, true
+	}
+	return AmfSetConfig{}, false
+}
+
+func (s *Snapshot) NsagList() []NsagConfig {
+	return s.nsagList
+}
+
+// AccessTypeWithoutTai returns the policy for the Access Types of the Allowed NSSAI without TAI
+func (s *Snapshot) AccessTypeWithoutTai() string {
+	return s.accessTypeWithoutTa
+}
+
+// AllowedNssaiLimit returns the maximum number of S-NSSAIs in the Allowed NSSAI of an Access Type
+func (s *Snapshot) AllowedNssaiLimit() int {
+	return s.allowedNssaiLimit
+}
+
+// SnssaiPriority returns the priority of the S-NSSAI for the Allowed NSSAI
+func (s *Snapshot) SnssaiPriority(snssai models.Snssai) (int32, bool) {
+	priority, found := s.snssaiPriorities[SnssaiToKey(snssai)]
+	return priority, found
+}
+
+// NssaiAvailabilityPolicy returns the rules for the NSSAI availability AMFs may provide
+func (s *Snapshot) NssaiAvailabilityPolicy() []NssaiAvailabilityRule {
+	return s.availabilityPolicy
+}
@@ -0,0 +1,282 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Configuration Validation
+ */
+
+package factory
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// Patterns of the common data types, TS 29.571 clause 5.4.2
+var (
+	MccPattern = regexp.MustCompile(`^[0-9]{3}$`)
+	MncPattern = regexp.MustCompile(`^[0-9]{2,3}$`)
+	TacPattern = regexp.MustCompile(`^([A-Fa-f0-9]{4}|[A-Fa-f0-9]{6})$`)
+	SdPattern  = regexp.MustCompile(`^[A-Fa-f0-9]{6}$`)
+	NidPattern = regexp.MustCompile(`^[A-Fa-f0-9]{11}$`)
+)
+
+// Violation is a single problem found in a configuration entry
+type Violation struct {
+	Field  string
+	Reason string
+}
+
+// ValidationError carries every violation found in a configuration entry
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	reasons := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", violation.Field, violation.Reason))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v.violations}
+}
+
+func (v *validator) plmnId(field string, plmnId models.PlmnId) {
+	if !MccPattern.MatchString(plmnId.GetMcc()) {
+		v.add(field+".mcc", "must be 3 digits, got %q", plmnId.GetMcc())
+	}
+	if !MncPattern.MatchString(plmnId.GetMnc()) {
+		v.add(field+".mnc", "must be 2 or 3 digits, got %q", plmnId.GetMnc())
+	}
+}
+
+func (v *validator) snssai(field string, snssai models.Snssai) {
+	if snssai.GetSst() < 0 || snssai.GetSst() > 255 {
+		v.add(field+".sst", "must be within 0 to 255, got %d", snssai.GetSst())
+	}
+	if snssai.Sd != nil && !SdPattern.MatchString(snssai.GetSd()) {
+		v.add(field+".sd", "must be 6 hexadecimal digits, got %q", snssai.GetSd())
+	}
+}
+
+func (v *validator) snssaiList(field string, snssaiList []models.Snssai) {
+	seen := make(map[SnssaiKey]struct{}, len(snssaiList))
+	for i, snssai := range snssaiList {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		v.snssai(itemField, snssai)
+		key := SnssaiToKey(snssai)
+		if _, found := seen[key]; found {
+			v.add(itemField, "duplicate S-NSSAI %s", SnssaiKeyString(key))
+		}
+		seen[key] = struct{}{}
+	}
+}
+
+func (v *validator) tai(field string, tai models.Tai) {
+	v.plmnId(field+".plmnId", tai.PlmnId)
+	if !TacPattern.MatchString(tai.GetTac()) {
+		v.add(field+".tac", "must be 4 or 6 hexadecimal digits, got %q", tai.GetTac())
+	}
+	if tai.Nid != nil && !NidPattern.MatchString(tai.GetNid()) {
+		v.add(field+".nid", "must be 11 hexadecimal digits, got %q", tai.GetNid())
+	}
+}
+
+func (v *validator) taiRange(field string, taiRange models.TaiRange) {
+	v.plmnId(field+".plmnId", taiRange.PlmnId)
+	if len(taiRange.TacRangeList) == 0 {
+		v.add(field+".tacRangeList", "must not be empty")
+	}
+	for i, tacRange := range taiRange.TacRangeList {
+		itemField := fmt.Sprintf("%s.tacRangeList[%d]", field, i)
+		if tacRange.Pattern != nil {
+			if tacRange.Start != nil || tacRange.End != nil {
+				v.add(itemField, "pattern must not be combined with start and end")
+			}
+			if _, err := regexp.Compile(tacRange.GetPattern()); err != nil {
+				v.add(itemField+".pattern", "invalid regular expression: %v", err)
+			}
+			continue
+		}
+		if !TacPattern.MatchString(tacRange.GetStart()) {
+			v.add(itemField+".start", "must be 4 or 6 hexadecimal digits, got %q", tacRange.GetStart())
+		}
+		if !TacPattern.MatchString(tacRange.GetEnd()) {
+			v.add(itemField+".end", "must be 4 or 6 hexadecimal digits, got %q", tacRange.GetEnd())
+		}
+		if len(tacRange.GetStart()) == len(tacRange.GetEnd()) &&
+			strings.ToLower(tacRange.GetStart()) > strings.ToLower(tacRange.GetEnd()) {
+			v.add(itemField, "start must not be greater than end")
+		}
+	}
+	if taiRange.Nid != nil && !NidPattern.MatchString(taiRange.GetNid()) {
+		v.add(field+".nid", "must be 11 hexadecimal digits, got %q", taiRange.GetNid())
+	}
+}
+
+func (v *validator) accessType(field string, accessType models.AccessType) {
+	switch accessType {
+	case models.ACCESSTYPE__3_GPP_ACCESS, models.ACCESSTYPE_NON_3_GPP_ACCESS:
+	default:
+		v.add(field, "unknown access type %q", accessType)
+	}
+}
+
+func (v *validator) restrictedSnssaiList(field string, restrictedSnssaiList []models.RestrictedSnssai) {
+	for i, restrictedSnssai := range restrictedSnssaiList {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		v.plmnId(itemField+".homePlmnId", restrictedSnssai.HomePlmnId)
+		for j, plmnId := range restrictedSnssai.HomePlmnIdList {
+			v.plmnId(fmt.Sprintf("%s.homePlmnIdList[%d]", itemField, j), plmnId)
+		}
+		v.snssaiList(itemField+".sNssaiList", restrictedSnssai.SNssaiList)
+	}
+}
+
+func (v *validator) supportedNssaiAvailabilityData(field string, data []models.SupportedNssaiAvailabilityData) {
+	for i, item := range data {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		v.tai(itemField+".tai", item.Tai)
+		v.snssaiList(itemField+".supportedSnssaiList", item.SupportedSnssaiList)
+	}
+}
+
+// ValidateNsiConfig checks an NSI list entry
+func ValidateNsiConfig(nsiConfig NsiConfig) error {
+	var v validator
+	if nsiConfig.Snssai == nil {
+		v.add("snssai", "is required")
+	} else {
+		v.snssai("snssai", *nsiConfig.Snssai)
+	}
+	if len(nsiConfig.NsiInformationList) == 0 {
+		v.add("nsiInformationList", "must not be empty")
+	}
+	for i, nsiInformation := range nsiConfig.NsiInformationList {
+		if nsiInformation.GetNrfId() == "" {
+			v.add(fmt.Sprintf("nsiInformationList[%d].nrfId", i), "is required")
+		}
+	}
+	return v.err()
+}
+
+// ValidateTaConfig checks a TA list entry
+func ValidateTaConfig(taConfig TaConfig) error {
+	var v validator
+	switch {
+	case taConfig.Tai != nil && taConfig.TaiRange == nil && taConfig.PlmnId == nil:
+		v.tai("tai", *taConfig.Tai)
+	case taConfig.Tai == nil && taConfig.TaiRange != nil && taConfig.PlmnId == nil:
+		v.taiRange("taiRange", *taConfig.TaiRange)
+	case taConfig.Tai == nil && taConfig.TaiRange == nil && taConfig.PlmnId != nil:
+		v.plmnId("plmnId", *taConfig.PlmnId)
+	default:
+		v.add("tai", "exactly one of tai, taiRange and plmnId is required")
+	}
+	if len(taConfig.AccessTypeList) != 0 {
+		if taConfig.AccessType != nil || len(taConfig.SupportedSnssaiList) != 0 || len(taConfig.RestrictedSnssaiList) != 0 {
+			v.add("accessTypeList", "must not be combined with accessType, supportedSnssaiList or restrictedSnssaiList")
+		}
+		seen := make(map[models.AccessType]bool, len(taConfig.AccessTypeList))
+		for i, accessTypeConfig := range taConfig.AccessTypeList {
+			itemField := fmt.Sprintf("accessTypeList[%d]", i)
+			v.accessType(itemField+".accessType", accessTypeConfig.AccessType)
+			if seen[accessTypeConfig.AccessType] {
+				v.add(itemField+".accessType", "duplicate access type %q", accessTypeConfig.AccessType)
+			}
+			seen[accessTypeConfig.AccessType] = true
+			v.snssaiList(itemField+".supportedSnssaiList", accessTypeConfig.SupportedSnssaiList)
+			v.restrictedSnssaiList(itemField+".restrictedSnssaiList", accessTypeConfig.RestrictedSnssaiList)
+		}
+		return v.err()
+	}
+	if taConfig.AccessType == nil {
+		v.add("accessType", "is required")
+	} else {
+		v.accessType("accessType", *taConfig.AccessType)
+	}
+	v.snssaiList("supportedSnssaiList", taConfig.SupportedSnssaiList)
+	v.restrictedSnssaiList("restrictedSnssaiList", taConfig.RestrictedSnssaiList)
+	return v.err()
+}
+
+// ValidateAmfSetConfig checks an AMF set list entry
+func ValidateAmfSetConfig(amfSetConfig AmfSetConfig) error {
+	var v validator
+	if amfSetConfig.AmfSetId == "" {
+		v.add("amfSetId", "is required")
+	}
+	for i, nfId := range amfSetConfig.AmfList {
+		if nfId == "" {
+			v.add(fmt.Sprintf("amfList[%d]", i), "must not be empty")
+		}
+	}
+	v.supportedNssaiAvailabilityData("supportedNssaiAvailabilityData", amfSetConfig.SupportedNssaiAvailabilityData)
+	return v.err()
+}
+
+// ValidateMappingFromPlmnConfig checks an S-NSSAI mapping list entry
+func ValidateMappingFromPlmnConfig(mappingFromPlmn MappingFromPlmnConfig) error {
+	var v validator
+	if mappingFromPlmn.HomePlmnId == nil {
+		v.add("homePlmnId", "is required")
+	} else {
+		v.plmnId("homePlmnId", *mappingFromPlmn.HomePlmnId)
+	}
+	servingSnssais := make(map[SnssaiKey]struct{}, len(mappingFromPlmn.MappingOfSnssai))
+	for i, mapping := range mappingFromPlmn.MappingOfSnssai {
+		itemField := fmt.Sprintf("mappingOfSnssai[%d]", i)
+		v.snssai(itemField+".servingSnssai", mapping.ServingSnssai)
+		v.snssai(itemField+".homeSnssai", mapping.HomeSnssai)
+		key := SnssaiToKey(mapping.ServingSnssai)
+		if _, found := servingSnssais[key]; found {
+			v.add(itemField+".servingSnssai", "S-NSSAI %s is mapped more than once", SnssaiKeyString(key))
+		}
+		servingSnssais[key] = struct{}{}
+	}
+	return v.err()
+}
+
+// ValidateSupportedNssaiInPlmnEntry checks a supported NSSAI in PLMN entry
+func ValidateSupportedNssaiInPlmnEntry(entry SupportedNssaiInPlmnEntry) error {
+	var v validator
+	v.plmnId("plmnId", entry.PlmnId)
+	v.snssaiList("sNssaiList", entry.SNssaiList)
+	return v.err()
+}
+
+// ValidateNsagConfig checks an NSAG list entry
+func ValidateNsagConfig(nsagConfig NsagConfig) error {
+	var v validator
+	if nsagConfig.NsagId < 0 || nsagConfig.NsagId > 255 {
+		v.add("nsagId", "must be within 0 to 255, got %d", nsagConfig.NsagId)
+	}
+	if len(nsagConfig.SnssaiList) == 0 {
+		v.add("snssaiList", "must not be empty")
+	}
+	v.snssaiList("snssaiList", nsagConfig.SnssaiList)
+	if nsagConfig.Priority != nil && (*nsagConfig.Priority < 1 || *nsagConfig.Priority > 256) {
+		v.add("priority", "must be within 1 to 256, got %d", *nsagConfig.Priority)
+	}
+	for i, tai := range nsagConfig.TaiList {
+		v.tai(fmt.Sprintf("taiList[%d]", i), tai)
+	}
+	return v.err()
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Supported Features
+ *
+ * Supported features bitmasks as defined in TS 29.500 clause 6.6 and the feature
+ * lists of the Nnssf services in TS 29.531
+ */
+
+package features
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxFeatures is the highest feature number that can be represented by a Set
+const maxFeatures = 64
+
+// Set is a supported features bitmask. Feature n (starting with 1) is bit n-1.
+type Set uint64
+
+// Parse decodes a supportedFeatures string. Characters are hexadecimal digits with the
+// most significant one first. Features beyond the ones a Set can hold are unknown to
+// the NSSF and are ignored, as a consumer may support more features than this NSSF knows of.
+func Parse(s string) (Set, error) {
+	var set Set
+	for i := 0; i < len(s); i++ {
+		var nibble uint64
+		c := s[len(s)-1-i]
+		switch {
+		case c >= '0' && c <= '9':
+			nibble = uint64(c - '0')
+		case c >= 'a' && c <= 'f':
+			nibble = uint64(c-'a') + 10
+		case c >= 'A' && c <= 'F':
+			nibble = uint64(c-'A') + 10
+		default:
+			return 0, fmt.Errorf("invalid supported features %q: %q is not a hexadecimal digit", s, c)
+		}
+		if 4*i < maxFeatures {
+			set |= Set(nibble << (4 * i))
+		}
+	}
+	return set, nil
+}
+
+// String encodes the set as a supportedFeatures string, without leading zeroes
+func (s Set) String() string {
+	return fmt.Sprintf("%X", uint64(s))
+}
+
+// Has reports whether feature number n is in the set
+func (s Set) Has(n uint) bool {
+	return n >= 1 && n <= maxFeatures && s&(1<<(n-1)) != 0
+}
+
+// Intersect returns the features which are in both sets
+func (s Set) Intersect(other Set) Set {
+	return s & other
+}
+
+// Feature is one entry of the supported features list of an API
+type Feature struct {
+	Number      uint
+	Name        string
+	Description string
+}
+
+// Registry holds the features of one API and which of them this NSSF implements
+type Registry struct {
+	api       string
+	features  []Feature
+	supported Set
+}
+
+// NewRegistry creates the registry of an API. supported lists the names of the features this NSSF implements.
+func NewRegistry(api string, features []Feature, supported ...string) *Registry {
+	registry := &Registry{api: api, features: features}
+	for _, name := range supported {
+		feature, found := registry.Lookup(name)
+		if !found {
+			panic(fmt.Sprintf("feature %s is not defined for %s", name, api))
+		}
+		registry.supported |= 1 << (feature.Number - 1)
+	}
+	return registry
+}
+
+// Lookup returns the feature with the given name
+func (r *Registry) Lookup(name string) (Feature, bool) {
+	for _, feature := range r.features {
+		if strings.EqualFold(feature.Name, name) {
+			return feature, true
+		}
+	}
+	return Feature{}, false
+}
+
+// Supported returns the features this NSSF implements
+func (r *Registry) Supported() Set {
+	return r.supported
+}
+
+// Negotiate parses the features indicated by a consumer and returns the ones both sides support.
+// A consumer which does not indicate any features gets none of the optional features.
+func (r *Registry) Negotiate(consumerFeatures string) (Set, error) {
+	consumerSet, err := Parse(consumerFeatures)
+	if err != nil {
+		return 0, err
+	}
+	return r.supported.Intersect(consumerSet), nil
+}
+
+// Includes reports whether the named feature is in the set
+func (r *Registry) Includes(set Set, name string) bool {
+	feature, found := r.Lookup(name)
+	return found && set.Has(feature.Number)
+}
+
+// Names returns the names of the features in the set, in feature number order
+func (r *Registry) Names(set Set) []string {
+	names := make([]string, 0, len(r.features))
+	for _, feature := range r.features {
+		if set.Has(feature.Number) {
+			names = append(names, feature.Name)
+		}
+	}
+	return names
+}
+
+// String returns the API the registry belongs to
+func (r *Registry) String() string {
+	return r.api
+}
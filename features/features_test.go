@@ -0,0 +1,66 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package features
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name        string
+		input       string
+		expected    Set
+		expectError bool
+	}{
+		{name: "empty", input: "", expected: 0},
+		{name: "feature 1", input: "1", expected: 0x1},
+		{name: "features 1 and 3", input: "05", expected: 0x5},
+		{name: "second character", input: "A0", expected: 0xA0},
+		{name: "longer than known features", input: "F0000000000000000001", expected: 0x1},
+		{name: "not hexadecimal", input: "1G", expectError: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			set, err := Parse(tc.input)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if set != tc.expected {
+				t.Errorf("expected %X, got %X", uint64(tc.expected), uint64(set))
+			}
+		})
+	}
+}
+
+func TestRegistryNegotiate(t *testing.T) {
+	registry := NewRegistry("test", []Feature{
+		{Number: 1, Name: "ONE"},
+		{Number: 2, Name: "TWO"},
+		{Number: 5, Name: "FIVE"},
+	}, "TWO", "FIVE")
+
+	if registry.Supported().String() != "12" {
+		t.Fatalf("unexpected supported features %s", registry.Supported())
+	}
+	negotiated, err := registry.Negotiate("3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !negotiated.Has(2) || negotiated.Has(1) || negotiated.Has(5) {
+		t.Errorf("unexpected negotiated features %s", negotiated)
+	}
+	if names := registry.Names(negotiated); len(names) != 1 || names[0] != "TWO" {
+		t.Errorf("unexpected feature names %v", names)
+	}
+	if negotiated, _ = registry.Negotiate(""); negotiated != 0 {
+		t.Errorf("expected no features without consumer features, got %s", negotiated)
+	}
+}
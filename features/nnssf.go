@@ -0,0 +1,37 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Supported Features
+ *
+ * Supported features bitmasks as defined in TS 29.500 clause 6.6 and the feature
+ * lists of the Nnssf services in TS 29.531
+ */
+
+package features
+
+// Features of the Nnssf_NSSAIAvailability service (TS 29.531 clause 6.2.8)
+const (
+	NssaiAvailabilityES3XX = "ES3XX"
+)
+
+// Features of the Nnssf_NSSelection service (TS 29.531 clause 6.1.8)
+const (
+	NSSelectionES3XX = "ES3XX"
+	// Network Slice Simultaneous Registration Group, TS 23.501 clause 5.15.12
+	NSSelectionNSSRG = "NSSRG"
+	// Network Slice AS Group, TS 23.501 clause 5.15.14
+	NSSelectionNSAG = "NSAG"
+)
+
+// NssaiAvailability is the feature registry of Nnssf_NSSAIAvailability
+var NssaiAvailability = NewRegistry("nnssf-nssaiavailability", []Feature{
+	{Number: 1, Name: NssaiAvailabilityES3XX, Description: "Extended support of HTTP 307/308 redirection"},
+})
+
+// NSSelection is the feature registry of Nnssf_NSSelection
+var NSSelection = NewRegistry("nnssf-nsselection", []Feature{
+	{Number: 1, Name: NSSelectionES3XX, Description: "Extended support of HTTP 307/308 redirection"},
+	{Number: 2, Name: NSSelectionNSSRG, Description: "Network Slice Simultaneous Registration Group"},
+	{Number: 3, Name: NSSelectionNSAG, Description: "Network Slice AS Group"},
+}, NSSelectionNSSRG, NSSelectionNSAG)
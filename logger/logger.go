@@ -26,6 +26,7 @@ var (
 	GinLog             *zap.SugaredLogger
 	PollConfigLog      *zap.SugaredLogger
 	NrfRegistrationLog *zap.SugaredLogger
+	AdminLog           *zap.SugaredLogger
 	atomicLevel        zap.AtomicLevel
 )
 
@@ -68,6 +69,7 @@ func init() {
 	GinLog = log.Sugar().With("component", "NSSF", "category", "GIN")
 	PollConfigLog = log.Sugar().With("component", "NSSF", "category", "PollConfig")
 	NrfRegistrationLog = log.Sugar().With("component", "NSSF", "category", "NrfRegistration")
+	AdminLog = log.Sugar().With("component", "NSSF", "category", "Admin")
 }
 
 // SetLogLevel: set the log level (panic|fatal|error|warn|info|debug)
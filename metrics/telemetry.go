@@ -17,7 +17,11 @@ import (
 
 // NssfStats captures NSSF stats
 type NssfStats struct {
-	nssfNsSelections *prometheus.CounterVec
+	nssfNsSelections        *prometheus.CounterVec
+	nssfStaleSnssaiMappings *prometheus.CounterVec
+	nssfRejectedSnssais     *prometheus.CounterVec
+	nssfRejectedRequests    *prometheus.CounterVec
+	nssfThrottledRequests   *prometheus.CounterVec
 }
 
 var nssfStats *NssfStats
@@ -28,6 +32,22 @@ func initNssfStats() *NssfStats {
 			Name: "nssf_ns_selections",
 			Help: "Counter of total NS selection queries",
 		}, []string{"target_nf_type", "nf_id", "result"}),
+		nssfStaleSnssaiMappings: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "nssf_stale_snssai_mappings",
+			Help: "Counter of S-NSSAI mappings provided by UEs which do not match the NSSF configuration",
+		}, []string{"home_plmn_id"}),
+		nssfRejectedSnssais: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "nssf_rejected_snssais",
+			Help: "Counter of S-NSSAIs rejected by the NSSF although available to the UE",
+		}, []string{"cause"}),
+		nssfRejectedRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "nssf_rejected_requests",
+			Help: "Counter of SBI requests rejected by the overload control of the NSSF",
+		}, []string{"cause"}),
+		nssfThrottledRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "nssf_throttled_requests",
+			Help: "Counter of SBI requests rejected by the rate limits of the NF service consumers",
+		}, []string{"class", "nf_type"}),
 	}
 }
 
@@ -35,6 +55,18 @@ func (ps *NssfStats) register() error {
 	if err := prometheus.Register(ps.nssfNsSelections); err != nil {
 		return err
 	}
+	if err := prometheus.Register(ps.nssfStaleSnssaiMappings); err != nil {
+		return err
+	}
+	if err := prometheus.Register(ps.nssfRejectedSnssais); err != nil {
+		return err
+	}
+	if err := prometheus.Register(ps.nssfRejectedRequests); err != nil {
+		return err
+	}
+	if err := prometheus.Register(ps.nssfThrottledRequests); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -58,3 +90,24 @@ func InitMetrics() {
 func IncrementNssfNsSelectionsStats(targetNfType, nfId, result string) {
 	nssfStats.nssfNsSelections.WithLabelValues(targetNfType, nfId, result).Inc()
 }
+
+// IncrementNssfStaleSnssaiMappingsStats increments number of stale S-NSSAI mappings provided by UEs
+func IncrementNssfStaleSnssaiMappingsStats(homePlmnId string) {
+	nssfStats.nssfStaleSnssaiMappings.WithLabelValues(homePlmnId).Inc()
+}
+
+// IncrementNssfRejectedSnssaisStats increments number of S-NSSAIs rejected by the NSSF with the given cause
+func IncrementNssfRejectedSnssaisStats(cause string) {
+	nssfStats.nssfRejectedSnssais.WithLabelValues(cause).Inc()
+}
+
+// IncrementNssfRejectedRequestsStats increments number of SBI requests rejected by the overload control
+func IncrementNssfRejectedRequestsStats(cause string) {
+	nssfStats.nssfRejectedRequests.WithLabelValues(cause).Inc()
+}
+
+// IncrementNssfThrottledRequestsStats increments number of SBI requests rejected by the rate limit of a class of
+// consumers. The consumers are not labelled by their NF instance ID, which they choose, to bound the series
+func IncrementNssfThrottledRequestsStats(class, nfType string) {
+	nssfStats.nssfThrottledRequests.WithLabelValues(class, nfType).Inc()
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package nfregistration
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/omec-project/nssf/consumer"
+	nssfContext "github.com/omec-project/nssf/context"
+	"github.com/omec-project/nssf/logger"
+)
+
+// A subscription is renewed when it expires within this time, that is before the next heartbeats
+const amfStatusSubscriptionRenewal = 2 * time.Duration(defaultHeartbeatTimer) * time.Second
+
+// AmfStatusSynchronizer applies the status of the AMFs at the NRF, discovered with amfRegistered when the NSSF
+// subscribes to the status of AMFs, so that the changes the NSSF was not notified about before are taken into account
+// It stops when ctx is cancelled, i.e. when the subscription is removed or replaced.
+var AmfStatusSynchronizer func(ctx context.Context, amfRegistered func(ctx context.Context, nfId string) (bool, error))
+
+var (
+	amfStatusSubscriptionId       string
+	amfStatusSubscriptionValidity *time.Time
+	// NRF at which the subscription was created
+	amfStatusSubscriptionNrfUri string
+	// Cancels the synchronization of the status of AMFs started with the subscription
+	amfStatusSynchronizationCancel context.CancelFunc
+	amfStatusSubscriptionMutex     sync.Mutex
+)
+
+// subscribeAmfStatus subscribes to the status of the AMFs at the NRF, unless the NSSF is subscribed already
+// A subscription about to expire is replaced by a new one, and a subscription at another NRF than the
+// current one is created again at the current NRF. The status of the AMFs is then synchronized in the
+// background, as it takes one search at the NRF per AMF.
+func subscribeAmfStatus() {
+	if synchronizationCtx := renewAmfStatusSubscription(); synchronizationCtx != nil && AmfStatusSynchronizer != nil {
+		go AmfStatusSynchronizer(synchronizationCtx, consumer.SendSearchAmfInstance)
+	}
+}
+
+// renewAmfStatusSubscription creates the subscription to the status of the AMFs if needed, and returns the context
+// of the synchronization of their status if it did
+func renewAmfStatusSubscription() context.Context {
+	amfStatusSubscriptionMutex.Lock()
+	defer amfStatusSubscriptionMutex.Unlock()
+	if nrfUri := currentNrfUri(); amfStatusSubscriptionId != "" && amfStatusSubscriptionNrfUri != nrfUri {
+		logger.NrfRegistrationLog.Infof("NRF changed, subscription %s at NRF %s is replaced",
+			amfStatusSubscriptionId, amfStatusSubscriptionNrfUri)
+		amfStatusSubscriptionId = ""
+		amfStatusSubscriptionValidity = nil
+	}
+	if amfStatusSubscriptionId != "" &&
+		(amfStatusSubscriptionValidity == nil || time.Until(*amfStatusSubscriptionValidity) > amfStatusSubscriptionRenewal) {
+		return nil
+	}
+
+	subscriptionId, validityTime, err := consumer.SendCreateSubscription(nssfContext.GetNfStatusNotifyUri())
+	if err != nil {
+		logger.NrfRegistrationLog.Warnln("subscribe to the status of AMFs at NRF failed. Will retry.", err.Error())
+		return nil
+	}
+	logger.NrfRegistrationLog.Infof("subscribed to the status of AMFs at NRF: %s", subscriptionId)
+	if previousSubscriptionId := amfStatusSubscriptionId; previousSubscriptionId != "" {
+		if err = consumer.SendRemoveSubscription(previousSubscriptionId); err != nil {
+			logger.NrfRegistrationLog.Warnln("remove subscription to the status of AMFs error:", err.Error())
+		}
+	}
+	amfStatusSubscriptionId = subscriptionId
+	amfStatusSubscriptionValidity = validityTime
+	amfStatusSubscriptionNrfUri = currentNrfUri()
+	cancelAmfStatusSynchronizationLocked()
+	var synchronizationCtx context.Context
+	synchronizationCtx, amfStatusSynchronizationCancel = context.WithCancel(context.Background())
+	return synchronizationCtx
+}
+
+// cancelAmfStatusSynchronizationLocked stops the synchronization of the status of AMFs, if it is running
+// The caller shall hold amfStatusSubscriptionMutex
+func cancelAmfStatusSynchronizationLocked() {
+	if amfStatusSynchronizationCancel != nil {
+		amfStatusSynchronizationCancel()
+		amfStatusSynchronizationCancel = nil
+	}
+}
+
+// unsubscribeAmfStatus removes the subscription to the status of the AMFs at the NRF
+func unsubscribeAmfStatus() {
+	amfStatusSubscriptionMutex.Lock()
+	defer amfStatusSubscriptionMutex.Unlock()
+	cancelAmfStatusSynchronizationLocked()
+	if amfStatusSubscriptionId == "" {
+		return
+	}
+	if err := consumer.SendRemoveSubscription(amfStatusSubscriptionId); err != nil {
+		logger.NrfRegistrationLog.Warnln("remove subscription to the status of AMFs error:", err.Error())
+	}
+	amfStatusSubscriptionId = ""
+	amfStatusSubscriptionValidity = nil
+}
+
+// AmfStatusSubscriptionActive reports whether the NRF is expected to notify the NSSF about the status of AMFs,
+// i.e. whether the NSSF is registered and has a valid subscription
+func AmfStatusSubscriptionActive() bool {
+	amfStatusSubscriptionMutex.Lock()
+	subscribed := amfStatusSubscriptionId != "" &&
+		(amfStatusSubscriptionValidity == nil || time.Now().Before(*amfStatusSubscriptionValidity))
+	amfStatusSubscriptionMutex.Unlock()
+	return subscribed && GetRegistrationState().Status == StatusRegistered
+}
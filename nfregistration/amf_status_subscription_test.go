@@ -0,0 +1,109 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package nfregistration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/omec-project/nssf/consumer"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// TestMain keeps the tests of the registration from subscribing to an actual NRF
+func TestMain(m *testing.M) {
+	consumer.SendCreateSubscription = func(string) (string, *time.Time, error) {
+		return "", nil, errors.New("no NRF in unit tests")
+	}
+	consumer.SendRemoveSubscription = func(string) error { return nil }
+	os.Exit(m.Run())
+}
+
+func TestAmfStatusSubscriptionFollowsRegistration(t *testing.T) {
+	originalCreate, originalRemove := consumer.SendCreateSubscription, consumer.SendRemoveSubscription
+	originalDeregister := consumer.SendDeregisterNFInstance
+	t.Cleanup(func() {
+		consumer.SendCreateSubscription, consumer.SendRemoveSubscription = originalCreate, originalRemove
+		consumer.SendDeregisterNFInstance = originalDeregister
+		amfStatusSubscriptionId, amfStatusSubscriptionValidity = "", nil
+	})
+
+	var created, removed []string
+	validityTime := time.Now().Add(time.Minute)
+	consumer.SendCreateSubscription = func(string) (string, *time.Time, error) {
+		subscriptionId := fmt.Sprintf("subscription-%d", len(created)+1)
+		created = append(created, subscriptionId)
+		return subscriptionId, &validityTime, nil
+	}
+	consumer.SendRemoveSubscription = func(subscriptionId string) error {
+		removed = append(removed, subscriptionId)
+		return nil
+	}
+	consumer.SendDeregisterNFInstance = func() error { return nil }
+
+	recordRegistered(models.NewNFProfileWithDefaults(), nil)
+	subscribeAmfStatus()
+	if !AmfStatusSubscriptionActive() {
+		t.Fatal("expected the subscription to be active once registered and subscribed")
+	}
+
+	// The subscription expires before the next heartbeats, so it is replaced
+	subscribeAmfStatus()
+	if len(created) != 2 || len(removed) != 1 || removed[0] != created[0] {
+		t.Fatalf("expected the expiring subscription to be replaced, created %v and removed %v", created, removed)
+	}
+
+	DeregisterNF()
+	if AmfStatusSubscriptionActive() || len(removed) != 2 || removed[1] != created[1] {
+		t.Fatalf("expected the subscription to be removed on deregistration, removed %v", removed)
+	}
+}
+
+func TestAmfStatusSynchronizationRunsOutsideTheSubscriptionLock(t *testing.T) {
+	originalCreate, originalDeregister := consumer.SendCreateSubscription, consumer.SendDeregisterNFInstance
+	originalSynchronizer := AmfStatusSynchronizer
+	t.Cleanup(func() {
+		consumer.SendCreateSubscription, consumer.SendDeregisterNFInstance = originalCreate, originalDeregister
+		AmfStatusSynchronizer = originalSynchronizer
+		amfStatusSubscriptionId, amfStatusSubscriptionValidity = "", nil
+	})
+
+	validityTime := time.Now().Add(time.Hour)
+	consumer.SendCreateSubscription = func(string) (string, *time.Time, error) {
+		return "subscription-1", &validityTime, nil
+	}
+	consumer.SendDeregisterNFInstance = func() error { return nil }
+	synchronizing, synchronizationDone := make(chan struct{}), make(chan error, 1)
+	AmfStatusSynchronizer = func(ctx context.Context, amfRegistered func(ctx context.Context, nfId string) (bool, error)) {
+		// Stands for searches at an NRF which does not answer
+		close(synchronizing)
+		<-ctx.Done()
+		synchronizationDone <- ctx.Err()
+	}
+
+	recordRegistered(models.NewNFProfileWithDefaults(), nil)
+	subscribeAmfStatus()
+	select {
+	case <-synchronizing:
+	case <-time.After(time.Second):
+		t.Fatal("expected the status of the AMFs to be synchronized")
+	}
+	if !AmfStatusSubscriptionActive() {
+		t.Fatal("expected the subscription to be active while the status of the AMFs is synchronized")
+	}
+
+	DeregisterNF()
+	select {
+	case err := <-synchronizationDone:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the synchronization to be cancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the synchronization to be cancelled on deregistration")
+	}
+}
@@ -10,11 +10,13 @@ package nfregistration
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/omec-project/nssf/consumer"
 	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/sbi"
 	"github.com/omec-project/openapi/v2/models"
 )
 
@@ -77,6 +79,7 @@ var registerNF = func(registerCtx context.Context, newPlmnConfig []models.PlmnId
 	registerCtxMutex.Lock()
 	defer registerCtxMutex.Unlock()
 	interval := 0 * time.Millisecond
+	recordRegistrationAttempt(newPlmnConfig)
 	for {
 		select {
 		case <-registerCtx.Done():
@@ -86,11 +89,14 @@ var registerNF = func(registerCtx context.Context, newPlmnConfig []models.PlmnId
 			nfProfile, _, err := consumer.SendRegisterNFInstance(newPlmnConfig)
 			if err != nil {
 				logger.NrfRegistrationLog.Errorln("register NSSF instance to NRF failed. Will retry.", err.Error())
+				recordRegistrationError(err)
 				interval = retryTime
 				continue
 			}
 			logger.NrfRegistrationLog.Infoln("register NSSF instance to NRF with updated profile succeeded")
+			recordRegistered(nfProfile, newPlmnConfig)
 			startKeepAliveTimer(nfProfile.GetHeartBeatTimer(), newPlmnConfig)
+			subscribeAmfStatus()
 			return
 		}
 	}
@@ -108,27 +114,55 @@ func heartbeatNF(plmnConfig []models.PlmnId) {
 	}
 	keepAliveTimerMutex.Unlock()
 
-	patchItem := []models.PatchItem{
-		{
-			Op:    models.PATCHOPERATION_REPLACE,
-			Path:  "/nfStatus",
-			Value: models.NFSTATUS_REGISTERED,
-		},
+	var nfProfile *models.NFProfile
+	var problemDetails *models.ProblemDetails
+	var err error
+	if nrfChanged() {
+		err = fmt.Errorf("NRF changed from %s to %s", GetRegistrationState().NrfUri, currentNrfUri())
+	} else {
+		patchItem := []models.PatchItem{
+			{
+				Op:    models.PATCHOPERATION_REPLACE,
+				Path:  "/nfStatus",
+				Value: models.NFSTATUS_REGISTERED,
+			},
+		}
+		if load, enabled := sbi.CurrentLoad(); enabled {
+			// The load may be absent from the profile at the NRF, "add" replaces it otherwise
+			patchItem = append(patchItem, models.PatchItem{
+				Op:    models.PATCHOPERATION_ADD,
+				Path:  "/load",
+				Value: load,
+			})
+		}
+		nfProfile, problemDetails, err = consumer.SendUpdateNFInstance(patchItem)
 	}
-	nfProfile, problemDetails, err := consumer.SendUpdateNFInstance(patchItem)
 
 	if shouldRegister(problemDetails, err) {
 		logger.NrfRegistrationLog.Debugln("NF heartbeat failed. Trying to register again")
 		nfProfile, _, err = consumer.SendRegisterNFInstance(plmnConfig)
 		if err != nil {
 			logger.NrfRegistrationLog.Errorln("register NSSF instance error:", err.Error())
+			recordRegistrationError(err)
 		} else {
 			logger.NrfRegistrationLog.Infoln("register NSSF instance to NRF with updated profile succeeded")
+			recordRegistered(nfProfile, plmnConfig)
 		}
 	} else {
 		logger.NrfRegistrationLog.Debugln("NSSF update NF instance (heartbeat) succeeded")
+		recordHeartbeat()
 	}
 	startKeepAliveTimer(nfProfile.GetHeartBeatTimer(), plmnConfig)
+	if err == nil {
+		subscribeAmfStatus()
+	}
+}
+
+// nrfChanged reports whether requests are no longer sent to the NRF at which the NSSF registered,
+// e.g. after a failover, so that the NSSF registers at the new NRF
+func nrfChanged() bool {
+	registeredNrfUri := GetRegistrationState().NrfUri
+	return registeredNrfUri != "" && registeredNrfUri != currentNrfUri()
 }
 
 func shouldRegister(problemDetails *models.ProblemDetails, err error) bool {
@@ -147,12 +181,15 @@ var DeregisterNF = func() {
 	keepAliveTimerMutex.Lock()
 	stopKeepAliveTimer()
 	keepAliveTimerMutex.Unlock()
+	unsubscribeAmfStatus()
 	err := consumer.SendDeregisterNFInstance()
 	if err != nil {
 		logger.NrfRegistrationLog.Warnln("deregister instance from NRF error:", err.Error())
+		recordRegistrationError(err)
 		return
 	}
 	logger.NrfRegistrationLog.Infoln("deregister instance from NRF successful")
+	recordDeregistered()
 }
 
 func startKeepAliveTimer(profileHeartbeatTimer int32, plmnConfig []models.PlmnId) {
@@ -18,6 +18,9 @@ import (
 	"time"
 
 	"github.com/omec-project/nssf/consumer"
+	nssfContext "github.com/omec-project/nssf/context"
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/sbi"
 	"github.com/omec-project/openapi/v2/models"
 )
 
@@ -409,6 +412,70 @@ func TestHeartbeatNF_WhenNfUpdateFails_ThenNfRegistersIsCalled(t *testing.T) {
 	}
 }
 
+func TestHeartbeatNF_WhenNrfChanged_ThenNfRegistersAtNewNrf(t *testing.T) {
+	keepAliveTimer = time.NewTimer(60 * time.Second)
+	nssfSelf := nssfContext.NSSF_Self()
+	originalNrfUriList := nssfSelf.NrfUriList
+	originalSendRegisterNFInstance := consumer.SendRegisterNFInstance
+	originalSendUpdateNFInstance := consumer.SendUpdateNFInstance
+	defer func() {
+		nssfSelf.NrfUriList = originalNrfUriList
+		updateRegistrationState(func(state *RegistrationState) { *state = RegistrationState{Status: StatusNotRegistered} })
+		consumer.SendRegisterNFInstance = originalSendRegisterNFInstance
+		consumer.SendUpdateNFInstance = originalSendUpdateNFInstance
+		if keepAliveTimer != nil {
+			keepAliveTimer.Stop()
+		}
+	}()
+
+	nssfSelf.NrfUriList = []string{"http://nrf-1:29510", "http://nrf-2:29510"}
+	recordRegistered(models.NewNFProfileWithDefaults(), nil)
+	// The NRF of the registration failed, the other NRF is used instead
+	nssfSelf.NrfUriList = []string{"http://nrf-2:29510"}
+
+	calledUpdate, calledRegister := false, false
+	consumer.SendUpdateNFInstance = func(patchItem []models.PatchItem) (*models.NFProfile, *models.ProblemDetails, error) {
+		calledUpdate = true
+		return &models.NFProfile{}, nil, nil
+	}
+	consumer.SendRegisterNFInstance = func(plmnConfig []models.PlmnId) (*models.NFProfile, string, error) {
+		calledRegister = true
+		return models.NewNFProfileWithDefaults(), "", nil
+	}
+	heartbeatNF(nil)
+
+	if calledUpdate || !calledRegister {
+		t.Errorf("expected the NSSF to register at the new NRF instead of sending a heartbeat")
+	}
+	if nrfUri := GetRegistrationState().NrfUri; nrfUri != "http://nrf-2:29510" {
+		t.Errorf("expected the NSSF to be registered at the new NRF, got %s", nrfUri)
+	}
+}
+
+func TestHeartbeatNF_WhenLoadControlEnabled_ThenLoadIsReported(t *testing.T) {
+	keepAliveTimer = time.NewTimer(60 * time.Second)
+	originalSendUpdateNFInstance := consumer.SendUpdateNFInstance
+	sbi.LoadControl(&factory.LoadControlConfig{Capacity: 100})
+	defer func() {
+		consumer.SendUpdateNFInstance = originalSendUpdateNFInstance
+		sbi.LoadControl(nil)
+		if keepAliveTimer != nil {
+			keepAliveTimer.Stop()
+		}
+	}()
+
+	var sentPatchItems []models.PatchItem
+	consumer.SendUpdateNFInstance = func(patchItem []models.PatchItem) (*models.NFProfile, *models.ProblemDetails, error) {
+		sentPatchItems = patchItem
+		return &models.NFProfile{}, nil, nil
+	}
+	heartbeatNF(nil)
+
+	if len(sentPatchItems) != 2 || sentPatchItems[1].Path != "/load" || sentPatchItems[1].Value != int32(0) {
+		t.Errorf("expected the load to be reported in the heartbeat, got %+v", sentPatchItems)
+	}
+}
+
 func TestHeartbeatNF_UsesDefaultTimerWhenUpdateReturnsNilProfile(t *testing.T) {
 	keepAliveTimer = time.NewTimer(60 * time.Second)
 	originalSendUpdateNFInstance := consumer.SendUpdateNFInstance
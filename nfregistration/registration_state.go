@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package nfregistration
+
+import (
+	"sync"
+	"time"
+
+	"github.com/omec-project/nssf/consumer"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// Registration status of the NSSF at the NRF
+const (
+	StatusNotRegistered = "NOT_REGISTERED"
+	StatusRegistering   = "REGISTERING"
+	StatusRegistered    = "REGISTERED"
+	StatusDeregistered  = "DEREGISTERED"
+)
+
+// RegistrationState is what the NSSF currently believes about its registration at the NRF
+type RegistrationState struct {
+	Status           string          `json:"status"`
+	NfInstanceId     string          `json:"nfInstanceId,omitempty"`
+	NrfUri           string          `json:"nrfUri,omitempty"`
+	PlmnList         []models.PlmnId `json:"plmnList,omitempty"`
+	HeartBeatTimer   int32           `json:"heartBeatTimer,omitempty"`
+	LastRegistration *time.Time      `json:"lastRegistration,omitempty"`
+	LastHeartbeat    *time.Time      `json:"lastHeartbeat,omitempty"`
+	LastError        string          `json:"lastError,omitempty"`
+	LastErrorTime    *time.Time      `json:"lastErrorTime,omitempty"`
+}
+
+var (
+	registrationState      = RegistrationState{Status: StatusNotRegistered}
+	registrationStateMutex sync.RWMutex
+)
+
+// GetRegistrationState returns a copy of the current NRF registration state
+func GetRegistrationState() RegistrationState {
+	registrationStateMutex.RLock()
+	defer registrationStateMutex.RUnlock()
+	state := registrationState
+	state.PlmnList = append([]models.PlmnId(nil), registrationState.PlmnList...)
+	return state
+}
+
+func updateRegistrationState(update func(state *RegistrationState)) {
+	registrationStateMutex.Lock()
+	defer registrationStateMutex.Unlock()
+	update(&registrationState)
+}
+
+func recordRegistrationAttempt(plmnConfig []models.PlmnId) {
+	updateRegistrationState(func(state *RegistrationState) {
+		if state.Status != StatusRegistered {
+			state.Status = StatusRegistering
+		}
+		state.PlmnList = append([]models.PlmnId(nil), plmnConfig...)
+	})
+}
+
+func recordRegistered(nfProfile *models.NFProfile, plmnConfig []models.PlmnId) {
+	now := time.Now()
+	updateRegistrationState(func(state *RegistrationState) {
+		state.Status = StatusRegistered
+		state.NfInstanceId = nfProfile.GetNfInstanceId()
+		state.NrfUri = currentNrfUri()
+		state.PlmnList = append([]models.PlmnId(nil), plmnConfig...)
+		state.HeartBeatTimer = nfProfile.GetHeartBeatTimer()
+		state.LastRegistration = &now
+		state.LastHeartbeat = &now
+	})
+}
+
+func recordHeartbeat() {
+	now := time.Now()
+	updateRegistrationState(func(state *RegistrationState) {
+		state.Status = StatusRegistered
+		state.LastHeartbeat = &now
+	})
+}
+
+func recordDeregistered() {
+	updateRegistrationState(func(state *RegistrationState) {
+		state.Status = StatusDeregistered
+		state.HeartBeatTimer = 0
+	})
+}
+
+func recordRegistrationError(err error) {
+	now := time.Now()
+	updateRegistrationState(func(state *RegistrationState) {
+		if state.Status == StatusRegistered {
+			state.Status = StatusRegistering
+		}
+		state.LastError = err.Error()
+		state.LastErrorTime = &now
+	})
+}
+
+func currentNrfUri() string {
+	return consumer.NrfUri()
+}
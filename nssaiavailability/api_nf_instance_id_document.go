@@ -25,6 +25,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/omec-project/nssf/logger"
 	"github.com/omec-project/nssf/producer"
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/nssf/validation"
 	"github.com/omec-project/openapi/v2"
 	"github.com/omec-project/openapi/v2/models"
 	"github.com/omec-project/openapi/v2/utils"
@@ -40,6 +42,11 @@ func HTTPNSSAIAvailabilityDelete(c *gin.Context) {
 
 	rsp := producer.HandleNSSAIAvailabilityDelete(req)
 
+	for key, values := range rsp.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
 	responseBody, err := openapi.SetBody(rsp.Body, "application/json")
 	if err != nil {
 		logger.HandlerLog.Errorln(err)
@@ -58,9 +65,16 @@ func HTTPNSSAIAvailabilityPatch(c *gin.Context) {
 
 	requestBody, err := c.GetRawData()
 	if err != nil {
-		problemDetail := utils.ProblemDetailsSystemFailure(err.Error())
+		problemDetail := sbi.ProblemDetailsRequestBody(err)
 		logger.HandlerLog.Errorf("Get Request Body error: %+v", err)
-		c.JSON(http.StatusInternalServerError, problemDetail)
+		c.JSON(int(problemDetail.GetStatus()), problemDetail)
+		return
+	}
+
+	if invalidParams := validation.ValidateBody(requestBody, validation.PatchDocument); len(invalidParams) != 0 {
+		rsp := validation.ProblemDetailsInvalidBody(invalidParams)
+		logger.HandlerLog.Errorf("invalid request body: %+v", invalidParams)
+		c.JSON(http.StatusBadRequest, rsp)
 		return
 	}
 
@@ -78,6 +92,11 @@ func HTTPNSSAIAvailabilityPatch(c *gin.Context) {
 
 	rsp := producer.HandleNSSAIAvailabilityPatch(req)
 
+	for key, values := range rsp.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
 	responseBody, err := openapi.SetBody(rsp.Body, "application/json")
 	if err != nil {
 		logger.HandlerLog.Errorln(err)
@@ -96,9 +115,16 @@ func HTTPNSSAIAvailabilityPut(c *gin.Context) {
 
 	requestBody, err := c.GetRawData()
 	if err != nil {
-		problemDetail := utils.ProblemDetailsSystemFailure(err.Error())
+		problemDetail := sbi.ProblemDetailsRequestBody(err)
 		logger.HandlerLog.Errorf("Get Request Body error: %+v", err)
-		c.JSON(http.StatusInternalServerError, problemDetail)
+		c.JSON(int(problemDetail.GetStatus()), problemDetail)
+		return
+	}
+
+	if invalidParams := validation.ValidateBody(requestBody, validation.NssaiAvailabilityInfo); len(invalidParams) != 0 {
+		rsp := validation.ProblemDetailsInvalidBody(invalidParams)
+		logger.HandlerLog.Errorf("invalid request body: %+v", invalidParams)
+		c.JSON(http.StatusBadRequest, rsp)
 		return
 	}
 
@@ -116,6 +142,11 @@ func HTTPNSSAIAvailabilityPut(c *gin.Context) {
 
 	rsp := producer.HandleNSSAIAvailabilityPut(req)
 
+	for key, values := range rsp.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
 	responseBody, err := openapi.SetBody(rsp.Body, "application/json")
 	if err != nil {
 		logger.HandlerLog.Errorln(err)
@@ -19,14 +19,35 @@ Generated by: OpenAPI Generator (https://openapi-generator.tech)
 package nssaiavailability
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/producer"
+	"github.com/omec-project/openapi/v2"
+	"github.com/omec-project/openapi/v2/utils"
+	"github.com/omec-project/util/httpwrapper"
 )
 
 // Options /nssai-availability
 // Discover communication options supported by NSSF for NSSAI Availability
 func HTTPNSSAIAvailabilityOptions(c *gin.Context) {
-	detail := "Handle Options /nssai-availability is not implemented"
-	logger.Nssaiavailability.Warnln(detail)
-	writeNotImplementedProblem(c, detail)
+	logger.Nssaiavailability.Infoln("Handle Options /nssai-availability")
+	req := httpwrapper.NewRequest(c.Request, nil)
+
+	rsp := producer.HandleNSSAIAvailabilityOptions(req)
+
+	for key, values := range rsp.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
+	responseBody, err := openapi.SetBody(rsp.Body, "application/json")
+	if err != nil {
+		logger.HandlerLog.Errorln(err)
+		problemDetails := utils.ProblemDetailsSystemFailure(err.Error())
+		c.JSON(http.StatusInternalServerError, problemDetails)
+	} else {
+		c.Data(rsp.Status, "application/json", responseBody.Bytes())
+	}
 }
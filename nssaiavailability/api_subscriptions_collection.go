@@ -25,6 +25,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/omec-project/nssf/logger"
 	"github.com/omec-project/nssf/producer"
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/nssf/validation"
 	"github.com/omec-project/openapi/v2"
 	"github.com/omec-project/openapi/v2/models"
 	"github.com/omec-project/openapi/v2/utils"
@@ -39,9 +41,16 @@ func HTTPNSSAIAvailabilityPost(c *gin.Context) {
 
 	requestBody, err := c.GetRawData()
 	if err != nil {
-		problemDetail := utils.ProblemDetailsSystemFailure(err.Error())
+		problemDetail := sbi.ProblemDetailsRequestBody(err)
 		logger.HandlerLog.Errorf("get Request Body error: %+v", err)
-		c.JSON(http.StatusInternalServerError, problemDetail)
+		c.JSON(int(problemDetail.GetStatus()), problemDetail)
+		return
+	}
+
+	if invalidParams := validation.ValidateBody(requestBody, validation.NssfEventSubscriptionCreateData); len(invalidParams) != 0 {
+		rsp := validation.ProblemDetailsInvalidBody(invalidParams)
+		logger.HandlerLog.Errorf("invalid request body: %+v", invalidParams)
+		c.JSON(http.StatusBadRequest, rsp)
 		return
 	}
 
@@ -38,6 +38,11 @@ func HTTPNSSelectionGet(c *gin.Context) {
 
 	rsp := producer.HandleNSSelectionGet(req)
 
+	for key, values := range rsp.Header {
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
 	responseBody, err := openapi.SetBody(rsp.Body, "application/json")
 	if err != nil {
 		logger.HandlerLog.Errorln(err)
@@ -31,7 +31,6 @@ const (
 type nfConfigPoller struct {
 	plmnConfigChan          chan<- []models.PlmnId
 	currentPlmnSnssaiConfig []nfConfigApi.PlmnSnssai
-	currentPlmnConfig       []models.PlmnId
 	client                  *http.Client
 }
 
@@ -41,7 +40,6 @@ func StartPollingService(ctx context.Context, webuiUri string, plmnConfigChan ch
 	poller := nfConfigPoller{
 		plmnConfigChan:          plmnConfigChan,
 		currentPlmnSnssaiConfig: []nfConfigApi.PlmnSnssai{},
-		currentPlmnConfig:       []models.PlmnId{},
 		client:                  &http.Client{Timeout: initialPollingInterval},
 	}
 	interval := initialPollingInterval
@@ -110,6 +108,8 @@ func (p *nfConfigPoller) fetchPlmnConfig(pollingEndpoint string) ([]nfConfigApi.
 	}
 }
 
+// handlePolledPlmnSnssaiConfig applies the polled supported NSSAI, except for the PLMNs provisioned through
+// the admin API, which take precedence, and updates the NF registration if the supported PLMNs changed
 func (p *nfConfigPoller) handlePolledPlmnSnssaiConfig(newPlmnSnssaiConfig []nfConfigApi.PlmnSnssai) {
 	if reflect.DeepEqual(p.currentPlmnSnssaiConfig, newPlmnSnssaiConfig) {
 		logger.PollConfigLog.Debugf("PLMN-SNSSAI config did not change %+v", p.currentPlmnSnssaiConfig)
@@ -119,14 +119,16 @@ func (p *nfConfigPoller) handlePolledPlmnSnssaiConfig(newPlmnSnssaiConfig []nfCo
 	defer factory.ConfigLock.Unlock()
 	p.currentPlmnSnssaiConfig = newPlmnSnssaiConfig
 	logger.PollConfigLog.Infof("PLMN-SNSSAI config changed. New PLMN-SNSSAI config: %+v", p.currentPlmnSnssaiConfig)
-	newPlmnConfig, newSupportedNssai := convertPlmnSnssaiList(p.currentPlmnSnssaiConfig)
-
-	if !reflect.DeepEqual(p.currentPlmnConfig, newPlmnConfig) {
-		logger.PollConfigLog.Debugf("PLMN config changed %+v. Updating NF registration", newPlmnConfig)
-		p.currentPlmnConfig = newPlmnConfig
-		p.plmnConfigChan <- p.currentPlmnConfig
+	_, newSupportedNssai := convertPlmnSnssaiList(p.currentPlmnSnssaiConfig)
+
+	configuration := factory.NssfConfig.Configuration
+	configuration.PolledSupportedNssaiInPlmnList = newSupportedNssai
+	configuration.MergeSupportedNssaiInPlmnList()
+	if configuration.UpdatePlmnList() {
+		logger.PollConfigLog.Debugf("PLMN config changed %+v. Updating NF registration", configuration.PlmnList)
+		p.plmnConfigChan <- configuration.PlmnList
 	}
-	factory.NssfConfig.Configuration.SupportedNssaiInPlmnList = newSupportedNssai
+	factory.PublishSnapshotLocked()
 }
 
 func convertPlmnSnssaiList(newConfig []nfConfigApi.PlmnSnssai) ([]models.PlmnId, factory.SupportedNssaiInPlmn) {
@@ -213,13 +213,13 @@ func TestHandlePolledPlmnSnssaiConfig_ExpectChannelNotToBeUpdated(t *testing.T)
 			factory.NssfConfig = factory.Config{
 				Configuration: &factory.Configuration{
 					SupportedNssaiInPlmnList: tc.initialSupportedNssaiConfig,
+					PlmnList:                 tc.initialPlmnConfig,
 				},
 			}
 
 			ch := make(chan []models.PlmnId, 1)
 			poller := nfConfigPoller{
 				currentPlmnSnssaiConfig: tc.initialPlmnSnssaiConfig,
-				currentPlmnConfig:       tc.initialPlmnConfig,
 				plmnConfigChan:          ch,
 			}
 
@@ -232,9 +232,9 @@ func TestHandlePolledPlmnSnssaiConfig_ExpectChannelNotToBeUpdated(t *testing.T)
 				// Expected
 			}
 
-			if !reflect.DeepEqual(poller.currentPlmnConfig, tc.expectedCurrentPlmnConfig) {
+			if !reflect.DeepEqual(factory.NssfConfig.Configuration.PlmnList, tc.expectedCurrentPlmnConfig) {
 				t.Errorf("Expected current PLMN config: %+v, got: %+v",
-					tc.expectedCurrentPlmnConfig, poller.currentPlmnConfig)
+					tc.expectedCurrentPlmnConfig, factory.NssfConfig.Configuration.PlmnList)
 			}
 
 			if len(factory.NssfConfig.Configuration.SupportedNssaiInPlmnList) != tc.expectedSupportedNssaiCount {
@@ -297,12 +297,12 @@ func TestHandlePolledPlmnSnssaiConfig_ExpectChannelUpdate(t *testing.T) {
 			factory.NssfConfig = factory.Config{
 				Configuration: &factory.Configuration{
 					SupportedNssaiInPlmnList: make(factory.SupportedNssaiInPlmn),
+					PlmnList:                 tc.initialPlmnConfig,
 				},
 			}
 
 			poller := nfConfigPoller{
 				currentPlmnSnssaiConfig: tc.initialPlmnSnssaiConfig,
-				currentPlmnConfig:       tc.initialPlmnConfig,
 				plmnConfigChan:          ch,
 			}
 
@@ -317,9 +317,9 @@ func TestHandlePolledPlmnSnssaiConfig_ExpectChannelUpdate(t *testing.T) {
 				t.Error("Expected update to be sent to channel but none received")
 			}
 
-			if !reflect.DeepEqual(poller.currentPlmnConfig, tc.expectedCurrentPlmnConfig) {
+			if !reflect.DeepEqual(factory.NssfConfig.Configuration.PlmnList, tc.expectedCurrentPlmnConfig) {
 				t.Errorf("Expected current PLMN config: %+v, got: %+v",
-					tc.expectedCurrentPlmnConfig, poller.currentPlmnConfig)
+					tc.expectedCurrentPlmnConfig, factory.NssfConfig.Configuration.PlmnList)
 			}
 
 			if len(factory.NssfConfig.Configuration.SupportedNssaiInPlmnList) != tc.expectedSupportedNssaiCount {
@@ -331,6 +331,48 @@ func TestHandlePolledPlmnSnssaiConfig_ExpectChannelUpdate(t *testing.T) {
 	}
 }
 
+func TestHandlePolledPlmnSnssaiConfig_ProvisionedEntriesTakePrecedence(t *testing.T) {
+	originalFactoryConfig := factory.NssfConfig
+	defer func() {
+		factory.NssfConfig = originalFactoryConfig
+	}()
+	provisionedPlmnId := models.PlmnId{Mcc: "001", Mnc: "01"}
+	factory.NssfConfig = factory.Config{
+		Configuration: &factory.Configuration{
+			ProvisionedSupportedNssaiInPlmnList: []factory.SupportedNssaiInPlmnEntry{
+				{PlmnId: provisionedPlmnId, SNssaiList: []models.Snssai{{Sst: 3}}},
+			},
+		},
+	}
+
+	ch := make(chan []models.PlmnId, 1)
+	poller := nfConfigPoller{
+		currentPlmnSnssaiConfig: []nfConfigApi.PlmnSnssai{},
+		plmnConfigChan:          ch,
+	}
+	poller.handlePolledPlmnSnssaiConfig([]nfConfigApi.PlmnSnssai{
+		{PlmnId: nfConfigApi.PlmnId{Mcc: "001", Mnc: "01"}, SNssaiList: []nfConfigApi.Snssai{{Sst: 1}}},
+		{PlmnId: nfConfigApi.PlmnId{Mcc: "002", Mnc: "02"}, SNssaiList: []nfConfigApi.Snssai{{Sst: 2}}},
+	})
+
+	expectedSupportedNssai := factory.SupportedNssaiInPlmn{
+		provisionedPlmnId:       {{Sst: 3}: struct{}{}},
+		{Mcc: "002", Mnc: "02"}: {{Sst: 2}: struct{}{}},
+	}
+	if !reflect.DeepEqual(factory.NssfConfig.Configuration.SupportedNssaiInPlmnList, expectedSupportedNssai) {
+		t.Errorf("Expected %+v, got %+v", expectedSupportedNssai, factory.NssfConfig.Configuration.SupportedNssaiInPlmnList)
+	}
+	select {
+	case updated := <-ch:
+		expectedPlmnConfig := []models.PlmnId{provisionedPlmnId, {Mcc: "002", Mnc: "02"}}
+		if !reflect.DeepEqual(updated, expectedPlmnConfig) {
+			t.Errorf("Wrong config sent on channel.\nExpected: %+v\nGot: %+v", expectedPlmnConfig, updated)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected update to be sent to channel but none received")
+	}
+}
+
 func TestConvertPlmnSnssaiList(t *testing.T) {
 	sdPtr := openapi.PtrString("010203")
 
@@ -520,7 +562,6 @@ func TestFetchPlmnConfig(t *testing.T) {
 			ch := make(chan []models.PlmnId, 1)
 			poller := nfConfigPoller{
 				currentPlmnSnssaiConfig: []nfConfigApi.PlmnSnssai{},
-				currentPlmnConfig:       []models.PlmnId{{Mcc: "001", Mnc: "01"}},
 				plmnConfigChan:          ch,
 				client:                  &http.Client{},
 			}
@@ -0,0 +1,374 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Admin
+ *
+ * Operator management of the slice configuration tables
+ */
+
+package producer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/openapi/v2"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/openapi/v2/utils"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+// Names of the configuration tables exposed by the admin API
+const (
+	AdminTableNsiList                  = "nsi-list"
+	AdminTableTaList                   = "ta-list"
+	AdminTableAmfSetList               = "amf-set-list"
+	AdminTableMappingListFromPlmn      = "mapping-list-from-plmn"
+	AdminTableSupportedNssaiInPlmnList = "supported-nssai-in-plmn-list"
+	AdminTableNsagList                 = "nsag-list"
+)
+
+// configTable is a keyed list in factory.Configuration that can be managed through the admin API
+type configTable[T any] struct {
+	// keyOf returns the identifier of an entry as used in the resource URI
+	keyOf func(T) string
+	// normalizeKey parses an identifier taken from the resource URI
+	normalizeKey func(string) (string, error)
+	validate     func(T) error
+	load         func(*factory.Configuration) []T
+	store        func(*factory.Configuration, []T)
+	// applied, if not nil, is called with ConfigLock held once a new table is in effect
+	applied func(*factory.Configuration)
+}
+
+// PlmnListChanged is called with the PLMNs of the supported NSSAI in PLMN when a change to the entries
+// provisioned through the admin API changes them, so that the registration of the NSSF at the NRF is updated
+var PlmnListChanged func(plmnList []models.PlmnId)
+
+// adminTable hides the entry type of a configTable from the handlers
+type adminTable interface {
+	list() any
+	get(key string) (any, *models.ProblemDetails)
+	create(body []byte) (string, any, *models.ProblemDetails)
+	replace(key string, body []byte) (any, *models.ProblemDetails)
+	remove(key string) *models.ProblemDetails
+}
+
+var adminTables = map[string]adminTable{
+	AdminTableNsiList: &configTable[factory.NsiConfig]{
+		keyOf: func(nsiConfig factory.NsiConfig) string {
+			return factory.SnssaiKeyString(factory.SnssaiToKey(*nsiConfig.Snssai))
+		},
+		normalizeKey: func(key string) (string, error) {
+			snssai, err := factory.ParseSnssaiKeyString(key)
+			if err != nil {
+				return "", err
+			}
+			return factory.SnssaiKeyString(factory.SnssaiToKey(snssai)), nil
+		},
+		validate: factory.ValidateNsiConfig,
+		load:     func(c *factory.Configuration) []factory.NsiConfig { return c.NsiList },
+		store:    func(c *factory.Configuration, l []factory.NsiConfig) { c.NsiList = l },
+	},
+	AdminTableTaList: &configTable[factory.TaConfig]{
+		keyOf: factory.TaConfigKey,
+		normalizeKey: func(key string) (string, error) {
+			if tai, err := factory.ParseTaiString(key); err == nil {
+				return factory.TaiString(tai), nil
+			}
+			if plmnId, err := factory.ParsePlmnIdString(key); err == nil {
+				return factory.PlmnIdString(plmnId), nil
+			}
+			return "", fmt.Errorf("invalid TA list entry %q, expected mcc-mnc-tac, mcc-mnc or mcc-mnc-<TAC ranges>", key)
+		},
+		validate: factory.ValidateTaConfig,
+		load:     func(c *factory.Configuration) []factory.TaConfig { return c.TaList },
+		store:    func(c *factory.Configuration, l []factory.TaConfig) { c.TaList = l },
+	},
+	AdminTableAmfSetList: &configTable[factory.AmfSetConfig]{
+		keyOf:        func(amfSetConfig factory.AmfSetConfig) string { return amfSetConfig.AmfSetId },
+		normalizeKey: func(key string) (string, error) { return key, nil },
+		validate:     factory.ValidateAmfSetConfig,
+		load:         func(c *factory.Configuration) []factory.AmfSetConfig { return c.AmfSetList },
+		store:        func(c *factory.Configuration, l []factory.AmfSetConfig) { c.AmfSetList = l },
+	},
+	AdminTableMappingListFromPlmn: &configTable[factory.MappingFromPlmnConfig]{
+		keyOf: func(mappingFromPlmn factory.MappingFromPlmnConfig) string {
+			return factory.PlmnIdString(*mappingFromPlmn.HomePlmnId)
+		},
+		normalizeKey: normalizePlmnIdKey,
+		validate:     factory.ValidateMappingFromPlmnConfig,
+		load:         func(c *factory.Configuration) []factory.MappingFromPlmnConfig { return c.MappingListFromPlmn },
+		store:        func(c *factory.Configuration, l []factory.MappingFromPlmnConfig) { c.MappingListFromPlmn = l },
+	},
+	// The entries provisioned through the admin API take precedence, per PLMN, over the ones polled from the webconsole
+	AdminTableSupportedNssaiInPlmnList: &configTable[factory.SupportedNssaiInPlmnEntry]{
+		keyOf:        func(entry factory.SupportedNssaiInPlmnEntry) string { return factory.PlmnIdString(entry.PlmnId) },
+		normalizeKey: normalizePlmnIdKey,
+		validate:     factory.ValidateSupportedNssaiInPlmnEntry,
+		load: func(c *factory.Configuration) []factory.SupportedNssaiInPlmnEntry {
+			return c.ProvisionedSupportedNssaiInPlmnList
+		},
+		store: func(c *factory.Configuration, l []factory.SupportedNssaiInPlmnEntry) {
+			c.ProvisionedSupportedNssaiInPlmnList = l
+			c.MergeSupportedNssaiInPlmnList()
+		},
+		applied: func(c *factory.Configuration) {
+			if c.UpdatePlmnList() && PlmnListChanged != nil {
+				PlmnListChanged(c.PlmnList)
+			}
+		},
+	},
+	AdminTableNsagList: &configTable[factory.NsagConfig]{
+		keyOf: func(nsagConfig factory.NsagConfig) string { return strconv.Itoa(int(nsagConfig.NsagId)) },
+		normalizeKey: func(key string) (string, error) {
+			nsagId, err := strconv.ParseInt(key, 10, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid NSAG ID %q", key)
+			}
+			return strconv.Itoa(int(nsagId)), nil
+		},
+		validate: factory.ValidateNsagConfig,
+		load:     func(c *factory.Configuration) []factory.NsagConfig { return c.NsagList },
+		store:    func(c *factory.Configuration, l []factory.NsagConfig) { c.NsagList = l },
+	},
+}
+
+func normalizePlmnIdKey(key string) (string, error) {
+	plmnId, err := factory.ParsePlmnIdString(key)
+	if err != nil {
+		return "", err
+	}
+	return factory.PlmnIdString(plmnId), nil
+}
+
+func (t *configTable[T]) list() any {
+	factory.ConfigLock.RLock()
+	defer factory.ConfigLock.RUnlock()
+	entries := t.load(factory.NssfConfig.Configuration)
+	return append(make([]T, 0, len(entries)), entries...)
+}
+
+func (t *configTable[T]) indexLocked(entries []T, key string) int {
+	for i, entry := range entries {
+		if t.keyOf(entry) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *configTable[T]) get(key string) (any, *models.ProblemDetails) {
+	key, err := t.normalizeKey(key)
+	if err != nil {
+		return nil, utils.ProblemDetailsMalformedRequestSyntax(err.Error())
+	}
+	factory.ConfigLock.RLock()
+	defer factory.ConfigLock.RUnlock()
+	entries := t.load(factory.NssfConfig.Configuration)
+	idx := t.indexLocked(entries, key)
+	if idx < 0 {
+		return nil, adminEntryNotFound(key)
+	}
+	return entries[idx], nil
+}
+
+func (t *configTable[T]) decode(body []byte) (T, *models.ProblemDetails) {
+	var entry T
+	if err := openapi.Decode(&entry, body, "application/json"); err != nil {
+		return entry, utils.ProblemDetailsMalformedRequestSyntax("[Request Body] " + err.Error())
+	}
+	if err := t.validate(entry); err != nil {
+		return entry, adminValidationProblem(err)
+	}
+	return entry, nil
+}
+
+func (t *configTable[T]) create(body []byte) (string, any, *models.ProblemDetails) {
+	entry, problemDetails := t.decode(body)
+	if problemDetails != nil {
+		return "", nil, problemDetails
+	}
+	key := t.keyOf(entry)
+
+	factory.ConfigLock.Lock()
+	defer factory.ConfigLock.Unlock()
+	entries := t.load(factory.NssfConfig.Configuration)
+	if t.indexLocked(entries, key) >= 0 {
+		return "", nil, utils.ProblemDetails(util.INVALID_REQUEST, http.StatusConflict,
+			fmt.Sprintf("entry '%s' already exists", key))
+	}
+	newEntries := append(append(make([]T, 0, len(entries)+1), entries...), entry)
+	if problemDetails = t.commitLocked(newEntries); problemDetails != nil {
+		return "", nil, problemDetails
+	}
+	return key, entry, nil
+}
+
+func (t *configTable[T]) replace(key string, body []byte) (any, *models.ProblemDetails) {
+	key, err := t.normalizeKey(key)
+	if err != nil {
+		return nil, utils.ProblemDetailsMalformedRequestSyntax(err.Error())
+	}
+	entry, problemDetails := t.decode(body)
+	if problemDetails != nil {
+		return nil, problemDetails
+	}
+	if t.keyOf(entry) != key {
+		return nil, utils.ProblemDetails(util.INVALID_REQUEST, http.StatusBadRequest,
+			fmt.Sprintf("entry identifier '%s' in the body does not match '%s' in the URI", t.keyOf(entry), key))
+	}
+
+	factory.ConfigLock.Lock()
+	defer factory.ConfigLock.Unlock()
+	entries := t.load(factory.NssfConfig.Configuration)
+	idx := t.indexLocked(entries, key)
+	if idx < 0 {
+		return nil, adminEntryNotFound(key)
+	}
+	newEntries := append(make([]T, 0, len(entries)), entries...)
+	newEntries[idx] = entry
+	if problemDetails = t.commitLocked(newEntries); problemDetails != nil {
+		return nil, problemDetails
+	}
+	return entry, nil
+}
+
+func (t *configTable[T]) remove(key string) *models.ProblemDetails {
+	key, err := t.normalizeKey(key)
+	if err != nil {
+		return utils.ProblemDetailsMalformedRequestSyntax(err.Error())
+	}
+
+	factory.ConfigLock.Lock()
+	defer factory.ConfigLock.Unlock()
+	entries := t.load(factory.NssfConfig.Configuration)
+	idx := t.indexLocked(entries, key)
+	if idx < 0 {
+		return adminEntryNotFound(key)
+	}
+	newEntries := append(append(make([]T, 0, len(entries)-1), entries[:idx]...), entries[idx+1:]...)
+	return t.commitLocked(newEntries)
+}
+
+// commitLocked persists the new table, if persistence is configured, and only then applies it,
+// so that the running configuration never diverges from what is on disk
+func (t *configTable[T]) commitLocked(newEntries []T) *models.ProblemDetails {
+	candidate := *factory.NssfConfig.Configuration
+	t.store(&candidate, newEntries)
+	if err := factory.PersistProvisionedTablesLocked(factory.ProvisionedTablesOf(&candidate)); err != nil {
+		logger.AdminLog.Errorf("failed to persist provisioned tables: %+v", err)
+		return utils.ProblemDetailsSystemFailure(err.Error())
+	}
+	t.store(factory.NssfConfig.Configuration, newEntries)
+	if t.applied != nil {
+		t.applied(factory.NssfConfig.Configuration)
+	}
+	factory.PublishSnapshotLocked()
+	return nil
+}
+
+func adminEntryNotFound(key string) *models.ProblemDetails {
+	problemDetails := utils.ProblemDetails(util.UNSUPPORTED_RESOURCE, http.StatusNotFound,
+		fmt.Sprintf("entry '%s' does not exist", key))
+	problemDetails.SetCause(utils.CauseDataNotFound)
+	return problemDetails
+}
+
+func adminValidationProblem(err error) *models.ProblemDetails {
+	var validationErr *factory.ValidationError
+	if !errors.As(err, &validationErr) {
+		return utils.ProblemDetailsMalformedRequestSyntax(err.Error())
+	}
+	invalidParams := make([]models.InvalidParam, 0, len(validationErr.Violations))
+	for _, violation := range validationErr.Violations {
+		invalidParam := models.NewInvalidParam(violation.Field)
+		invalidParam.SetReason(violation.Reason)
+		invalidParams = append(invalidParams, *invalidParam)
+	}
+	return utils.ProblemDetailsWithInvalidParams(util.INVALID_REQUEST, http.StatusBadRequest,
+		"configuration entry is invalid", invalidParams)
+}
+
+func lookupAdminTable(name string) (adminTable, *models.ProblemDetails) {
+	table, found := adminTables[name]
+	if !found {
+		return nil, utils.ProblemDetails(util.UNSUPPORTED_RESOURCE, http.StatusNotFound,
+			fmt.Sprintf("configuration table '%s' does not exist", name))
+	}
+	return table, nil
+}
+
+// HandleAdminConfigList - Lists all entries of a configuration table
+func HandleAdminConfigList(request *httpwrapper.Request) *httpwrapper.Response {
+	table, problemDetails := lookupAdminTable(request.Params["table"])
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	return httpwrapper.NewResponse(http.StatusOK, nil, table.list())
+}
+
+// HandleAdminConfigGet - Reads one entry of a configuration table
+func HandleAdminConfigGet(request *httpwrapper.Request) *httpwrapper.Response {
+	table, problemDetails := lookupAdminTable(request.Params["table"])
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	entry, problemDetails := table.get(request.Params["key"])
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	return httpwrapper.NewResponse(http.StatusOK, nil, entry)
+}
+
+// HandleAdminConfigCreate - Adds a new entry to a configuration table
+func HandleAdminConfigCreate(request *httpwrapper.Request) *httpwrapper.Response {
+	tableName := request.Params["table"]
+	table, problemDetails := lookupAdminTable(tableName)
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	key, entry, problemDetails := table.create(request.Body.([]byte))
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	logger.AdminLog.Infof("created %s entry %s", tableName, key)
+	header := http.Header{}
+	header.Set("Location", request.URL.Path+"/"+key)
+	return httpwrapper.NewResponse(http.StatusCreated, header, entry)
+}
+
+// HandleAdminConfigReplace - Replaces an existing entry of a configuration table
+func HandleAdminConfigReplace(request *httpwrapper.Request) *httpwrapper.Response {
+	tableName := request.Params["table"]
+	table, problemDetails := lookupAdminTable(tableName)
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	entry, problemDetails := table.replace(request.Params["key"], request.Body.([]byte))
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	logger.AdminLog.Infof("replaced %s entry %s", tableName, request.Params["key"])
+	return httpwrapper.NewResponse(http.StatusOK, nil, entry)
+}
+
+// HandleAdminConfigDelete - Removes an entry from a configuration table
+func HandleAdminConfigDelete(request *httpwrapper.Request) *httpwrapper.Response {
+	tableName := request.Params["table"]
+	table, problemDetails := lookupAdminTable(tableName)
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	if problemDetails = table.remove(request.Params["key"]); problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	logger.AdminLog.Infof("deleted %s entry %s", tableName, request.Params["key"])
+	return httpwrapper.NewResponse(http.StatusNoContent, nil, nil)
+}
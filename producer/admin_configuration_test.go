@@ -0,0 +1,175 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/openapi/v2/models"
+	"go.yaml.in/yaml/v4"
+)
+
+func setAdminTestConfig(t *testing.T, configuration *factory.Configuration) {
+	t.Helper()
+	originalFactoryConfig := factory.NssfConfig
+	t.Cleanup(func() {
+		factory.NssfConfig = originalFactoryConfig
+		factory.PublishSnapshotLocked()
+	})
+	factory.NssfConfig = factory.Config{Configuration: configuration}
+	factory.PublishSnapshotLocked()
+}
+
+func TestAdminTaListCrud(t *testing.T) {
+	setAdminTestConfig(t, &factory.Configuration{})
+	table := adminTables[AdminTableTaList]
+
+	body := []byte(`{"tai":{"plmnId":{"mcc":"001","mnc":"01"},"tac":"000001"},"accessType":"3GPP_ACCESS",` +
+		`"supportedSnssaiList":[{"sst":1,"sd":"010203"}]}`)
+	key, _, problemDetails := table.create(body)
+	if problemDetails != nil {
+		t.Fatalf("create returned problem details: %+v", problemDetails)
+	}
+	if key != "001-01-000001" {
+		t.Fatalf("unexpected key %q", key)
+	}
+	if _, _, problemDetails = table.create(body); problemDetails == nil || problemDetails.GetStatus() != http.StatusConflict {
+		t.Fatalf("expected 409 on duplicate create, got %+v", problemDetails)
+	}
+
+	replaced := []byte(`{"tai":{"plmnId":{"mcc":"001","mnc":"01"},"tac":"000001"},"accessType":"NON_3GPP_ACCESS",` +
+		`"supportedSnssaiList":[{"sst":2}]}`)
+	if _, problemDetails = table.replace(key, replaced); problemDetails != nil {
+		t.Fatalf("replace returned problem details: %+v", problemDetails)
+	}
+	taList := factory.NssfConfig.Configuration.TaList
+	if len(taList) != 1 || *taList[0].AccessType != models.ACCESSTYPE_NON_3_GPP_ACCESS {
+		t.Fatalf("unexpected TA list after replace: %+v", taList)
+	}
+
+	if problemDetails = table.remove(key); problemDetails != nil {
+		t.Fatalf("remove returned problem details: %+v", problemDetails)
+	}
+	if len(factory.NssfConfig.Configuration.TaList) != 0 {
+		t.Fatalf("expected TA list to be empty, got %+v", factory.NssfConfig.Configuration.TaList)
+	}
+	if problemDetails = table.remove(key); problemDetails == nil || problemDetails.GetStatus() != http.StatusNotFound {
+		t.Fatalf("expected 404 on removing missing entry, got %+v", problemDetails)
+	}
+}
+
+func TestAdminCreateReportsEveryViolation(t *testing.T) {
+	setAdminTestConfig(t, &factory.Configuration{})
+	table := adminTables[AdminTableTaList]
+
+	body := []byte(`{"tai":{"plmnId":{"mcc":"1","mnc":"01"},"tac":"xyz"},` +
+		`"supportedSnssaiList":[{"sst":1,"sd":"12"}]}`)
+	_, _, problemDetails := table.create(body)
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %+v", problemDetails)
+	}
+	params := make([]string, 0, len(problemDetails.InvalidParams))
+	for _, invalidParam := range problemDetails.InvalidParams {
+		params = append(params, invalidParam.Param)
+	}
+	expected := []string{"tai.plmnId.mcc", "tai.tac", "accessType", "supportedSnssaiList[0].sd"}
+	if strings.Join(params, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected invalid params %v, got %v", expected, params)
+	}
+	if len(factory.NssfConfig.Configuration.TaList) != 0 {
+		t.Fatal("invalid entry must not be applied")
+	}
+}
+
+func TestAdminReplaceRejectsMismatchedKey(t *testing.T) {
+	setAdminTestConfig(t, &factory.Configuration{
+		AmfSetList: []factory.AmfSetConfig{{AmfSetId: "1"}},
+	})
+	table := adminTables[AdminTableAmfSetList]
+
+	_, problemDetails := table.replace("1", []byte(`{"amfSetId":"2"}`))
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %+v", problemDetails)
+	}
+}
+
+func TestAdminSupportedNssaiInPlmnListTakesPrecedenceOverWebconsole(t *testing.T) {
+	persistFile := filepath.Join(t.TempDir(), "provisioned.yaml")
+	polledPlmnId := models.PlmnId{Mcc: "001", Mnc: "01"}
+	polledSupportedNssai := factory.SupportedNssaiInPlmn{polledPlmnId: {factory.SnssaiKey{Sst: 1}: {}}}
+	setAdminTestConfig(t, &factory.Configuration{
+		SupportedNssaiInPlmnList:       polledSupportedNssai,
+		PolledSupportedNssaiInPlmnList: polledSupportedNssai,
+		PlmnList:                       []models.PlmnId{polledPlmnId},
+		Admin:                          &factory.Admin{PersistFile: persistFile},
+	})
+	var changedPlmnLists [][]models.PlmnId
+	originalPlmnListChanged := PlmnListChanged
+	t.Cleanup(func() { PlmnListChanged = originalPlmnListChanged })
+	PlmnListChanged = func(plmnList []models.PlmnId) { changedPlmnLists = append(changedPlmnLists, plmnList) }
+	table := adminTables[AdminTableSupportedNssaiInPlmnList]
+
+	// Overriding a polled PLMN does not change the PLMNs registered at the NRF
+	body := []byte(`{"plmnId":{"mcc":"001","mnc":"01"},"sNssaiList":[{"sst":1,"sd":"010203"},{"sst":2}]}`)
+	if _, _, problemDetails := table.create(body); problemDetails != nil {
+		t.Fatalf("create returned problem details: %+v", problemDetails)
+	}
+	if snssaiSet := factory.NssfConfig.Configuration.SupportedNssaiInPlmnList[polledPlmnId]; len(snssaiSet) != 2 {
+		t.Fatalf("expected the provisioned entry to replace the polled one, got %+v", snssaiSet)
+	}
+	if len(changedPlmnLists) != 0 {
+		t.Fatalf("expected the PLMN list to be unchanged, got %+v", changedPlmnLists)
+	}
+
+	body = []byte(`{"plmnId":{"mcc":"001","mnc":"02"},"sNssaiList":[{"sst":3}]}`)
+	if _, _, problemDetails := table.create(body); problemDetails != nil {
+		t.Fatalf("create returned problem details: %+v", problemDetails)
+	}
+	expectedPlmnList := []models.PlmnId{polledPlmnId, {Mcc: "001", Mnc: "02"}}
+	if len(changedPlmnLists) != 1 || len(changedPlmnLists[0]) != 2 ||
+		changedPlmnLists[0][0] != expectedPlmnList[0] || changedPlmnLists[0][1] != expectedPlmnList[1] {
+		t.Fatalf("expected the PLMN list %+v to be registered, got %+v", expectedPlmnList, changedPlmnLists)
+	}
+
+	content, err := os.ReadFile(persistFile)
+	if err != nil {
+		t.Fatalf("expected persist file to be written: %v", err)
+	}
+	var tables factory.ProvisionedTables
+	if err = yaml.Unmarshal(content, &tables); err != nil {
+		t.Fatalf("failed to parse persist file: %v", err)
+	}
+	if len(tables.SupportedNssaiInPlmnList) != 2 {
+		t.Fatalf("expected only the provisioned entries to be persisted, got %+v", tables.SupportedNssaiInPlmnList)
+	}
+
+	// Removing the override restores the polled entry
+	if problemDetails := table.remove("001-01"); problemDetails != nil {
+		t.Fatalf("remove returned problem details: %+v", problemDetails)
+	}
+	if snssaiSet := factory.NssfConfig.Configuration.SupportedNssaiInPlmnList[polledPlmnId]; len(snssaiSet) != 1 {
+		t.Fatalf("expected the polled entry to be restored, got %+v", snssaiSet)
+	}
+}
+
+func TestAdminCommitKeepsConfigurationWhenPersistFails(t *testing.T) {
+	setAdminTestConfig(t, &factory.Configuration{
+		Admin: &factory.Admin{PersistFile: filepath.Join(t.TempDir(), "missing", "provisioned.yaml")},
+	})
+	table := adminTables[AdminTableNsiList]
+
+	body := []byte(`{"snssai":{"sst":1},"nsiInformationList":[{"nrfId":"http://nrf:29510","nsiId":"1"}]}`)
+	_, _, problemDetails := table.create(body)
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %+v", problemDetails)
+	}
+	if len(factory.NssfConfig.Configuration.NsiList) != 0 {
+		t.Fatal("configuration must not change when it cannot be persisted")
+	}
+}
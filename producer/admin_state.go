@@ -0,0 +1,242 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Admin
+ *
+ * Read-only views of the state the NSSF has learned at runtime
+ */
+
+package producer
+
+import (
+	"net/http"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/nfregistration"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+// Query parameters accepted by the state views
+const (
+	adminFilterPlmn   = "plmn"
+	adminFilterTai    = "tai"
+	adminFilterSnssai = "snssai"
+	adminFilterAmf    = "amf"
+)
+
+// adminStateFilter narrows down a state view. Unset fields match everything.
+type adminStateFilter struct {
+	plmnId *models.PlmnId
+	tai    *models.Tai
+	snssai *models.Snssai
+	amfId  string
+}
+
+// parseAdminStateFilter reads the filter query parameters, rejecting the ones the view does not support
+func parseAdminStateFilter(request *httpwrapper.Request, supported ...string) (adminStateFilter, *models.ProblemDetails) {
+	var filter adminStateFilter
+	var violations []factory.Violation
+	isSupported := make(map[string]bool, len(supported))
+	for _, name := range supported {
+		isSupported[name] = true
+	}
+
+	for name, values := range request.Query {
+		if !isSupported[name] {
+			violations = append(violations, factory.Violation{Field: name, Reason: "is not a supported filter"})
+			continue
+		}
+		if len(values) != 1 {
+			violations = append(violations, factory.Violation{Field: name, Reason: "must be given once"})
+			continue
+		}
+		var err error
+		switch name {
+		case adminFilterPlmn:
+			var plmnId models.PlmnId
+			plmnId, err = factory.ParsePlmnIdString(values[0])
+			filter.plmnId = &plmnId
+		case adminFilterTai:
+			var tai models.Tai
+			tai, err = factory.ParseTaiString(values[0])
+			filter.tai = &tai
+		case adminFilterSnssai:
+			var snssai models.Snssai
+			snssai, err = factory.ParseSnssaiKeyString(values[0])
+			filter.snssai = &snssai
+		case adminFilterAmf:
+			filter.amfId = values[0]
+		}
+		if err != nil {
+			violations = append(violations, factory.Violation{Field: name, Reason: err.Error()})
+		}
+	}
+
+	if len(violations) != 0 {
+		return filter, adminValidationProblem(&factory.ValidationError{Violations: violations})
+	}
+	return filter, nil
+}
+
+func (f adminStateFilter) matchTai(tai models.Tai) bool {
+	if f.plmnId != nil && tai.PlmnId != *f.plmnId {
+		return false
+	}
+	if f.tai != nil && (tai.PlmnId != f.tai.PlmnId || tai.Tac != f.tai.Tac) {
+		return false
+	}
+	return true
+}
+
+func (f adminStateFilter) matchSnssaiList(snssaiList []models.Snssai) bool {
+	if f.snssai == nil {
+		return true
+	}
+	key := factory.SnssaiToKey(*f.snssai)
+	for _, snssai := range snssaiList {
+		if factory.SnssaiToKey(snssai) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// filterAmfAvailabilityLocked returns copies of the AMF entries and TAs matching the filter
+// The caller shall hold ConfigLock
+func filterAmfAvailabilityLocked(amfList []factory.AmfConfig, filter adminStateFilter) []factory.AmfConfig {
+	areaFiltered := filter.plmnId != nil || filter.tai != nil || filter.snssai != nil
+	result := make([]factory.AmfConfig, 0, len(amfList))
+	for _, amfConfig := range amfList {
+		if filter.amfId != "" && amfConfig.NfId != filter.amfId {
+			continue
+		}
+		availabilityData := make([]models.SupportedNssaiAvailabilityData, 0, len(amfConfig.SupportedNssaiAvailabilityData))
+		for _, data := range amfConfig.SupportedNssaiAvailabilityData {
+			if !filter.matchTai(data.Tai) || !filter.matchSnssaiList(data.SupportedSnssaiList) {
+				continue
+			}
+			data.SupportedSnssaiList = append([]models.Snssai(nil), data.SupportedSnssaiList...)
+			availabilityData = append(availabilityData, data)
+		}
+		if areaFiltered && len(availabilityData) == 0 {
+			continue
+		}
+		result = append(result, factory.AmfConfig{
+			NfId:                           amfConfig.NfId,
+			SupportedNssaiAvailabilityData: availabilityData,
+		})
+	}
+	return result
+}
+
+// filterSubscriptionsLocked returns copies of the subscriptions matching the filter
+// The caller shall hold ConfigLock
+func filterSubscriptionsLocked(subscriptions []factory.Subscription, filter adminStateFilter) []factory.Subscription {
+	result := make([]factory.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.SubscriptionData == nil {
+			continue
+		}
+		subscriptionData := *subscription.SubscriptionData
+		if filter.amfId != "" && subscriptionData.GetAmfId() != filter.amfId {
+			continue
+		}
+		if filter.tai != nil && !subscriptionIncludesTai(subscriptionData, *filter.tai) {
+			continue
+		}
+		if filter.plmnId != nil {
+			matched := false
+			for _, tai := range subscriptionData.TaiList {
+				if filter.matchTai(tai) {
+					matched = true
+					break
+				}
+			}
+			for _, taiRange := range subscriptionData.TaiRangeList {
+				if taiRange.PlmnId == *filter.plmnId {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		subscriptionData.TaiList = append([]models.Tai(nil), subscriptionData.TaiList...)
+		subscriptionData.TaiRangeList = append([]models.TaiRange(nil), subscriptionData.TaiRangeList...)
+		result = append(result, factory.Subscription{
+			SubscriptionId:   subscription.SubscriptionId,
+			SubscriptionData: &subscriptionData,
+			RoutingBinding:   subscription.RoutingBinding,
+		})
+	}
+	return result
+}
+
+func filterSupportedNssaiInPlmn(entries []factory.SupportedNssaiInPlmnEntry,
+	filter adminStateFilter,
+) []factory.SupportedNssaiInPlmnEntry {
+	result := make([]factory.SupportedNssaiInPlmnEntry, 0, len(entries))
+	for _, entry := range entries {
+		if filter.plmnId != nil && entry.PlmnId != *filter.plmnId {
+			continue
+		}
+		if !filter.matchSnssaiList(entry.SNssaiList) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// HandleAdminAmfAvailability - Shows the S-NSSAIs AMFs reported as available per TA
+func HandleAdminAmfAvailability(request *httpwrapper.Request) *httpwrapper.Response {
+	filter, problemDetails := parseAdminStateFilter(request,
+		adminFilterPlmn, adminFilterTai, adminFilterSnssai, adminFilterAmf)
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+
+	factory.ConfigLock.RLock()
+	defer factory.ConfigLock.RUnlock()
+	amfList := filterAmfAvailabilityLocked(factory.NssfConfig.Configuration.AmfList, filter)
+	return httpwrapper.NewResponse(http.StatusOK, nil, amfList)
+}
+
+// HandleAdminSubscriptions - Shows the active NSSAI availability subscriptions
+// S-NSSAIs are not part of a subscription, so they cannot be used as a filter
+func HandleAdminSubscriptions(request *httpwrapper.Request) *httpwrapper.Response {
+	filter, problemDetails := parseAdminStateFilter(request, adminFilterPlmn, adminFilterTai, adminFilterAmf)
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+
+	factory.ConfigLock.RLock()
+	defer factory.ConfigLock.RUnlock()
+	subscriptions := filterSubscriptionsLocked(factory.NssfConfig.Subscriptions, filter)
+	return httpwrapper.NewResponse(http.StatusOK, nil, subscriptions)
+}
+
+// HandleAdminSupportedNssaiInPlmn - Shows the supported NSSAI per PLMN in effect, polled from the webconsole
+// or provisioned through the admin API
+func HandleAdminSupportedNssaiInPlmn(request *httpwrapper.Request) *httpwrapper.Response {
+	filter, problemDetails := parseAdminStateFilter(request, adminFilterPlmn, adminFilterSnssai)
+	if problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+
+	factory.ConfigLock.RLock()
+	defer factory.ConfigLock.RUnlock()
+	entries := filterSupportedNssaiInPlmn(factory.NssfConfig.Configuration.SupportedNssaiInPlmnList.Entries(), filter)
+	return httpwrapper.NewResponse(http.StatusOK, nil, entries)
+}
+
+// HandleAdminNrfRegistration - Shows the registration state of the NSSF at the NRF
+func HandleAdminNrfRegistration(request *httpwrapper.Request) *httpwrapper.Response {
+	if _, problemDetails := parseAdminStateFilter(request); problemDetails != nil {
+		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
+	}
+	return httpwrapper.NewResponse(http.StatusOK, nil, nfregistration.GetRegistrationState())
+}
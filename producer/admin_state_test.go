@@ -0,0 +1,105 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+func newAdminStateRequest(target string) *httpwrapper.Request {
+	return httpwrapper.NewRequest(httptest.NewRequest(http.MethodGet, target, nil), nil)
+}
+
+func TestAdminAmfAvailabilityFilters(t *testing.T) {
+	snssai := func(sst int32, sd string) models.Snssai {
+		s := models.NewSnssai(sst)
+		if sd != "" {
+			s.SetSd(sd)
+		}
+		return *s
+	}
+	tai := func(tac string) models.Tai {
+		return models.Tai{PlmnId: models.PlmnId{Mcc: "208", Mnc: "93"}, Tac: tac}
+	}
+	setAdminTestConfig(t, &factory.Configuration{
+		AmfList: []factory.AmfConfig{
+			{
+				NfId: "amf-1",
+				SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+					{Tai: tai("000001"), SupportedSnssaiList: []models.Snssai{snssai(1, "010203")}},
+					{Tai: tai("000002"), SupportedSnssaiList: []models.Snssai{snssai(2, "")}},
+				},
+			},
+			{
+				NfId: "amf-2",
+				SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+					{Tai: tai("000002"), SupportedSnssaiList: []models.Snssai{snssai(1, "010203")}},
+				},
+			},
+		},
+	})
+
+	testCases := []struct {
+		name      string
+		target    string
+		expectAmf map[string]int
+	}{
+		{
+			name:      "no filter",
+			target:    "/state/amf-availability",
+			expectAmf: map[string]int{"amf-1": 2, "amf-2": 1},
+		},
+		{
+			name:      "by TAI",
+			target:    "/state/amf-availability?tai=208-93-000002",
+			expectAmf: map[string]int{"amf-1": 1, "amf-2": 1},
+		},
+		{
+			name:      "by S-NSSAI and AMF",
+			target:    "/state/amf-availability?snssai=1-010203&amf=amf-1",
+			expectAmf: map[string]int{"amf-1": 1},
+		},
+		{
+			name:      "by PLMN without match",
+			target:    "/state/amf-availability?plmn=001-01",
+			expectAmf: map[string]int{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rsp := HandleAdminAmfAvailability(newAdminStateRequest(tc.target))
+			if rsp.Status != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %+v", rsp.Status, rsp.Body)
+			}
+			amfList := rsp.Body.([]factory.AmfConfig)
+			if len(amfList) != len(tc.expectAmf) {
+				t.Fatalf("expected %d AMFs, got %+v", len(tc.expectAmf), amfList)
+			}
+			for _, amfConfig := range amfList {
+				if len(amfConfig.SupportedNssaiAvailabilityData) != tc.expectAmf[amfConfig.NfId] {
+					t.Errorf("unexpected TAs for %s: %+v", amfConfig.NfId, amfConfig.SupportedNssaiAvailabilityData)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminStateRejectsInvalidFilters(t *testing.T) {
+	setAdminTestConfig(t, &factory.Configuration{})
+
+	rsp := HandleAdminSubscriptions(newAdminStateRequest("/state/subscriptions?snssai=1&plmn=20893"))
+	if rsp.Status != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rsp.Status)
+	}
+	problemDetails := rsp.Body.(*models.ProblemDetails)
+	if len(problemDetails.InvalidParams) != 2 {
+		t.Fatalf("expected both filters to be reported, got %+v", problemDetails.InvalidParams)
+	}
+}
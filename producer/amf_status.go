@@ -0,0 +1,219 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF NSSAI Availability
+ *
+ * Status of the AMFs which provided their NSSAI availability
+ */
+
+package producer
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+var (
+	// Time at which each AMF which provided its NSSAI availability last contacted the NSSF
+	amfLastContact      = make(map[string]time.Time)
+	amfLastContactMutex sync.Mutex
+)
+
+// recordAmfContact records that the AMF has just contacted the NSSF
+func recordAmfContact(nfId string) {
+	amfLastContactMutex.Lock()
+	defer amfLastContactMutex.Unlock()
+	amfLastContact[nfId] = time.Now()
+}
+
+// amfContactedSince reports whether the AMF contacted the NSSF after the given time
+func amfContactedSince(nfId string, since time.Time) bool {
+	amfLastContactMutex.Lock()
+	defer amfLastContactMutex.Unlock()
+	lastContact, found := amfLastContact[nfId]
+	return found && lastContact.After(since)
+}
+
+func forgetAmfContact(nfId string) {
+	amfLastContactMutex.Lock()
+	defer amfLastContactMutex.Unlock()
+	delete(amfLastContact, nfId)
+}
+
+// amfContacted records that the AMF contacted the NSSF, and resumes its NSSAI availability if it was suspended
+// because it had not contacted the NSSF. The NSSAI availability of an AMF suspended by the NRF is not resumed.
+func amfContacted(nfId string) {
+	amfConfig, found := factory.CurrentSnapshot().Amf(nfId)
+	if !found || amfConfig.Version == 0 {
+		return
+	}
+	recordAmfContact(nfId)
+	if amfConfig.SuspensionCause != factory.AmfSuspendedByTtl {
+		return
+	}
+	factory.ConfigLock.Lock()
+	defer factory.ConfigLock.Unlock()
+	resumeAmfLocked(nfId, factory.AmfSuspendedByTtl)
+}
+
+// suspendAmfLocked suspends the NSSAI availability of the AMF for the cause. The suspension by the NRF
+// takes precedence, so that the AMF is not resumed when it contacts the NSSF.
+// The caller shall hold ConfigLock
+func suspendAmfLocked(nfId string, cause string) {
+	amfConfig, found := findAmfConfigLocked(nfId)
+	if !found || (amfConfig.Suspended && (amfConfig.SuspensionCause == cause || cause == factory.AmfSuspendedByTtl)) {
+		return
+	}
+	amfConfig.Suspended = true
+	amfConfig.SuspensionCause = cause
+	putAmfConfigLocked(amfConfig)
+	logger.Nssaiavailability.Infof("NSSAI availability of AMF %s suspended, cause: %s", nfId, cause)
+}
+
+// resumeAmfLocked resumes the NSSAI availability of the AMF if it was suspended for one of the causes
+// The caller shall hold ConfigLock
+func resumeAmfLocked(nfId string, causes ...string) {
+	amfConfig, found := findAmfConfigLocked(nfId)
+	if !found || !amfConfig.Suspended || !slices.Contains(causes, amfConfig.SuspensionCause) {
+		return
+	}
+	amfConfig.Suspended = false
+	amfConfig.SuspensionCause = ""
+	putAmfConfigLocked(amfConfig)
+	logger.Nssaiavailability.Infof("NSSAI availability of AMF %s resumed", nfId)
+}
+
+// HandleNfStatusNotify - Handles the notification of the NRF about a change of the status of an AMF
+func HandleNfStatusNotify(request *httpwrapper.Request) *httpwrapper.Response {
+	logger.Nssaiavailability.Infof("Handle NfStatusNotify")
+
+	notificationData := request.Body.(models.NotificationData)
+
+	NfStatusNotifyProcedure(notificationData)
+	return httpwrapper.NewResponse(http.StatusNoContent, nil, nil)
+}
+
+// NfStatusNotifyProcedure applies the status of an AMF reported by the NRF, TS 29.510 clause 5.2.2.6.5
+// The NSSAI availability of a deregistered AMF is removed, and the one of an AMF which is no longer
+// discoverable is suspended until the AMF is registered again.
+func NfStatusNotifyProcedure(notificationData models.NotificationData) {
+	nfId := notificationData.NfInstanceUri[strings.LastIndex(notificationData.NfInstanceUri, "/")+1:]
+	if nfProfile := notificationData.NfProfile; nfProfile != nil {
+		if nfProfile.GetNfType() != models.NFTYPE_AMF {
+			return
+		}
+		nfId = nfProfile.GetNfInstanceId()
+	}
+
+	factory.ConfigLock.Lock()
+	defer factory.ConfigLock.Unlock()
+	switch notificationData.Event {
+	case models.NOTIFICATIONEVENTTYPE_NF_DEREGISTERED:
+		if removeAmfConfigLocked(nfId) {
+			logger.Nssaiavailability.Infof("NSSAI availability of AMF %s removed, the AMF deregistered", nfId)
+		}
+	case models.NOTIFICATIONEVENTTYPE_NF_REGISTERED:
+		resumeAmfLocked(nfId, factory.AmfSuspendedByNrf, factory.AmfSuspendedByTtl)
+	case models.NOTIFICATIONEVENTTYPE_NF_PROFILE_CHANGED:
+		nfStatus, found := nfStatusOfNotification(notificationData)
+		switch {
+		case !found:
+		case nfStatus == models.NFSTATUS_REGISTERED:
+			resumeAmfLocked(nfId, factory.AmfSuspendedByNrf, factory.AmfSuspendedByTtl)
+		default:
+			suspendAmfLocked(nfId, factory.AmfSuspendedByNrf)
+		}
+	}
+}
+
+// SynchronizeAmfStatus applies the status of the AMFs which provided their NSSAI availability, as discovered at
+// the NRF with amfRegistered, e.g. after the NSSF subscribed to their status at another NRF. The NSSAI availability
+// of an AMF which the NRF reports as not registered is suspended, and the one of a registered AMF resumed. The
+// status of an AMF which cannot be discovered is left unchanged. The synchronization stops when ctx is cancelled.
+func SynchronizeAmfStatus(ctx context.Context, amfRegistered func(ctx context.Context, nfId string) (bool, error)) {
+	var nfIds []string
+	for _, amfConfig := range factory.CurrentSnapshot().AmfList() {
+		if amfConfig.Version != 0 {
+			nfIds = append(nfIds, amfConfig.NfId)
+		}
+	}
+
+	for _, nfId := range nfIds {
+		registered, err := amfRegistered(ctx, nfId)
+		if ctx.Err() != nil {
+			logger.Nssaiavailability.Infoln("synchronization of the status of AMFs cancelled")
+			return
+		}
+		if err != nil {
+			logger.Nssaiavailability.Warnf("discover the status of AMF %s at NRF failed: %+v", nfId, err)
+			continue
+		}
+		factory.ConfigLock.Lock()
+		if registered {
+			resumeAmfLocked(nfId, factory.AmfSuspendedByNrf, factory.AmfSuspendedByTtl)
+		} else {
+			suspendAmfLocked(nfId, factory.AmfSuspendedByNrf)
+		}
+		factory.ConfigLock.Unlock()
+	}
+}
+
+// nfStatusOfNotification returns the status of the NF, given in its profile or in the changes of its profile
+func nfStatusOfNotification(notificationData models.NotificationData) (models.NFStatus, bool) {
+	if nfProfile := notificationData.NfProfile; nfProfile != nil {
+		return nfProfile.GetNfStatus(), true
+	}
+	for _, changeItem := range notificationData.ProfileChanges {
+		if changeItem.Path != "/nfStatus" {
+			continue
+		}
+		if nfStatus, ok := changeItem.NewValue.(string); ok {
+			return models.NFStatus(nfStatus), true
+		}
+	}
+	return "", false
+}
+
+// StartAmfAvailabilityExpiry suspends the NSSAI availability of the AMFs which have not contacted the NSSF for
+// the TTL, while the NSSF is not subscribed to the status of AMFs at the NRF, until the context is cancelled
+func StartAmfAvailabilityExpiry(ctx context.Context, ttl time.Duration, nrfStatusActive func() bool) {
+	if ttl <= 0 {
+		return
+	}
+	ticker := time.NewTicker(max(ttl/4, time.Second))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !nrfStatusActive() {
+				expireAmfAvailability(now, ttl)
+			}
+		}
+	}
+}
+
+// expireAmfAvailability suspends the NSSAI availability of the AMFs which have not contacted the NSSF since
+// the TTL before now. AMFs of the configuration file never expire
+func expireAmfAvailability(now time.Time, ttl time.Duration) {
+	factory.ConfigLock.Lock()
+	defer factory.ConfigLock.Unlock()
+	for _, amfConfig := range factory.NssfConfig.Configuration.AmfList {
+		if amfConfig.Version == 0 || amfConfig.Suspended || amfContactedSince(amfConfig.NfId, now.Add(-ttl)) {
+			continue
+		}
+		logger.Nssaiavailability.Warnf("AMF %s has not contacted the NSSF for %s", amfConfig.NfId, ttl)
+		suspendAmfLocked(amfConfig.NfId, factory.AmfSuspendedByTtl)
+	}
+}
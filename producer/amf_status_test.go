@@ -0,0 +1,227 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// setAmfStatusTestConfig adds the NSSAI availability of amf-3 through the NSSAIAvailability service, and a
+// subscription to the serving TA whose notifications are returned on the channel
+func setAmfStatusTestConfig(t *testing.T) <-chan models.NssfEventNotification {
+	t.Helper()
+	setAvailabilityTestConfig(t)
+	if _, _, problemDetails := NSSAIAvailabilityPutProcedure(newNssaiAvailabilityInfo(testServingSnssai2), "amf-3", ""); problemDetails != nil {
+		t.Fatalf("expected the NSSAI availability to be stored, got %+v", problemDetails)
+	}
+
+	notifications := make(chan models.NssfEventNotification, 8)
+	originalSend := sendNssaiAvailabilityNotification
+	t.Cleanup(func() { sendNssaiAvailabilityNotification = originalSend })
+	sendNssaiAvailabilityNotification = func(uri, routingBinding string, notification models.NssfEventNotification) error {
+		notifications <- notification
+		return nil
+	}
+	factory.NssfConfig.Subscriptions = []factory.Subscription{{
+		SubscriptionId: "1",
+		SubscriptionData: &models.NssfEventSubscriptionCreateData{
+			NfNssaiAvailabilityUri: "http://amf-1/notify",
+			TaiList:                []models.Tai{testServingTai},
+			Event:                  models.NSSFEVENTTYPE_SNSSAI_STATUS_CHANGE_REPORT,
+		},
+	}}
+	return notifications
+}
+
+func expectNssaiAvailabilityNotification(t *testing.T, notifications <-chan models.NssfEventNotification,
+	supportedSnssaiList []models.Snssai,
+) {
+	t.Helper()
+	select {
+	case notification := <-notifications:
+		if len(notification.AuthorizedNssaiAvailabilityData) != 1 ||
+			!reflect.DeepEqual(notification.AuthorizedNssaiAvailabilityData[0].SupportedSnssaiList, supportedSnssaiList) {
+			t.Fatalf("expected a notification of S-NSSAIs %+v, got %+v", supportedSnssaiList, notification)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a notification of the subscription")
+	}
+}
+
+func candidateAmfs(snssai models.Snssai) []string {
+	info := &models.AuthorizedNetworkSliceInfo{AllowedNssaiList: []models.AllowedNssai{{
+		AllowedSnssaiList: []models.AllowedSnssai{{AllowedSnssai: snssai}},
+		AccessType:        models.ACCESSTYPE__3_GPP_ACCESS,
+	}}}
+	util.AddAmfInformation(factory.CurrentSnapshot(), testServingTai, info)
+	return info.CandidateAmfList
+}
+
+func TestNfStatusNotifyRemovesDeregisteredAmf(t *testing.T) {
+	notifications := setAmfStatusTestConfig(t)
+
+	NfStatusNotifyProcedure(models.NotificationData{
+		Event:         models.NOTIFICATIONEVENTTYPE_NF_DEREGISTERED,
+		NfInstanceUri: "http://nrf/nnrf-nfm/v1/nf-instances/amf-3",
+	})
+
+	if _, found := factory.CurrentSnapshot().Amf("amf-3"); found {
+		t.Fatal("expected the NSSAI availability of the deregistered AMF to be removed")
+	}
+	expectNssaiAvailabilityNotification(t, notifications, []models.Snssai{testServingSnssai1})
+}
+
+func TestNfStatusNotifyKeepsAmfSetOfDeregisteredAmfUnavailable(t *testing.T) {
+	setTestConfig(t, &factory.Configuration{
+		SupportedNssaiInPlmnList: factory.SupportedNssaiInPlmn{
+			testServingPlmnId: {factory.SnssaiToKey(testServingSnssai2): {}},
+		},
+		AmfSetList: []factory.AmfSetConfig{{
+			AmfSetId: "set-1",
+			AmfList:  []string{"amf-3"},
+			SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+				{Tai: testServingTai, SupportedSnssaiList: []models.Snssai{testServingSnssai2}},
+			},
+		}},
+	})
+	if _, _, problemDetails := NSSAIAvailabilityPutProcedure(newNssaiAvailabilityInfo(testServingSnssai2), "amf-3", ""); problemDetails != nil {
+		t.Fatalf("expected the NSSAI availability to be stored, got %+v", problemDetails)
+	}
+
+	NfStatusNotifyProcedure(models.NotificationData{
+		Event:         models.NOTIFICATIONEVENTTYPE_NF_DEREGISTERED,
+		NfInstanceUri: "http://nrf/nnrf-nfm/v1/nf-instances/amf-3",
+	})
+	if candidates := candidateAmfs(testServingSnssai2); len(candidates) != 0 {
+		t.Fatalf("expected the AMF Set of the deregistered AMF not to be selected, got %+v", candidates)
+	}
+	if amfSetConfig, _ := factory.CurrentSnapshot().AmfSet("set-1"); len(amfSetConfig.SupportedNssaiAvailabilityData) != 0 {
+		t.Fatalf("expected the NSSAI availability of the configuration file not to apply again, got %+v", amfSetConfig)
+	}
+}
+
+func TestNfStatusNotifySuspendsUnavailableAmf(t *testing.T) {
+	notifications := setAmfStatusTestConfig(t)
+
+	NfStatusNotifyProcedure(models.NotificationData{
+		Event:          models.NOTIFICATIONEVENTTYPE_NF_PROFILE_CHANGED,
+		NfInstanceUri:  "http://nrf/nnrf-nfm/v1/nf-instances/amf-3",
+		ProfileChanges: []models.ChangeItem{{Op: models.CHANGETYPE_REPLACE, Path: "/nfStatus", NewValue: "SUSPENDED"}},
+	})
+	if candidates := candidateAmfs(testServingSnssai2); len(candidates) != 0 {
+		t.Fatalf("expected the suspended AMF not to be a candidate, got %+v", candidates)
+	}
+	expectNssaiAvailabilityNotification(t, notifications, []models.Snssai{testServingSnssai1})
+
+	NfStatusNotifyProcedure(models.NotificationData{
+		Event:         models.NOTIFICATIONEVENTTYPE_NF_REGISTERED,
+		NfInstanceUri: "http://nrf/nnrf-nfm/v1/nf-instances/amf-3",
+	})
+	if candidates := candidateAmfs(testServingSnssai2); !reflect.DeepEqual(candidates, []string{"amf-3"}) {
+		t.Fatalf("expected the registered AMF to be a candidate again, got %+v", candidates)
+	}
+	expectNssaiAvailabilityNotification(t, notifications, []models.Snssai{testServingSnssai1, testServingSnssai2})
+}
+
+func TestAmfSuspendedByNrfIsNotResumedOnContact(t *testing.T) {
+	setAmfStatusTestConfig(t)
+
+	NfStatusNotifyProcedure(models.NotificationData{
+		Event:          models.NOTIFICATIONEVENTTYPE_NF_PROFILE_CHANGED,
+		NfInstanceUri:  "http://nrf/nnrf-nfm/v1/nf-instances/amf-3",
+		ProfileChanges: []models.ChangeItem{{Op: models.CHANGETYPE_REPLACE, Path: "/nfStatus", NewValue: "UNDISCOVERABLE"}},
+	})
+	// The TTL expiry does not replace the suspension by the NRF
+	expireAmfAvailability(time.Now().Add(time.Hour), time.Minute)
+	amfContacted("amf-3")
+	if _, _, problemDetails := NSSAIAvailabilityPutProcedure(newNssaiAvailabilityInfo(testServingSnssai2), "amf-3", ""); problemDetails != nil {
+		t.Fatalf("expected the NSSAI availability to be stored, got %+v", problemDetails)
+	}
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); !amfConfig.Suspended ||
+		amfConfig.SuspensionCause != factory.AmfSuspendedByNrf {
+		t.Fatalf("expected the NSSAI availability to stay suspended by the NRF, got %+v", amfConfig)
+	}
+
+	NfStatusNotifyProcedure(models.NotificationData{
+		Event:         models.NOTIFICATIONEVENTTYPE_NF_REGISTERED,
+		NfInstanceUri: "http://nrf/nnrf-nfm/v1/nf-instances/amf-3",
+	})
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); amfConfig.Suspended {
+		t.Fatal("expected the NSSAI availability to be resumed when the NRF reports the AMF registered")
+	}
+}
+
+func TestExpireAmfAvailability(t *testing.T) {
+	setAmfStatusTestConfig(t)
+	ttl := time.Minute
+
+	expireAmfAvailability(time.Now(), ttl)
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); amfConfig.Suspended {
+		t.Fatal("expected the NSSAI availability of an AMF which contacted the NSSF to be kept")
+	}
+
+	expireAmfAvailability(time.Now().Add(2*ttl), ttl)
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); !amfConfig.Suspended ||
+		amfConfig.SuspensionCause != factory.AmfSuspendedByTtl {
+		t.Fatal("expected the NSSAI availability of the AMF to be suspended")
+	}
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-1"); amfConfig.Suspended {
+		t.Fatal("expected the NSSAI availability of the configuration file not to expire")
+	}
+
+	amfContacted("amf-3")
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); amfConfig.Suspended {
+		t.Fatal("expected the NSSAI availability to be resumed when the AMF contacts the NSSF")
+	}
+}
+
+func TestSynchronizeAmfStatus(t *testing.T) {
+	setAmfStatusTestConfig(t)
+	var discovered []string
+	amfRegistered := func(registered bool, err error) func(ctx context.Context, nfId string) (bool, error) {
+		return func(ctx context.Context, nfId string) (bool, error) {
+			discovered = append(discovered, nfId)
+			return registered, err
+		}
+	}
+
+	SynchronizeAmfStatus(t.Context(), amfRegistered(false, errors.New("NRF unavailable")))
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); amfConfig.Suspended {
+		t.Fatal("expected the NSSAI availability of an AMF which cannot be discovered to be kept")
+	}
+
+	SynchronizeAmfStatus(t.Context(), amfRegistered(false, nil))
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); !amfConfig.Suspended {
+		t.Fatal("expected the NSSAI availability of an AMF not registered at the NRF to be suspended")
+	}
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-1"); amfConfig.Suspended {
+		t.Fatal("expected the NSSAI availability of the configuration file to be kept")
+	}
+
+	SynchronizeAmfStatus(t.Context(), amfRegistered(true, nil))
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); amfConfig.Suspended {
+		t.Fatal("expected the NSSAI availability of the registered AMF to be resumed")
+	}
+	if !reflect.DeepEqual(discovered, []string{"amf-3", "amf-3", "amf-3"}) {
+		t.Fatalf("expected only the AMF which provided its NSSAI availability to be discovered, got %v", discovered)
+	}
+
+	// The result of a search which is cancelled, e.g. on deregistration, is not applied
+	ctx, cancel := context.WithCancel(t.Context())
+	SynchronizeAmfStatus(ctx, func(ctx context.Context, nfId string) (bool, error) {
+		cancel()
+		return false, ctx.Err()
+	})
+	if amfConfig, _ := factory.CurrentSnapshot().Amf("amf-3"); amfConfig.Suspended {
+		t.Fatal("expected a cancelled synchronization to leave the NSSAI availability unchanged")
+	}
+}
@@ -21,9 +21,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/features"
 	"github.com/omec-project/nssf/logger"
 	stats "github.com/omec-project/nssf/metrics"
 	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/nssf/validation"
 	"github.com/omec-project/openapi/v2/models"
 	"github.com/omec-project/openapi/v2/utils"
 	"github.com/omec-project/util/httpwrapper"
@@ -363,11 +366,47 @@ func parseQueryParameter(query url.Values) (NsselectionQueryParameter, error) {
 
 	if query.Get("supported-features") != "" {
 		param.SupportedFeatures = query.Get("supported-features")
+		if param.negotiatedFeatures, err = features.NSSelection.Negotiate(param.SupportedFeatures); err != nil {
+			return param, err
+		}
 	}
 
 	return param, err
 }
 
+// Schemas of the query parameters of NSSelectionGet, TS 29.531 clause 6.1.3.2.3.1
+var nsselectionQueryParameterSchemas = []struct {
+	name     string
+	schema   *validation.Schema
+	required bool
+}{
+	{"nf-type", validation.NfType, true},
+	{"nf-id", validation.NfInstanceId, true},
+	{"slice-info-request-for-registration", validation.SliceInfoForRegistration, false},
+	{"slice-info-request-for-pdu-session", validation.SliceInfoForPDUSession, false},
+	{"home-plmn-id", validation.PlmnId, false},
+	{"tai", validation.Tai, false},
+	{"supported-features", validation.SupportedFeatures, false},
+}
+
+// validateQueryParameters validates the query parameters against their schemas, and returns every violation,
+// including every required query parameter which is missing
+func validateQueryParameters(query url.Values) *models.ProblemDetails {
+	var invalidParams []models.InvalidParam
+	for _, parameter := range nsselectionQueryParameterSchemas {
+		if parameter.required && !query.Has(parameter.name) && !hasExplodedQueryParam(query, parameter.name) {
+			reason := "is required"
+			invalidParams = append(invalidParams, models.InvalidParam{Param: parameter.name, Reason: &reason})
+			continue
+		}
+		invalidParams = append(invalidParams, validation.ValidateQueryParameter(query, parameter.name, parameter.schema)...)
+	}
+	if len(invalidParams) != 0 {
+		return validation.ProblemDetailsInvalidQuery(invalidParams)
+	}
+	return nil
+}
+
 // Check if the NF service consumer is authorized
 // TODO: Check if the NF service consumer is legal with local configuration, or possibly after querying NRF through `nf-id` e.g. Whether the V-NSSF is authorized
 func checkNfServiceConsumer(nfType models.NFType) error {
@@ -383,12 +422,15 @@ func HandleNSSelectionGet(request *httpwrapper.Request) *httpwrapper.Response {
 	logger.Nsselection.Infof("Handle NSSelectionGet")
 
 	query := request.Query
-
-	response, problemDetails := NSSelectionGetProcedure(query)
-
 	nfType := GetNfTypeFromQueryParameters(query)
 	nfId := GetNfIdFromQueryParameters(query)
 
+	if rsp := throttle(models.SERVICENAME_NNSSF_NSSELECTION, nfType, nfId); rsp != nil {
+		return rsp
+	}
+
+	response, problemDetails := NSSelectionGetProcedure(query)
+
 	if response != nil {
 		stats.IncrementNssfNsSelectionsStats(nfType, nfId, "SUCCESS")
 		return httpwrapper.NewResponse(http.StatusOK, nil, response)
@@ -406,9 +448,12 @@ func NSSelectionGetProcedure(query url.Values) (*models.AuthorizedNetworkSliceIn
 	response := models.NewAuthorizedNetworkSliceInfo()
 	problemDetails := models.NewProblemDetails()
 
-	// TODO: Record request times of the NF service consumer and response with ProblemDetails of 429 Too Many Requests
-	//       if the consumer has sent too many requests in a configured amount of time
-	// TODO: Check URI length and response with ProblemDetails of 414 URI Too Long if URI is too long
+	// The length of the URI and the number of query parameters are limited by sbi.RequestLimits
+
+	if problemDetails = validateQueryParameters(query); problemDetails != nil {
+		logger.Nsselection.Errorf("%s: %+v", problemDetails.GetDetail(), problemDetails.InvalidParams)
+		return nil, problemDetails
+	}
 
 	// Parse query parameter
 	param, err := parseQueryParameter(query)
@@ -419,17 +464,12 @@ func NSSelectionGetProcedure(query url.Values) (*models.AuthorizedNetworkSliceIn
 		return nil, problemDetails
 	}
 
-	// Check permission of NF service consumer
-	if param.NfType == nil {
-		problemDetail := "[Query Parameter] `nf-type` is required"
-		invalidParams := []models.InvalidParam{{
-			Param:  "nf-type",
-			Reason: &problemDetail,
-		}}
-		problemDetails = utils.ProblemDetailsWithInvalidParams(util.INVALID_REQUEST, http.StatusBadRequest, problemDetail, invalidParams)
+	if problemDetails = checkNsselectionQueryLimits(param); problemDetails != nil {
+		logger.Nsselection.Errorln(problemDetails.GetDetail())
 		return nil, problemDetails
 	}
 
+	// Check permission of NF service consumer. `nf-type` is required by validateQueryParameters
 	err = checkNfServiceConsumer(*param.NfType)
 	if err != nil {
 		// status = http.StatusForbidden
@@ -447,6 +487,11 @@ func NSSelectionGetProcedure(query url.Values) (*models.AuthorizedNetworkSliceIn
 		return nil, problemDetails
 	}
 
+	if *param.NfType == models.NFTYPE_AMF {
+		amfContacted(param.NfId)
+	}
+
+	param.config = factory.CurrentSnapshot()
 	if param.SliceInfoRequestForRegistration != nil {
 		// Network slice information is requested during the Registration procedure
 		status = nsselectionForRegistration(param, response, problemDetails)
@@ -458,9 +503,23 @@ func NSSelectionGetProcedure(query url.Values) (*models.AuthorizedNetworkSliceIn
 	if status != http.StatusOK {
 		return nil, problemDetails
 	}
+	applyNegotiatedFeatures(param, response)
 	return response, nil
 }
 
+// applyNegotiatedFeatures removes the response attributes of features the consumer did not negotiate
+// and indicates the negotiated features if the consumer provided its own
+func applyNegotiatedFeatures(param NsselectionQueryParameter, response *models.AuthorizedNetworkSliceInfo) {
+	if !param.featureNegotiated(features.NSSelectionNSAG) {
+		response.NsagInfos = nil
+	}
+	if param.SupportedFeatures != "" {
+		logger.Nsselection.Debugf("negotiated features with %s: %v", param.NfId,
+			features.NSSelection.Names(param.negotiatedFeatures))
+		response.SetSupportedFeatures(param.negotiatedFeatures.String())
+	}
+}
+
 func GetNfTypeFromQueryParameters(query url.Values) (nfType string) {
 	if query.Get("nf-type") != "" {
 		return query.Get("nf-type")
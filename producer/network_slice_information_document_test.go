@@ -4,13 +4,17 @@
 package producer
 
 import (
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/sbi"
 	"github.com/omec-project/openapi/v2"
 	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/util/httpwrapper"
 )
 
 func TestParseQueryParameterSupportsExplodedRegistrationRequest(t *testing.T) {
@@ -116,3 +120,163 @@ func TestParseExplodedSnssaiRejectsSdWithoutSst(t *testing.T) {
 		t.Fatalf("expected error to include parameter name, got %q", err)
 	}
 }
+
+func TestNegotiatedFeaturesGateResponse(t *testing.T) {
+	testCases := []struct {
+		name                      string
+		supportedFeatures         string
+		expectSupportedFeatures   string
+		expectNsagInfos           bool
+		expectNegotiationRejected bool
+	}{
+		{
+			name:            "consumer without supported features",
+			expectNsagInfos: false,
+		},
+		{
+			name:                    "consumer supporting ES3XX and NSAG",
+			supportedFeatures:       "5",
+			expectSupportedFeatures: "4",
+			expectNsagInfos:         true,
+		},
+		{
+			name:                    "consumer supporting unknown features only",
+			supportedFeatures:       "100",
+			expectSupportedFeatures: "0",
+			expectNsagInfos:         false,
+		},
+		{
+			name:                      "malformed supported features",
+			supportedFeatures:         "xyz",
+			expectNegotiationRejected: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := url.Values{}
+			if tc.supportedFeatures != "" {
+				query.Set("supported-features", tc.supportedFeatures)
+			}
+			param, err := parseQueryParameter(query)
+			if tc.expectNegotiationRejected {
+				if err == nil {
+					t.Fatal("expected malformed supported features to be rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseQueryParameter returned error: %v", err)
+			}
+
+			response := models.NewAuthorizedNetworkSliceInfo()
+			response.SetNsagInfos([]models.NsagInfo{{NsagIds: []int32{1}}})
+			applyNegotiatedFeatures(param, response)
+
+			if response.GetSupportedFeatures() != tc.expectSupportedFeatures {
+				t.Errorf("expected supportedFeatures %q, got %q", tc.expectSupportedFeatures, response.GetSupportedFeatures())
+			}
+			if (len(response.NsagInfos) != 0) != tc.expectNsagInfos {
+				t.Errorf("unexpected nsagInfos: %+v", response.NsagInfos)
+			}
+		})
+	}
+}
+
+func TestNSSelectionGetThrottlesConsumerAboveRateLimit(t *testing.T) {
+	sbi.SetRateLimits([]factory.RateLimitConfig{{Name: "amf", NfType: models.NFTYPE_AMF, Rate: 0.1}})
+	t.Cleanup(func() { sbi.SetRateLimits(nil) })
+
+	request := func(nfId string) *httpwrapper.Response {
+		query := url.Values{"nf-type": {"AMF"}, "nf-id": {nfId}}
+		return HandleNSSelectionGet(httpwrapper.NewRequest(&http.Request{URL: &url.URL{RawQuery: query.Encode()}}, nil))
+	}
+	amf1, amf2 := "9d2e5f4a-1b3c-4d5e-8f60-718293a4b5c6", "0a1b2c3d-4e5f-4061-8273-8495a6b7c8d9"
+	if rsp := request(amf1); rsp.Status == http.StatusTooManyRequests {
+		t.Fatal("expected the first request not to be throttled")
+	}
+	rsp := request(amf1)
+	if rsp.Status != http.StatusTooManyRequests || rsp.Header.Get("Retry-After") != "10" {
+		t.Fatalf("expected the second request to be throttled for 10s, got %d %+v", rsp.Status, rsp.Header)
+	}
+	if problemDetails := rsp.Body.(*models.ProblemDetails); problemDetails.GetCause() != sbi.CauseNfCongestion {
+		t.Errorf("expected cause %s, got %+v", sbi.CauseNfCongestion, problemDetails)
+	}
+	if rsp = request(amf2); rsp.Status == http.StatusTooManyRequests {
+		t.Error("expected another AMF not to be throttled")
+	}
+
+	// The AMF has its own rate limit for the NSSAIAvailability service
+	setTestConfig(t, &factory.Configuration{})
+	deleteRequest := httpwrapper.NewRequest(&http.Request{URL: &url.URL{}, Header: http.Header{}}, nil)
+	deleteRequest.Params["nfId"] = amf1
+	if rsp = HandleNSSAIAvailabilityDelete(deleteRequest); rsp.Status == http.StatusTooManyRequests {
+		t.Error("expected the NSSAI availability of the throttled AMF not to be throttled")
+	}
+}
+
+func TestNSSelectionGetReturnsEveryInvalidQueryParameter(t *testing.T) {
+	query := url.Values{
+		"nf-type": {"AMF"},
+		"nf-id":   {"9d2e5f4a-1b3c-4d5e-8f60-718293a4b5c6"},
+		"slice-info-request-for-pdu-session[sNssai][sst]":       {"1"},
+		"slice-info-request-for-pdu-session[sNssai][sd]":        {"not-hex"},
+		"slice-info-request-for-pdu-session[roamingIndication]": {"ROAMING"},
+		"home-plmn-id": {`{"mcc": "2080", "mnc": "93"}`},
+	}
+	_, problemDetails := NSSelectionGetProcedure(query)
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusBadRequest ||
+		problemDetails.GetCause() != sbi.CauseInvalidQueryParam {
+		t.Fatalf("expected the invalid query parameters to be rejected, got %+v", problemDetails)
+	}
+	var params []string
+	for _, invalidParam := range problemDetails.InvalidParams {
+		params = append(params, invalidParam.Param)
+	}
+	expected := []string{
+		"slice-info-request-for-pdu-session[roamingIndication]",
+		"slice-info-request-for-pdu-session[sNssai][sd]",
+		"home-plmn-id[mcc]",
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("expected invalid params %v, got %v", expected, params)
+	}
+}
+
+func TestNSSelectionGetRequiresTheNfServiceConsumer(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    url.Values
+		expected []string
+	}{
+		{
+			name:     "nf-type and nf-id missing",
+			query:    url.Values{"slice-info-request-for-registration": {`{"requestedNssai":[{"sst":1}]}`}},
+			expected: []string{"nf-type", "nf-id"},
+		},
+		{
+			name: "nf-id not an NF instance ID",
+			query: url.Values{
+				"nf-type":                             {"AMF"},
+				"nf-id":                               {"amf-1"},
+				"slice-info-request-for-registration": {`{"requestedNssai":[{"sst":1}]}`},
+			},
+			expected: []string{"nf-id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, problemDetails := NSSelectionGetProcedure(tt.query)
+			if problemDetails == nil || problemDetails.GetStatus() != http.StatusBadRequest ||
+				problemDetails.GetCause() != sbi.CauseInvalidQueryParam {
+				t.Fatalf("expected the request to be rejected, got %+v", problemDetails)
+			}
+			var params []string
+			for _, invalidParam := range problemDetails.InvalidParams {
+				params = append(params, invalidParam.Param)
+			}
+			if !reflect.DeepEqual(params, tt.expected) {
+				t.Errorf("expected invalid params %v, got %v", tt.expected, params)
+			}
+		})
+	}
+}
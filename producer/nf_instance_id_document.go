@@ -29,8 +29,11 @@ func HandleNSSAIAvailabilityDelete(request *httpwrapper.Request) *httpwrapper.Re
 	logger.Nssaiavailability.Infof("Handle NSSAIAvailabilityDelete")
 
 	nfID := request.Params["nfId"]
+	if rsp := throttle(models.SERVICENAME_NNSSF_NSSAIAVAILABILITY, string(models.NFTYPE_AMF), nfID); rsp != nil {
+		return rsp
+	}
 
-	problemDetails := NSSAIAvailabilityDeleteProcedure(nfID)
+	problemDetails := NSSAIAvailabilityDeleteProcedure(nfID, request.Header.Get("If-Match"))
 
 	if problemDetails != nil {
 		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
@@ -45,16 +48,22 @@ func HandleNSSAIAvailabilityPatch(request *httpwrapper.Request) *httpwrapper.Res
 
 	nssaiAvailabilityUpdateInfo := request.Body.([]models.PatchItem)
 	nfID := request.Params["nfId"]
+	if rsp := throttle(models.SERVICENAME_NNSSF_NSSAIAVAILABILITY, string(models.NFTYPE_AMF), nfID); rsp != nil {
+		return rsp
+	}
+	if problemDetails := checkPatchItemsLimit(nssaiAvailabilityUpdateInfo); problemDetails != nil {
+		return httpwrapper.NewResponse(http.StatusBadRequest, nil, problemDetails)
+	}
 
 	// TODO: Request NfProfile of NfId from NRF
 	//       Check if NfId is valid AMF and obtain AMF Set ID
 	//       If NfId is invalid, return ProblemDetails with code 404 Not Found
 	//       If NF consumer is not authorized to update NSSAI availability, return ProblemDetails with code 403 Forbidden
 
-	response, problemDetails := NSSAIAvailabilityPatchProcedure(nssaiAvailabilityUpdateInfo, nfID)
+	response, etag, problemDetails := NSSAIAvailabilityPatchProcedure(nssaiAvailabilityUpdateInfo, nfID, request.Header.Get("If-Match"))
 
 	if response != nil {
-		return httpwrapper.NewResponse(http.StatusOK, nil, response)
+		return httpwrapper.NewResponse(http.StatusOK, http.Header{"ETag": []string{etag}}, response)
 	} else if problemDetails != nil {
 		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
 	}
@@ -69,11 +78,17 @@ func HandleNSSAIAvailabilityPut(request *httpwrapper.Request) *httpwrapper.Respo
 
 	nssaiAvailabilityInfo := request.Body.(models.NssaiAvailabilityInfo)
 	nfID := request.Params["nfId"]
+	if rsp := throttle(models.SERVICENAME_NNSSF_NSSAIAVAILABILITY, string(models.NFTYPE_AMF), nfID); rsp != nil {
+		return rsp
+	}
+	if problemDetails := checkNssaiAvailabilityInfoLimits(nssaiAvailabilityInfo); problemDetails != nil {
+		return httpwrapper.NewResponse(http.StatusBadRequest, nil, problemDetails)
+	}
 
-	response, problemDetails := NSSAIAvailabilityPutProcedure(nssaiAvailabilityInfo, nfID)
+	response, etag, problemDetails := NSSAIAvailabilityPutProcedure(nssaiAvailabilityInfo, nfID, request.Header.Get("If-Match"))
 
 	if response != nil {
-		return httpwrapper.NewResponse(http.StatusOK, nil, response)
+		return httpwrapper.NewResponse(http.StatusOK, http.Header{"ETag": []string{etag}}, response)
 	} else if problemDetails != nil {
 		return httpwrapper.NewResponse(int(problemDetails.GetStatus()), nil, problemDetails)
 	}
@@ -0,0 +1,172 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF NSSAI Availability
+ *
+ * Notifications of the NSSAI availability to the subscribed NF service consumers
+ */
+
+package producer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/omec-project/nssf/consumer"
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+const (
+	nssaiAvailabilityNotificationTimeout   = 10 * time.Second
+	nssaiAvailabilityNotificationQueueSize = 1024
+)
+
+type nssaiAvailabilityNotification struct {
+	uri            string
+	routingBinding string
+	notification   models.NssfEventNotification
+}
+
+var (
+	nssaiAvailabilityNotificationQueue chan nssaiAvailabilityNotification
+	nssaiAvailabilityNotifierOnce      sync.Once
+)
+
+// sendNssaiAvailabilityNotification sends the notification to the callback URI of the subscription,
+// TS 29.531 clause 5.3.2.4.2. Through an SCP, it is routed with the routing binding of the subscription.
+var sendNssaiAvailabilityNotification = func(uri, routingBinding string, notification models.NssfEventNotification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(consumer.WithSbiRouting(context.Background(), consumer.SbiRouting{
+		TargetNfType:   models.NFTYPE_AMF,
+		RoutingBinding: routingBinding,
+		Callback:       sbi.CallbackNssaiAvailabilityNotification,
+	}), nssaiAvailabilityNotificationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := consumer.SbiClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if bodyCloseErr := res.Body.Close(); bodyCloseErr != nil {
+			logger.Nssaiavailability.Errorf("NSSAI availability notification response body cannot close: %+v", bodyCloseErr)
+		}
+	}()
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code returned by the NF service consumer %d", res.StatusCode)
+	}
+	return nil
+}
+
+// queueNssaiAvailabilityNotification hands the notification over to the notifier, which sends the
+// notifications one at a time in the order they were queued
+func queueNssaiAvailabilityNotification(notification nssaiAvailabilityNotification) {
+	nssaiAvailabilityNotifierOnce.Do(func() {
+		nssaiAvailabilityNotificationQueue = make(chan nssaiAvailabilityNotification, nssaiAvailabilityNotificationQueueSize)
+		go func() {
+			for item := range nssaiAvailabilityNotificationQueue {
+				if err := sendNssaiAvailabilityNotification(item.uri, item.routingBinding, item.notification); err != nil {
+					logger.Nssaiavailability.Warnf("NSSAI availability notification of subscription %s failed: %+v",
+						item.notification.SubscriptionId, err)
+				}
+			}
+		}()
+	})
+	select {
+	case nssaiAvailabilityNotificationQueue <- notification:
+	default:
+		logger.Nssaiavailability.Warnf("NSSAI availability notification of subscription %s dropped, queue is full",
+			notification.notification.SubscriptionId)
+	}
+}
+
+// subscriptionReportsSnssaiStatusChange reports whether the subscription is about changes of the S-NSSAIs
+// available per TA
+func subscriptionReportsSnssaiStatusChange(subscriptionData models.NssfEventSubscriptionCreateData) bool {
+	return subscriptionData.Event == models.NSSFEVENTTYPE_SNSSAI_STATUS_CHANGE_REPORT ||
+		slices.Contains(subscriptionData.AdditionalEvents, models.NSSFEVENTTYPE_SNSSAI_STATUS_CHANGE_REPORT)
+}
+
+// notifyNssaiAvailabilityChangeLocked notifies the subscriptions about the TAs whose NSSAI availability changed,
+// with the NSSAI availability of the snapshot
+// The caller shall hold ConfigLock
+func notifyNssaiAvailabilityChangeLocked(cfg *factory.Snapshot, tais []models.Tai) {
+	if len(tais) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, subscription := range factory.NssfConfig.Subscriptions {
+		subscriptionData := subscription.SubscriptionData
+		if subscriptionData == nil || !subscriptionReportsSnssaiStatusChange(*subscriptionData) {
+			continue
+		}
+		if expiry := subscriptionData.GetExpiry(); !expiry.IsZero() && expiry.Before(now) {
+			continue
+		}
+		notification := models.NssfEventNotification{SubscriptionId: subscription.SubscriptionId}
+		for _, tai := range tais {
+			if subscriptionIncludesTai(*subscriptionData, tai) {
+				notification.AuthorizedNssaiAvailabilityData = append(notification.AuthorizedNssaiAvailabilityData,
+					util.AuthorizeOfTaFromAmfs(cfg, tai))
+			}
+		}
+		if len(notification.AuthorizedNssaiAvailabilityData) != 0 {
+			queueNssaiAvailabilityNotification(nssaiAvailabilityNotification{
+				uri:            subscriptionData.NfNssaiAvailabilityUri,
+				routingBinding: subscription.RoutingBinding,
+				notification:   notification,
+			})
+		}
+	}
+}
+
+// appendTaisOfNssaiAvailabilityData appends the TAs of the NSSAI availability which are not in the list yet
+// TAI ranges are resolved to the TAs known to the NSSF in the snapshot.
+func appendTaisOfNssaiAvailabilityData(tais []models.Tai, cfg *factory.Snapshot,
+	s []models.SupportedNssaiAvailabilityData,
+) []models.Tai {
+	seen := make(map[factory.TaiKey]bool, len(tais))
+	for _, tai := range tais {
+		seen[factory.TaiToKey(tai)] = true
+	}
+	addTai := func(tai models.Tai) {
+		if key := factory.TaiToKey(tai); !seen[key] {
+			seen[key] = true
+			tais = append(tais, tai)
+		}
+	}
+	for _, supportedNssaiAvailabilityData := range s {
+		addTai(supportedNssaiAvailabilityData.Tai)
+		for _, tai := range supportedNssaiAvailabilityData.TaiList {
+			addTai(tai)
+		}
+		for _, taiRange := range supportedNssaiAvailabilityData.TaiRangeList {
+			matcher := factory.NewTaiRangeMatcher(taiRange)
+			for _, tai := range cfg.KnownTais() {
+				if matcher.Includes(tai) {
+					addTai(tai)
+				}
+			}
+		}
+	}
+	return tais
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF NSSAI Availability
+ *
+ * Communication options of the Nnssf_NSSAIAvailability service
+ */
+
+package producer
+
+import (
+	"net/http"
+
+	"github.com/omec-project/nssf/features"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+// AcceptedContentEncodings lists the content codings the NSSF accepts in request bodies
+const AcceptedContentEncodings = "identity"
+
+// NSSAIAvailabilityOptionsProcedure returns the communication options of NSSAIAvailability (TS 29.531 clause 6.2.3.2.3.3)
+func NSSAIAvailabilityOptionsProcedure() *models.OptionsResponse {
+	optionsResponse := models.NewOptionsResponse()
+	optionsResponse.SetSupportedFeatures(features.NssaiAvailability.Supported().String())
+	return optionsResponse
+}
+
+// HandleNSSAIAvailabilityOptions - Discovers the communication options supported by the NSSF for NSSAI Availability
+func HandleNSSAIAvailabilityOptions(request *httpwrapper.Request) *httpwrapper.Response {
+	logger.Nssaiavailability.Infof("Handle NSSAIAvailabilityOptions")
+
+	header := http.Header{
+		"Accept-Encoding": []string{AcceptedContentEncodings},
+	}
+	return httpwrapper.NewResponse(http.StatusOK, header, NSSAIAvailabilityOptionsProcedure())
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/omec-project/nssf/features"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+func TestNSSAIAvailabilityOptionsReturnsCommunicationOptions(t *testing.T) {
+	httpRequest := httptest.NewRequest(http.MethodOptions, "/nnssf-nssaiavailability/v1/nssai-availability", nil)
+	request := httpwrapper.NewRequest(httpRequest, nil)
+
+	response := HandleNSSAIAvailabilityOptions(request)
+
+	if response.Status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", response.Status)
+	}
+	optionsResponse, ok := response.Body.(*models.OptionsResponse)
+	if !ok {
+		t.Fatalf("expected an OptionsResponse, got %T", response.Body)
+	}
+	if expected := features.NssaiAvailability.Supported().String(); optionsResponse.GetSupportedFeatures() != expected {
+		t.Errorf("expected supportedFeatures %q, got %q", expected, optionsResponse.GetSupportedFeatures())
+	}
+	if acceptEncoding := response.Header.Get("Accept-Encoding"); acceptEncoding != AcceptedContentEncodings {
+		t.Errorf("expected Accept-Encoding %q, got %q", AcceptedContentEncodings, acceptEncoding)
+	}
+}
@@ -17,93 +17,244 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/omec-project/nssf/factory"
 	"github.com/omec-project/nssf/logger"
+	stats "github.com/omec-project/nssf/metrics"
 	"github.com/omec-project/nssf/util"
 	"github.com/omec-project/openapi/v2/models"
 	"github.com/omec-project/openapi/v2/utils"
 )
 
-// NSSAIAvailability DELETE method
-func NSSAIAvailabilityDeleteProcedure(nfId string) *models.ProblemDetails {
-	var problemDetails *models.ProblemDetails
-	factory.ConfigLock.Lock()
-	defer factory.ConfigLock.Unlock()
-	for i, amfConfig := range factory.NssfConfig.Configuration.AmfList {
-		if amfConfig.NfId == nfId {
-			factory.NssfConfig.Configuration.AmfList = append(
-				factory.NssfConfig.Configuration.AmfList[:i],
-				factory.NssfConfig.Configuration.AmfList[i+1:]...)
-			return nil
+// lastAmfAvailabilityVersion is the version given to the latest update of an NSSAI availability resource
+// It starts from the start-up time, so that ETags are not reused after a restart.
+// Guarded by ConfigLock
+var lastAmfAvailabilityVersion = uint64(time.Now().UnixNano())
+
+// amfAvailabilityETag returns the entity tag of the NSSAI availability resource of the AMF
+func amfAvailabilityETag(amfConfig factory.AmfConfig) string {
+	return strconv.Quote(strconv.FormatUint(amfConfig.Version, 10))
+}
+
+// checkIfMatch evaluates the If-Match header against the NSSAI availability resource of the AMF, RFC 9110 clause 13.1.1
+// It holds when the header is absent, or when the resource exists and is `*` or one of the listed entity tags.
+// Weak entity tags never match, as the comparison is strong.
+func checkIfMatch(ifMatch string, amfConfig factory.AmfConfig, found bool) bool {
+	if ifMatch == "" {
+		return true
+	}
+	if !found {
+		return false
+	}
+	etag := amfAvailabilityETag(amfConfig)
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || tag == etag {
+			return true
 		}
 	}
+	return false
+}
 
-	problemDetails = utils.ProblemDetails(
+func amfNotFound(nfId string) *models.ProblemDetails {
+	return utils.ProblemDetails(
 		util.UNSUPPORTED_RESOURCE,
 		http.StatusNotFound,
 		fmt.Sprintf("AMF ID '%s' does not exist", nfId),
 	)
-	return problemDetails
 }
 
-// NSSAIAvailability PATCH method
-func NSSAIAvailabilityPatchProcedure(nssaiAvailabilityUpdateInfo []models.PatchItem, nfId string) (
-	*models.AuthorizedNssaiAvailabilityInfo, *models.ProblemDetails,
-) {
-	response := models.NewAuthorizedNssaiAvailabilityInfoWithDefaults()
-	var amfIdx int
-	var original []byte
-	hitAmf := false
-	factory.ConfigLock.RLock()
-	for amfIdx, amfConfig := range factory.NssfConfig.Configuration.AmfList {
+func amfPreconditionFailed(nfId string) *models.ProblemDetails {
+	return utils.ProblemDetails(
+		util.PRECONDITION_FAILED,
+		http.StatusPreconditionFailed,
+		fmt.Sprintf("NSSAI availability of AMF ID '%s' does not match If-Match", nfId),
+	)
+}
+
+// findAmfConfigLocked returns the NSSAI availability of the AMF
+// The caller shall hold ConfigLock
+func findAmfConfigLocked(nfId string) (factory.AmfConfig, bool) {
+	for _, amfConfig := range factory.NssfConfig.Configuration.AmfList {
 		if amfConfig.NfId == nfId {
-			// Since json-patch package does not have idea of optional field of datatype,
-			// provide with null or empty value instead of omitting the field
-			var temp []models.SupportedNssaiAvailabilityData
-			configData, err := json.Marshal(factory.NssfConfig.Configuration.AmfList[amfIdx].SupportedNssaiAvailabilityData)
-			if err != nil {
-				factory.ConfigLock.RUnlock()
-				logger.Nssaiavailability.Errorf("marshal error in NSSAIAvailabilityPatchProcedure: %+v", err)
-				return nil, utils.ProblemDetailsSystemFailure(err.Error())
-			}
-			if err = json.Unmarshal(configData, &temp); err != nil {
-				factory.ConfigLock.RUnlock()
-				logger.Nssaiavailability.Errorf("unmarshal error in NSSAIAvailabilityPatchProcedure: %+v", err)
-				return nil, utils.ProblemDetailsSystemFailure(err.Error())
-			}
-			const dummyString string = "DUMMY"
-			for i := range temp {
-				for j := range temp[i].SupportedSnssaiList {
-					if temp[i].SupportedSnssaiList[j].GetSd() == "" {
-						temp[i].SupportedSnssaiList[j].SetSd(dummyString)
-					}
-				}
-			}
-			original, err = json.Marshal(temp)
-			if err != nil {
-				logger.Nssaiavailability.Errorf("marshal error in NSSAIAvailabilityPatchProcedure: %+v", err)
-				factory.ConfigLock.RUnlock()
-				return nil, utils.ProblemDetailsSystemFailure(err.Error())
-			}
-			original = bytes.ReplaceAll(original, []byte(dummyString), []byte(""))
+			return amfConfig, true
+		}
+	}
+	return factory.AmfConfig{}, false
+}
 
-			// original, _ = json.Marshal(factory.NssfConfig.Configuration.AmfList[amfIdx].SupportedNssaiAvailabilityData)
+// NSSAIAvailability DELETE method
+// The NSSAI availability is only deleted if it matches `ifMatch`, when given
+func NSSAIAvailabilityDeleteProcedure(nfId string, ifMatch string) *models.ProblemDetails {
+	factory.ConfigLock.Lock()
+	defer factory.ConfigLock.Unlock()
+	amfConfig, found := findAmfConfigLocked(nfId)
+	if !checkIfMatch(ifMatch, amfConfig, found) {
+		return amfPreconditionFailed(nfId)
+	}
+	if !found {
+		return amfNotFound(nfId)
+	}
+	removeAmfConfigLocked(nfId)
+	return nil
+}
 
+// storeAmfConfigLocked replaces the NSSAI availability of the AMF, or adds it if the AMF is unknown, with a new
+// version, and returns the published snapshot and the stored NSSAI availability
+// The caller shall hold ConfigLock
+func storeAmfConfigLocked(amfConfig factory.AmfConfig) (*factory.Snapshot, factory.AmfConfig) {
+	lastAmfAvailabilityVersion++
+	amfConfig.Version = lastAmfAvailabilityVersion
+	// The AMF contacted the NSSF, which only resumes its NSSAI availability if the NRF did not suspend it
+	amfConfig.Suspended, amfConfig.SuspensionCause = false, ""
+	if previous, found := findAmfConfigLocked(amfConfig.NfId); found && previous.SuspensionCause == factory.AmfSuspendedByNrf {
+		amfConfig.Suspended, amfConfig.SuspensionCause = true, previous.SuspensionCause
+	}
+	recordAmfContact(amfConfig.NfId)
+	return putAmfConfigLocked(amfConfig), amfConfig
+}
+
+// putAmfConfigLocked replaces the AMF, or adds it if the AMF is unknown, publishes the configuration and
+// notifies the subscriptions about the TAs of the old and new NSSAI availability of the AMF
+// The AMF list is copied rather than modified, so that the published configuration snapshots do not change.
+// The caller shall hold ConfigLock
+func putAmfConfigLocked(amfConfig factory.AmfConfig) *factory.Snapshot {
+	var tais []models.Tai
+	amfList := factory.NssfConfig.Configuration.AmfList
+	newAmfList := make([]factory.AmfConfig, 0, len(amfList)+1)
+	hitAmf := false
+	for _, item := range amfList {
+		if item.NfId == amfConfig.NfId && !hitAmf {
+			tais = appendTaisOfNssaiAvailabilityData(tais, factory.CurrentSnapshot(), item.SupportedNssaiAvailabilityData)
+			item = amfConfig
 			hitAmf = true
-			break
 		}
+		newAmfList = append(newAmfList, item)
 	}
-	factory.ConfigLock.RUnlock()
 	if !hitAmf {
-		problemDetails := utils.ProblemDetails(
-			util.UNSUPPORTED_RESOURCE,
-			http.StatusNotFound,
-			fmt.Sprintf("AMF ID '%s' does not exist", nfId),
-		)
-		return nil, problemDetails
+		newAmfList = append(newAmfList, amfConfig)
+	}
+	factory.NssfConfig.Configuration.AmfList = newAmfList
+	cfg := factory.PublishSnapshotLocked()
+	notifyNssaiAvailabilityChangeLocked(cfg,
+		appendTaisOfNssaiAvailabilityData(tais, cfg, amfConfig.SupportedNssaiAvailabilityData))
+	return cfg
+}
+
+// removeAmfConfigLocked removes the NSSAI availability of the AMF, notifies the subscriptions about its TAs,
+// and reports whether the AMF was known
+// The caller shall hold ConfigLock
+func removeAmfConfigLocked(nfId string) bool {
+	amfList := factory.NssfConfig.Configuration.AmfList
+	for i, amfConfig := range amfList {
+		if amfConfig.NfId == nfId {
+			tais := appendTaisOfNssaiAvailabilityData(nil, factory.CurrentSnapshot(), amfConfig.SupportedNssaiAvailabilityData)
+			newAmfList := make([]factory.AmfConfig, 0, len(amfList)-1)
+			newAmfList = append(append(newAmfList, amfList[:i]...), amfList[i+1:]...)
+			factory.NssfConfig.Configuration.AmfList = newAmfList
+			recordRemovedAmfSetsLocked(amfConfig)
+			forgetAmfContact(nfId)
+			notifyNssaiAvailabilityChangeLocked(factory.PublishSnapshotLocked(), tais)
+			return true
+		}
+	}
+	return false
+}
+
+// recordRemovedAmfSetsLocked records the AMF Sets of a removed AMF, so that the NSSAI availability of the
+// configuration file does not apply to them again
+// The caller shall hold ConfigLock
+func recordRemovedAmfSetsLocked(amfConfig factory.AmfConfig) {
+	amfSetIds := slices.Clone(factory.CurrentSnapshot().AmfSetIdsOfAmf(amfConfig.NfId))
+	if amfConfig.AmfSetId != "" {
+		amfSetIds = append(amfSetIds, amfConfig.AmfSetId)
+	}
+	configuration := factory.NssfConfig.Configuration
+	for _, amfSetId := range amfSetIds {
+		if !slices.Contains(configuration.RemovedAmfSetIds, amfSetId) {
+			configuration.RemovedAmfSetIds = append(slices.Clip(configuration.RemovedAmfSetIds), amfSetId)
+		}
 	}
+}
+
+// NSSAIAvailability PATCH method
+// The patch is applied to the NSSAI availability of the AMF in one transaction, and only if it matches
+// `ifMatch`, when given. It returns the entity tag of the updated NSSAI availability.
+func NSSAIAvailabilityPatchProcedure(nssaiAvailabilityUpdateInfo []models.PatchItem, nfId string, ifMatch string) (
+	*models.AuthorizedNssaiAvailabilityInfo, string, *models.ProblemDetails,
+) {
+	response := models.NewAuthorizedNssaiAvailabilityInfoWithDefaults()
+
+	factory.ConfigLock.Lock()
+	defer factory.ConfigLock.Unlock()
+	amfConfig, found := findAmfConfigLocked(nfId)
+	if !checkIfMatch(ifMatch, amfConfig, found) {
+		return nil, "", amfPreconditionFailed(nfId)
+	}
+	if !found {
+		return nil, "", amfNotFound(nfId)
+	}
+
+	supportedNssaiAvailabilityData, problemDetails := patchSupportedNssaiAvailabilityData(
+		amfConfig.SupportedNssaiAvailabilityData, nssaiAvailabilityUpdateInfo)
+	if problemDetails != nil {
+		return nil, "", problemDetails
+	}
+	supportedNssaiAvailabilityData, problemDetails = authorizeNssaiAvailability(factory.CurrentSnapshot(), nfId,
+		supportedNssaiAvailabilityData)
+	if problemDetails != nil {
+		return nil, "", problemDetails
+	}
+	amfConfig.SupportedNssaiAvailabilityData = supportedNssaiAvailabilityData
+	cfg, amfConfig := storeAmfConfigLocked(amfConfig)
+
+	// Return all authorized NSSAI availability information
+	var err error
+	response.AuthorizedNssaiAvailabilityData, err = util.AuthorizeOfAmfFromConfig(cfg, nfId)
+	if err != nil {
+		logger.Nssaiavailability.Errorf("util AuthorizeOfAmfFromConfig error in NSSAIAvailabilityPatchProcedure: %+v", err)
+	}
+
+	// TODO: Return authorized NSSAI availability information of updated TAI only
+
+	return response, amfAvailabilityETag(amfConfig), nil
+}
+
+// patchSupportedNssaiAvailabilityData applies the JSON patch to the NSSAI availability
+// The result is decoded into a new list, as the current one is shared with the published configuration snapshots.
+func patchSupportedNssaiAvailabilityData(current []models.SupportedNssaiAvailabilityData,
+	nssaiAvailabilityUpdateInfo []models.PatchItem,
+) ([]models.SupportedNssaiAvailabilityData, *models.ProblemDetails) {
+	// Since json-patch package does not have idea of optional field of datatype,
+	// provide with null or empty value instead of omitting the field
+	var temp []models.SupportedNssaiAvailabilityData
+	configData, err := json.Marshal(current)
+	if err != nil {
+		logger.Nssaiavailability.Errorf("marshal error in NSSAIAvailabilityPatchProcedure: %+v", err)
+		return nil, utils.ProblemDetailsSystemFailure(err.Error())
+	}
+	if err = json.Unmarshal(configData, &temp); err != nil {
+		logger.Nssaiavailability.Errorf("unmarshal error in NSSAIAvailabilityPatchProcedure: %+v", err)
+		return nil, utils.ProblemDetailsSystemFailure(err.Error())
+	}
+	const dummyString string = "DUMMY"
+	for i := range temp {
+		for j := range temp[i].SupportedSnssaiList {
+			if temp[i].SupportedSnssaiList[j].GetSd() == "" {
+				temp[i].SupportedSnssaiList[j].SetSd(dummyString)
+			}
+		}
+	}
+	original, err := json.Marshal(temp)
+	if err != nil {
+		logger.Nssaiavailability.Errorf("marshal error in NSSAIAvailabilityPatchProcedure: %+v", err)
+		return nil, utils.ProblemDetailsSystemFailure(err.Error())
+	}
+	original = bytes.ReplaceAll(original, []byte(dummyString), []byte(""))
 
 	// TODO: Check if returned HTTP status codes or problem details are proper when errors occur
 
@@ -124,86 +275,84 @@ func NSSAIAvailabilityPatchProcedure(nssaiAvailabilityUpdateInfo []models.PatchI
 
 	patch, err := jsonpatch.DecodePatch(patchJSON)
 	if err != nil {
-		problemDetails := utils.ProblemDetailsMalformedRequestSyntax(err.Error())
-		return nil, problemDetails
+		return nil, utils.ProblemDetailsMalformedRequestSyntax(err.Error())
 	}
 
 	modified, err := patch.Apply(original)
 	if err != nil {
-		problemDetails := utils.ProblemDetails(util.INVALID_REQUEST, http.StatusConflict, err.Error())
-		return nil, problemDetails
+		return nil, utils.ProblemDetails(util.INVALID_REQUEST, http.StatusConflict, err.Error())
 	}
 
-	factory.ConfigLock.Lock()
-	err = json.Unmarshal(modified, &factory.NssfConfig.Configuration.AmfList[amfIdx].SupportedNssaiAvailabilityData)
-	factory.ConfigLock.Unlock()
-	if err != nil {
-		problemDetails := utils.ProblemDetails(util.INVALID_REQUEST, http.StatusBadRequest, err.Error())
-		return nil, problemDetails
+	var supportedNssaiAvailabilityData []models.SupportedNssaiAvailabilityData
+	if err = json.Unmarshal(modified, &supportedNssaiAvailabilityData); err != nil {
+		return nil, utils.ProblemDetails(util.INVALID_REQUEST, http.StatusBadRequest, err.Error())
 	}
+	return supportedNssaiAvailabilityData, nil
+}
 
-	// Return all authorized NSSAI availability information
-	response.AuthorizedNssaiAvailabilityData, err = util.AuthorizeOfAmfFromConfig(nfId)
-	if err != nil {
-		logger.Nssaiavailability.Errorf("util AuthorizeOfAmfFromConfig error in NSSAIAvailabilityPatchProcedure: %+v", err)
+// authorizeNssaiAvailability keeps the S-NSSAIs of the NSSAI availability which the AMF is authorized to provide
+// The request is only rejected if none of the S-NSSAIs provided is authorized.
+func authorizeNssaiAvailability(cfg *factory.Snapshot, nfId string, s []models.SupportedNssaiAvailabilityData) (
+	[]models.SupportedNssaiAvailabilityData, *models.ProblemDetails,
+) {
+	authorized, rejected := util.AuthorizeNssaiAvailabilityData(cfg, nfId, s)
+	for _, rejectedSnssai := range rejected {
+		logger.Nssaiavailability.Warnf("S-NSSAI %s provided by AMF %s in TA %s is not authorized: %s",
+			factory.SnssaiKeyString(factory.SnssaiToKey(rejectedSnssai.Snssai)), nfId,
+			factory.TaiString(rejectedSnssai.Tai), rejectedSnssai.Cause)
+		stats.IncrementNssfRejectedSnssaisStats(rejectedSnssai.Cause)
 	}
-
-	// TODO: Return authorized NSSAI availability information of updated TAI only
-
-	return response, nil
+	if len(rejected) == 0 {
+		return authorized, nil
+	}
+	for _, supportedNssaiAvailabilityData := range authorized {
+		if len(supportedNssaiAvailabilityData.SupportedSnssaiList) != 0 {
+			return authorized, nil
+		}
+	}
+	problemDetails := utils.ProblemDetails(
+		util.UNSUPPORTED_RESOURCE,
+		http.StatusForbidden,
+		"none of the S-NSSAIs provided is authorized",
+	)
+	problemDetails.SetCause(utils.CauseSnssaiNotSupported)
+	return nil, problemDetails
 }
 
 // NSSAIAvailability PUT method
-func NSSAIAvailabilityPutProcedure(nssaiAvailabilityInfo models.NssaiAvailabilityInfo, nfId string) (
-	*models.AuthorizedNssaiAvailabilityInfo, *models.ProblemDetails,
+// The NSSAI availability is only replaced if it matches `ifMatch`, when given. It returns the entity tag of
+// the new NSSAI availability.
+func NSSAIAvailabilityPutProcedure(nssaiAvailabilityInfo models.NssaiAvailabilityInfo, nfId string, ifMatch string) (
+	*models.AuthorizedNssaiAvailabilityInfo, string, *models.ProblemDetails,
 ) {
 	response := models.NewAuthorizedNssaiAvailabilityInfoWithDefaults()
 
-	for _, s := range nssaiAvailabilityInfo.SupportedNssaiAvailabilityData {
-		if !util.CheckSupportedNssaiInPlmn(s.SupportedSnssaiList, s.Tai.PlmnId) {
-			problemDetails := utils.ProblemDetails(
-				util.UNSUPPORTED_RESOURCE,
-				http.StatusForbidden,
-				"S-NSSAI in Requested NSSAI is not supported in PLMN",
-			)
-			problemDetails.SetCause(utils.CauseSnssaiNotSupported)
-			return nil, problemDetails
-		}
-	}
-
-	// TODO: Currently authorize all the provided S-NSSAIs
-	//       Take some issue into consideration e.g. operator policies
-
-	hitAmf := false
-	// Find AMF configuration of given NfId
-	// If found, then update the SupportedNssaiAvailabilityData
 	factory.ConfigLock.Lock()
-	for i, amfConfig := range factory.NssfConfig.Configuration.AmfList {
-		if amfConfig.NfId == nfId {
-			factory.NssfConfig.Configuration.AmfList[i].SupportedNssaiAvailabilityData = nssaiAvailabilityInfo.SupportedNssaiAvailabilityData
-
-			hitAmf = true
-			break
-		}
+	defer factory.ConfigLock.Unlock()
+	if amfConfig, found := findAmfConfigLocked(nfId); !checkIfMatch(ifMatch, amfConfig, found) {
+		return nil, "", amfPreconditionFailed(nfId)
 	}
-	factory.ConfigLock.Unlock()
 
-	// If no AMF record is found, create a new one
-	if !hitAmf {
-		var amfConfig factory.AmfConfig
-		amfConfig.NfId = nfId
-		amfConfig.SupportedNssaiAvailabilityData = nssaiAvailabilityInfo.SupportedNssaiAvailabilityData
-		factory.ConfigLock.Lock()
-		factory.NssfConfig.Configuration.AmfList = append(factory.NssfConfig.Configuration.AmfList, amfConfig)
-		factory.ConfigLock.Unlock()
+	supportedNssaiAvailabilityData, problemDetails := authorizeNssaiAvailability(factory.CurrentSnapshot(), nfId,
+		nssaiAvailabilityInfo.SupportedNssaiAvailabilityData)
+	if problemDetails != nil {
+		return nil, "", problemDetails
 	}
 
+	// Update the SupportedNssaiAvailabilityData of the AMF, or create a new AMF record if none is found
+	var amfConfig factory.AmfConfig
+	amfConfig.NfId = nfId
+	amfConfig.SupportedNssaiAvailabilityData = supportedNssaiAvailabilityData
+	// The NSSAI availability is also aggregated into the one of the AMF Set, when given
+	amfConfig.AmfSetId = nssaiAvailabilityInfo.GetAmfSetId()
+	cfg, amfConfig := storeAmfConfigLocked(amfConfig)
+
 	// Return all authorized NSSAI availability information
 	// a.AuthorizedNssaiAvailabilityData, _ = authorizeOfAmfFromConfig(nfId)
 
 	// Return authorized NSSAI availability information of updated TAI only
 	for _, s := range nssaiAvailabilityInfo.SupportedNssaiAvailabilityData {
-		authorizedNssaiAvailabilityData, err := util.AuthorizeOfAmfTaFromConfig(nfId, s.Tai)
+		authorizedNssaiAvailabilityData, err := util.AuthorizeOfAmfTaFromConfig(cfg, nfId, s.Tai)
 		if err == nil {
 			response.AuthorizedNssaiAvailabilityData = append(response.AuthorizedNssaiAvailabilityData, authorizedNssaiAvailabilityData)
 		} else {
@@ -211,5 +360,5 @@ func NSSAIAvailabilityPutProcedure(nssaiAvailabilityInfo models.NssaiAvailabilit
 		}
 	}
 
-	return response, nil
+	return response, amfAvailabilityETag(amfConfig), nil
 }
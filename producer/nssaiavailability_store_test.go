@@ -0,0 +1,169 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/openapi/v2"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+func setAvailabilityTestConfig(t *testing.T) {
+	setTestConfig(t, &factory.Configuration{
+		SupportedNssaiInPlmnList: factory.SupportedNssaiInPlmn{
+			testServingPlmnId: {
+				factory.SnssaiToKey(testServingSnssai1): {},
+				factory.SnssaiToKey(testServingSnssai2): {},
+			},
+		},
+		AmfList: []factory.AmfConfig{
+			{
+				NfId: "amf-1",
+				SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+					{Tai: testServingTai, SupportedSnssaiList: []models.Snssai{testServingSnssai1}},
+				},
+			},
+			{
+				NfId: "amf-2",
+				SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+					{Tai: testServingTai, SupportedSnssaiList: []models.Snssai{testServingSnssai1}},
+				},
+			},
+		},
+	})
+}
+
+func newNssaiAvailabilityInfo(snssai models.Snssai) models.NssaiAvailabilityInfo {
+	return models.NssaiAvailabilityInfo{
+		SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+			{Tai: testServingTai, SupportedSnssaiList: []models.Snssai{snssai}},
+		},
+	}
+}
+
+func TestNSSAIAvailabilityPutHonoursIfMatch(t *testing.T) {
+	setAvailabilityTestConfig(t)
+
+	_, etag, problemDetails := NSSAIAvailabilityPutProcedure(newNssaiAvailabilityInfo(testServingSnssai2), "amf-1", "")
+	if problemDetails != nil || etag == "" {
+		t.Fatalf("expected the NSSAI availability to be replaced with an ETag, got %q %+v", etag, problemDetails)
+	}
+
+	_, newEtag, problemDetails := NSSAIAvailabilityPutProcedure(newNssaiAvailabilityInfo(testServingSnssai1), "amf-1", etag)
+	if problemDetails != nil || newEtag == etag {
+		t.Fatalf("expected a new ETag after a matching update, got %q %+v", newEtag, problemDetails)
+	}
+
+	_, _, problemDetails = NSSAIAvailabilityPutProcedure(newNssaiAvailabilityInfo(testServingSnssai2), "amf-1", etag)
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a stale ETag, got %+v", problemDetails)
+	}
+	amfConfig, _ := factory.CurrentSnapshot().Amf("amf-1")
+	if amfConfig.SupportedNssaiAvailabilityData[0].SupportedSnssaiList[0].GetSd() != testServingSnssai1.GetSd() {
+		t.Fatalf("expected the NSSAI availability to be left unchanged, got %+v", amfConfig)
+	}
+
+	_, _, problemDetails = NSSAIAvailabilityPutProcedure(newNssaiAvailabilityInfo(testServingSnssai2), "amf-3", "*")
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for If-Match of an unknown AMF, got %+v", problemDetails)
+	}
+}
+
+func TestNSSAIAvailabilityPatchUpdatesItsOwnAmf(t *testing.T) {
+	setAvailabilityTestConfig(t)
+	patchItems := func() []models.PatchItem {
+		return []models.PatchItem{{
+			Op:    models.PATCHOPERATION_REPLACE,
+			Path:  "/0/supportedSnssaiList/0",
+			Value: map[string]interface{}{"sst": float64(1), "sd": testServingSnssai2.GetSd()},
+		}}
+	}
+
+	_, _, problemDetails := NSSAIAvailabilityPatchProcedure(patchItems(), "amf-2", `"1", W/"0"`)
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for non-matching ETags, got %+v", problemDetails)
+	}
+
+	_, etag, problemDetails := NSSAIAvailabilityPatchProcedure(patchItems(), "amf-2", `"0"`)
+	if problemDetails != nil || etag == "" {
+		t.Fatalf("expected the NSSAI availability to be patched, got %+v", problemDetails)
+	}
+
+	cfg := factory.CurrentSnapshot()
+	amf1, _ := cfg.Amf("amf-1")
+	amf2, _ := cfg.Amf("amf-2")
+	if amf1.SupportedNssaiAvailabilityData[0].SupportedSnssaiList[0].GetSd() != testServingSnssai1.GetSd() {
+		t.Errorf("expected amf-1 to be left unchanged, got %+v", amf1)
+	}
+	if amf2.SupportedNssaiAvailabilityData[0].SupportedSnssaiList[0].GetSd() != testServingSnssai2.GetSd() {
+		t.Errorf("expected amf-2 to be patched, got %+v", amf2)
+	}
+}
+
+func TestNSSAIAvailabilityDeleteHonoursIfMatch(t *testing.T) {
+	setAvailabilityTestConfig(t)
+
+	if problemDetails := NSSAIAvailabilityDeleteProcedure("amf-1", `"1"`); problemDetails == nil ||
+		problemDetails.GetStatus() != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a non-matching ETag, got %+v", problemDetails)
+	}
+	if problemDetails := NSSAIAvailabilityDeleteProcedure("amf-1", "*"); problemDetails != nil {
+		t.Fatalf("expected the NSSAI availability to be deleted, got %+v", problemDetails)
+	}
+	if problemDetails := NSSAIAvailabilityDeleteProcedure("amf-1", ""); problemDetails == nil ||
+		problemDetails.GetStatus() != http.StatusNotFound {
+		t.Fatalf("expected 404 for a deleted AMF, got %+v", problemDetails)
+	}
+}
+
+func TestNSSAIAvailabilityPutAuthorizesPartially(t *testing.T) {
+	setAvailabilityTestConfig(t)
+	unknownSnssai := models.Snssai{Sst: 1, Sd: openapi.PtrString("0000ff")}
+	info := models.NssaiAvailabilityInfo{
+		SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+			{Tai: testServingTai, SupportedSnssaiList: []models.Snssai{testServingSnssai1, unknownSnssai}},
+		},
+	}
+
+	response, _, problemDetails := NSSAIAvailabilityPutProcedure(info, "amf-1", "")
+	if problemDetails != nil {
+		t.Fatalf("expected the supported S-NSSAI to be authorized, got %+v", problemDetails)
+	}
+	if len(response.AuthorizedNssaiAvailabilityData) != 1 ||
+		!reflect.DeepEqual(response.AuthorizedNssaiAvailabilityData[0].SupportedSnssaiList, []models.Snssai{testServingSnssai1}) {
+		t.Fatalf("expected only the supported S-NSSAI to be authorized, got %+v", response.AuthorizedNssaiAvailabilityData)
+	}
+
+	info.SupportedNssaiAvailabilityData[0].SupportedSnssaiList = []models.Snssai{unknownSnssai}
+	if _, _, problemDetails = NSSAIAvailabilityPutProcedure(info, "amf-1", ""); problemDetails == nil ||
+		problemDetails.GetStatus() != http.StatusForbidden {
+		t.Fatalf("expected 403 when no S-NSSAI is authorized, got %+v", problemDetails)
+	}
+}
+
+func TestNSSAIAvailabilityPutUpdatesAmfSet(t *testing.T) {
+	setAvailabilityTestConfig(t)
+	info := newNssaiAvailabilityInfo(testServingSnssai2)
+	info.SetAmfSetId("208-93-01-001")
+
+	if _, _, problemDetails := NSSAIAvailabilityPutProcedure(info, "amf-3", ""); problemDetails != nil {
+		t.Fatalf("expected the NSSAI availability to be stored, got %+v", problemDetails)
+	}
+	info = newNssaiAvailabilityInfo(testServingSnssai1)
+	info.SetAmfSetId("208-93-01-001")
+	if _, _, problemDetails := NSSAIAvailabilityPutProcedure(info, "amf-4", ""); problemDetails != nil {
+		t.Fatalf("expected the NSSAI availability to be stored, got %+v", problemDetails)
+	}
+
+	amfSetConfig, found := factory.CurrentSnapshot().AmfSet("208-93-01-001")
+	if !found || len(amfSetConfig.SupportedNssaiAvailabilityData) != 1 ||
+		!reflect.DeepEqual(amfSetConfig.SupportedNssaiAvailabilityData[0].SupportedSnssaiList,
+			[]models.Snssai{testServingSnssai2, testServingSnssai1}) {
+		t.Fatalf("expected the NSSAI availability of both members in the AMF Set, got %+v", amfSetConfig)
+	}
+}
@@ -46,17 +46,17 @@ func getUnusedSubscriptionIDLocked() (string, error) {
 }
 
 // NSSAIAvailability subscription POST method
-func NSSAIAvailabilityPostProcedure(createData models.NssfEventSubscriptionCreateData) (
+// The notifications are routed with the routing binding, when sent through an SCP.
+func NSSAIAvailabilityPostProcedure(createData models.NssfEventSubscriptionCreateData, routingBinding string) (
 	*models.NssfEventSubscriptionCreatedData, *models.ProblemDetails,
 ) {
 	response := models.NewNssfEventSubscriptionCreatedDataWithDefaults()
 
 	var subscription factory.Subscription
 	factory.ConfigLock.Lock()
-	defer factory.ConfigLock.Unlock()
-
 	tempID, err := getUnusedSubscriptionIDLocked()
 	if err != nil {
+		factory.ConfigLock.Unlock()
 		logger.Nssaiavailability.Warnln(err.Error())
 		problemDetails := utils.ProblemDetails(util.UNSUPPORTED_RESOURCE, http.StatusNotFound, err.Error())
 		return nil, problemDetails
@@ -64,19 +64,38 @@ func NSSAIAvailabilityPostProcedure(createData models.NssfEventSubscriptionCreat
 
 	subscription.SubscriptionId = tempID
 	subscription.SubscriptionData = &createData
+	subscription.RoutingBinding = routingBinding
 
 	factory.NssfConfig.Subscriptions = append(factory.NssfConfig.Subscriptions, subscription)
+	factory.ConfigLock.Unlock()
 
 	response.SetSubscriptionId(subscription.SubscriptionId)
 	timeExpiry := subscription.SubscriptionData.GetExpiry()
 	if !timeExpiry.IsZero() {
 		response.SetExpiry(timeExpiry)
 	}
-	response.SetAuthorizedNssaiAvailabilityData(util.AuthorizeOfTaListFromConfig(subscription.SubscriptionData.GetTaiList()))
+	// TAI ranges are resolved to the TAs known to the NSSF
+	response.SetAuthorizedNssaiAvailabilityData(util.AuthorizeOfTaListFromConfig(factory.CurrentSnapshot(),
+		subscription.SubscriptionData.GetTaiList(), subscription.SubscriptionData.GetTaiRangeList()))
 
 	return response, nil
 }
 
+// subscriptionIncludesTai reports whether the subscription is about the TA, given in its TAI list or a TAI range
+func subscriptionIncludesTai(subscriptionData models.NssfEventSubscriptionCreateData, tai models.Tai) bool {
+	for _, item := range subscriptionData.TaiList {
+		if item.PlmnId == tai.PlmnId && item.Tac == tai.Tac {
+			return true
+		}
+	}
+	for _, taiRange := range subscriptionData.TaiRangeList {
+		if util.CheckTaiInRange(tai, taiRange) {
+			return true
+		}
+	}
+	return false
+}
+
 func NSSAIAvailabilityUnsubscribeProcedure(subscriptionId string) *models.ProblemDetails {
 	var problemDetails *models.ProblemDetails
 
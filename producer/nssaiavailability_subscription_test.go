@@ -0,0 +1,65 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"testing"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/openapi/v2"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+func TestNSSAIAvailabilityPostResolvesTaiRanges(t *testing.T) {
+	accessType := models.ACCESSTYPE__3_GPP_ACCESS
+	inRangeTai := models.Tai{PlmnId: testServingPlmnId, Tac: "000010"}
+	reportedTai := models.Tai{PlmnId: testServingPlmnId, Tac: "000020"}
+	outOfRangeTai := models.Tai{PlmnId: testServingPlmnId, Tac: "000100"}
+	setTestConfig(t, &factory.Configuration{
+		TaList: []factory.TaConfig{
+			{Tai: &inRangeTai, AccessType: &accessType, SupportedSnssaiList: []models.Snssai{testServingSnssai1}},
+			{Tai: &outOfRangeTai, AccessType: &accessType, SupportedSnssaiList: []models.Snssai{testServingSnssai1}},
+			{PlmnId: &testServingPlmnId, AccessType: &accessType, SupportedSnssaiList: []models.Snssai{testServingSnssai2}},
+		},
+		AmfList: []factory.AmfConfig{{
+			NfId: "amf-1",
+			SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+				{Tai: reportedTai, SupportedSnssaiList: []models.Snssai{testServingSnssai2}},
+			},
+		}},
+	})
+	taiRange := models.TaiRange{
+		PlmnId:       testServingPlmnId,
+		TacRangeList: []models.TacRange{{Start: openapi.PtrString("000001"), End: openapi.PtrString("0000ff")}},
+	}
+
+	createData := models.NssfEventSubscriptionCreateData{
+		NfNssaiAvailabilityUri: "http://amf:29518/notify",
+		Event:                  models.NSSFEVENTTYPE_SNSSAI_STATUS_CHANGE_REPORT,
+		TaiRangeList:           []models.TaiRange{taiRange},
+	}
+	response, problemDetails := NSSAIAvailabilityPostProcedure(createData, "")
+	if problemDetails != nil {
+		t.Fatalf("unexpected problem %+v", problemDetails)
+	}
+
+	authorized := response.GetAuthorizedNssaiAvailabilityData()
+	if len(authorized) != 2 {
+		t.Fatalf("expected authorized data of the 2 known TAs in range, got %+v", authorized)
+	}
+	expected := map[string]factory.SnssaiKey{
+		factory.TaiString(inRangeTai):  factory.SnssaiToKey(testServingSnssai1),
+		factory.TaiString(reportedTai): factory.SnssaiToKey(testServingSnssai2),
+	}
+	for _, data := range authorized {
+		snssai, found := expected[factory.TaiString(data.Tai)]
+		if !found || len(data.SupportedSnssaiList) != 1 || factory.SnssaiToKey(data.SupportedSnssaiList[0]) != snssai {
+			t.Errorf("unexpected authorized data %+v", data)
+		}
+	}
+
+	if !subscriptionIncludesTai(createData, reportedTai) || subscriptionIncludesTai(createData, outOfRangeTai) {
+		t.Error("expected the subscription to include the TAs of its TAI range only")
+	}
+}
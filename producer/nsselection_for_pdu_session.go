@@ -39,7 +39,7 @@ func nsselectionForPduSession(param NsselectionQueryParameter,
 	var status int
 	if param.HomePlmnId != nil {
 		// Check whether UE's Home PLMN is supported when UE is a roamer
-		if !util.CheckSupportedHplmn(*param.HomePlmnId) {
+		if !util.CheckSupportedHplmn(param.config, *param.HomePlmnId) {
 			rejectedNssaiInPlmn := append(authorizedNetworkSliceInfo.GetRejectedNssaiInPlmn(), param.SliceInfoRequestForPduSession.GetSNssai())
 			authorizedNetworkSliceInfo.SetRejectedNssaiInPlmn(rejectedNssaiInPlmn)
 
@@ -50,7 +50,7 @@ func nsselectionForPduSession(param NsselectionQueryParameter,
 
 	if param.Tai != nil {
 		// Check whether UE's current TA is supported when UE provides TAI
-		if !util.CheckSupportedTa(*param.Tai) {
+		if !util.CheckSupportedTa(param.config, *param.Tai) {
 			rejectedNssaiInTa := append(authorizedNetworkSliceInfo.GetRejectedNssaiInTa(), param.SliceInfoRequestForPduSession.GetSNssai())
 			authorizedNetworkSliceInfo.SetRejectedNssaiInTa(rejectedNssaiInTa)
 
@@ -60,7 +60,7 @@ func nsselectionForPduSession(param NsselectionQueryParameter,
 	}
 
 	if param.Tai != nil &&
-		!util.CheckSupportedSnssaiInPlmn(param.SliceInfoRequestForPduSession.GetSNssai(), param.Tai.GetPlmnId()) {
+		!util.CheckSupportedSnssaiInPlmn(param.config, param.SliceInfoRequestForPduSession.GetSNssai(), param.Tai.GetPlmnId()) {
 		// Return ProblemDetails indicating S-NSSAI is not supported
 		// TODO: Based on TS 23.501 V15.2.0, if the Requested NSSAI includes an S-NSSAI that is not valid in the
 		//       Serving PLMN, the NSSF may derive the Configured NSSAI for Serving PLMN
@@ -97,7 +97,7 @@ func nsselectionForPduSession(param NsselectionQueryParameter,
 		}
 	}
 
-	if param.Tai != nil && !util.CheckSupportedSnssaiInTa(param.SliceInfoRequestForPduSession.GetSNssai(), *param.Tai) {
+	if param.Tai != nil && !util.CheckSupportedSnssaiInTa(param.config, param.SliceInfoRequestForPduSession.GetSNssai(), *param.Tai, nil) {
 		// Requested S-NSSAI does not supported in UE's current TA
 		// Add it to Rejected NSSAI in TA
 		rejectedNssaiInTa := append(authorizedNetworkSliceInfo.GetRejectedNssaiInTa(), param.SliceInfoRequestForPduSession.GetSNssai())
@@ -106,7 +106,7 @@ func nsselectionForPduSession(param NsselectionQueryParameter,
 		return status
 	}
 
-	nsiInformationList := util.GetNsiInformationListFromConfig(param.SliceInfoRequestForPduSession.GetSNssai())
+	nsiInformationList := util.GetNsiInformationListFromConfig(param.config, param.SliceInfoRequestForPduSession.GetSNssai())
 
 	if nsiInformationList != nil {
 		nsiInformation := selectNsiInformation(nsiInformationList)
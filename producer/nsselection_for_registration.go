@@ -16,11 +16,20 @@ import (
 
 	"github.com/omec-project/nssf/factory"
 	"github.com/omec-project/nssf/logger"
+	stats "github.com/omec-project/nssf/metrics"
 	"github.com/omec-project/nssf/util"
 	"github.com/omec-project/openapi/v2/models"
 	"github.com/omec-project/openapi/v2/utils"
 )
 
+// addAllowedSnssai adds the Allowed S-NSSAI to the Allowed NSSAI of the Access Types in which it is allowed
+func addAllowedSnssai(param NsselectionQueryParameter, allowedSnssai models.AllowedSnssai,
+	authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo,
+) {
+	accessTypes := util.GetAccessTypesFromConfig(param.config, allowedSnssai.AllowedSnssai, param.Tai, param.currentAccessType())
+	util.AddAllowedSnssai(allowedSnssai, accessTypes, authorizedNetworkSliceInfo)
+}
+
 // Set Allowed NSSAI with Subscribed S-NSSAI(s) which are marked as default S-NSSAI(s)
 func useDefaultSubscribedSnssai(
 	param NsselectionQueryParameter, authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo,
@@ -28,7 +37,7 @@ func useDefaultSubscribedSnssai(
 	var mappingOfSnssai []models.MappingOfSnssai
 	if param.HomePlmnId != nil {
 		// Find mapping of Subscribed S-NSSAI of UE's HPLMN to S-NSSAI in Serving PLMN from NSSF configuration
-		mappingOfSnssai = util.GetMappingOfPlmnFromConfig(*param.HomePlmnId)
+		mappingOfSnssai = util.GetMappingOfPlmnFromConfig(param.config, *param.HomePlmnId)
 
 		if mappingOfSnssai == nil {
 			logger.Nsselection.Warnf("no S-NSSAI mapping of UE's HPLMN %+v in NSSF configuration", *param.HomePlmnId)
@@ -41,7 +50,6 @@ func useDefaultSubscribedSnssai(
 			// Subscribed S-NSSAI is marked as default S-NSSAI
 
 			var mappingOfSubscribedSnssai models.Snssai
-			// TODO: Compared with Restricted S-NSSAI list in configuration under roaming scenario
 			if param.HomePlmnId != nil && !util.CheckStandardSnssai(subscribedSnssai.GetSubscribedSnssai()) {
 				targetMapping, found := util.FindMappingWithHomeSnssai(subscribedSnssai.GetSubscribedSnssai(), mappingOfSnssai)
 
@@ -57,13 +65,20 @@ func useDefaultSubscribedSnssai(
 				mappingOfSubscribedSnssai = subscribedSnssai.GetSubscribedSnssai()
 			}
 
-			if param.Tai != nil && !util.CheckSupportedSnssaiInTa(mappingOfSubscribedSnssai, *param.Tai) {
+			if param.Tai != nil && !util.CheckSupportedSnssaiInTa(param.config, mappingOfSubscribedSnssai, *param.Tai, param.currentAccessType()) {
+				continue
+			}
+
+			if param.HomePlmnId != nil && param.Tai != nil &&
+				util.CheckRestrictedSnssaiInTa(param.config, mappingOfSubscribedSnssai, *param.Tai, param.currentAccessType(), *param.HomePlmnId) {
+				logger.Nsselection.Infof("default S-NSSAI %+v is restricted in TA %s for roaming UEs of HPLMN %s",
+					mappingOfSubscribedSnssai, factory.TaiString(*param.Tai), factory.PlmnIdString(*param.HomePlmnId))
 				continue
 			}
 
 			var allowedSnssaiElement models.AllowedSnssai
 			allowedSnssaiElement.SetAllowedSnssai(mappingOfSubscribedSnssai)
-			nsiInformationList := util.GetNsiInformationListFromConfig(mappingOfSubscribedSnssai)
+			nsiInformationList := util.GetNsiInformationListFromConfig(param.config, mappingOfSubscribedSnssai)
 			if nsiInformationList != nil {
 				// TODO: `NsiInformationList` should be slice in `AllowedSnssai` instead of pointer of slice
 				allowedSnssaiElement.NsiInformationList = append(allowedSnssaiElement.NsiInformationList,
@@ -73,15 +88,7 @@ func useDefaultSubscribedSnssai(
 				allowedSnssaiElement.SetMappedHomeSnssai(subscribedSnssai.GetSubscribedSnssai())
 			}
 
-			// Default Access Type is set to 3GPP Access if no TAI is provided
-			// TODO: Depend on operator implementation, it may also return S-NSSAIs in all valid Access Type if
-			//       UE's Access Type could not be identified
-			accessType := models.ACCESSTYPE__3_GPP_ACCESS
-			if param.Tai != nil {
-				accessType = util.GetAccessTypeFromConfig(*param.Tai)
-			}
-
-			util.AddAllowedSnssai(allowedSnssaiElement, accessType, authorizedNetworkSliceInfo)
+			addAllowedSnssai(param, allowedSnssaiElement, authorizedNetworkSliceInfo)
 		}
 	}
 }
@@ -118,7 +125,7 @@ func setConfiguredNssai(
 	var mappingOfSnssai []models.MappingOfSnssai
 	if param.HomePlmnId != nil {
 		// Find mapping of Subscribed S-NSSAI of UE's HPLMN to S-NSSAI in Serving PLMN from NSSF configuration
-		mappingOfSnssai = util.GetMappingOfPlmnFromConfig(*param.HomePlmnId)
+		mappingOfSnssai = util.GetMappingOfPlmnFromConfig(param.config, *param.HomePlmnId)
 
 		if mappingOfSnssai == nil {
 			logger.Nsselection.Warnf("no S-NSSAI mapping of UE's HPLMN %+v in NSSF configuration", *param.HomePlmnId)
@@ -143,7 +150,7 @@ func setConfiguredNssai(
 			mappingOfSubscribedSnssai = subscribedSnssai.GetSubscribedSnssai()
 		}
 
-		if util.CheckSupportedSnssaiInPlmn(mappingOfSubscribedSnssai, param.Tai.GetPlmnId()) {
+		if util.CheckSupportedSnssaiInPlmn(param.config, mappingOfSubscribedSnssai, param.Tai.GetPlmnId()) {
 			configuredSnssai := models.NewConfiguredSnssaiWithDefaults()
 			configuredSnssai.SetConfiguredSnssai(mappingOfSubscribedSnssai)
 			if param.HomePlmnId != nil && !util.CheckStandardSnssai(subscribedSnssai.GetSubscribedSnssai()) {
@@ -155,6 +162,47 @@ func setConfiguredNssai(
 	}
 }
 
+// Set NSAG information for the S-NSSAIs in the Allowed NSSAI and Configured NSSAI
+func setNsagInfos(param NsselectionQueryParameter, authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo) {
+	var nssai []models.Snssai
+	for _, allowedNssai := range authorizedNetworkSliceInfo.AllowedNssaiList {
+		for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
+			nssai = append(nssai, allowedSnssai.AllowedSnssai)
+		}
+	}
+	for _, configuredSnssai := range authorizedNetworkSliceInfo.ConfiguredNssai {
+		nssai = append(nssai, configuredSnssai.ConfiguredSnssai)
+	}
+	if len(nssai) == 0 {
+		return
+	}
+	if nsagInfos := util.GetNsagInfosFromConfig(param.config, param.Tai, nssai); len(nsagInfos) != 0 {
+		authorizedNetworkSliceInfo.SetNsagInfos(nsagInfos)
+	}
+}
+
+// Cross-check the mapping of S-NSSAIs provided by UE against the mapping in NSSF configuration
+// Returns true if any of the mappings provided by UE is stale
+func checkMappingOfNssaiProvidedByUe(param NsselectionQueryParameter, mappingOfSnssaiFromConfig []models.MappingOfSnssai) bool {
+	stale := false
+	for _, mappingFromUe := range param.SliceInfoRequestForRegistration.GetMappingOfNssai() {
+		targetMapping, found := util.FindMappingWithServingSnssai(mappingFromUe.GetServingSnssai(), mappingOfSnssaiFromConfig)
+		switch {
+		case !found:
+			logger.Nsselection.Warnf("UE provided mapping of S-NSSAI %+v to %+v of HPLMN %+v, which has no mapping in NSSF configuration",
+				mappingFromUe.GetServingSnssai(), mappingFromUe.GetHomeSnssai(), *param.HomePlmnId)
+		case factory.SnssaiToKey(targetMapping.GetHomeSnssai()) != factory.SnssaiToKey(mappingFromUe.GetHomeSnssai()):
+			logger.Nsselection.Warnf("UE provided mapping of S-NSSAI %+v to %+v of HPLMN %+v, but it is mapped to %+v in NSSF configuration",
+				mappingFromUe.GetServingSnssai(), mappingFromUe.GetHomeSnssai(), *param.HomePlmnId, targetMapping.GetHomeSnssai())
+		default:
+			continue
+		}
+		stats.IncrementNssfStaleSnssaiMappingsStats(factory.PlmnIdString(*param.HomePlmnId))
+		stale = true
+	}
+	return stale
+}
+
 // Network slice selection for registration
 // The function is executed when the IE, `slice-info-request-for-registration`, is provided in query parameters
 func nsselectionForRegistration(param NsselectionQueryParameter,
@@ -164,7 +212,7 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 	var status int
 	if param.HomePlmnId != nil {
 		// Check whether UE's Home PLMN is supported when UE is a roamer
-		if !util.CheckSupportedHplmn(*param.HomePlmnId) {
+		if !util.CheckSupportedHplmn(param.config, *param.HomePlmnId) {
 			rejectedNssaiInPlmn := append(authorizedNetworkSliceInfo.GetRejectedNssaiInPlmn(), param.SliceInfoRequestForRegistration.GetRequestedNssai()...)
 			authorizedNetworkSliceInfo.SetRejectedNssaiInPlmn(rejectedNssaiInPlmn)
 
@@ -175,7 +223,7 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 
 	if param.Tai != nil {
 		// Check whether UE's current TA is supported when UE provides TAI
-		if !util.CheckSupportedTa(*param.Tai) {
+		if !util.CheckSupportedTa(param.config, *param.Tai) {
 			rejectedNssaiInTa := append(authorizedNetworkSliceInfo.GetRejectedNssaiInTa(), param.SliceInfoRequestForRegistration.GetRequestedNssai()...)
 			authorizedNetworkSliceInfo.SetRejectedNssaiInTa(rejectedNssaiInTa)
 
@@ -200,7 +248,7 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 			return status
 		}
 
-		mappingOfSnssai := util.GetMappingOfPlmnFromConfig(*param.HomePlmnId)
+		mappingOfSnssai := util.GetMappingOfPlmnFromConfig(param.config, *param.HomePlmnId)
 
 		if mappingOfSnssai != nil {
 			// Find mappings for S-NSSAIs in `subscribedSnssai`
@@ -223,15 +271,7 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 					mappedHomeSnssai := subscribedSnssai.GetSubscribedSnssai()
 					allowedSnssaiElement.MappedHomeSnssai = &mappedHomeSnssai
 
-					// Default Access Type is set to 3GPP Access if no TAI is provided
-					// TODO: Depend on operator implementation, it may also return S-NSSAIs in all valid Access Type if
-					//       UE's Access Type could not be identified
-					accessType := models.ACCESSTYPE__3_GPP_ACCESS
-					if param.Tai != nil {
-						accessType = util.GetAccessTypeFromConfig(*param.Tai)
-					}
-
-					util.AddAllowedSnssai(allowedSnssaiElement, accessType, authorizedNetworkSliceInfo)
+					addAllowedSnssai(param, allowedSnssaiElement, authorizedNetworkSliceInfo)
 				}
 			}
 
@@ -255,15 +295,7 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 					snssaiCopy := snssai
 					allowedSnssaiElement.MappedHomeSnssai = &snssaiCopy
 
-					// Default Access Type is set to 3GPP Access if no TAI is provided
-					// TODO: Depend on operator implementation, it may also return S-NSSAIs in all valid Access Type if
-					//       UE's Access Type could not be identified
-					accessType := models.ACCESSTYPE__3_GPP_ACCESS
-					if param.Tai != nil {
-						accessType = util.GetAccessTypeFromConfig(*param.Tai)
-					}
-
-					util.AddAllowedSnssai(allowedSnssaiElement, accessType, authorizedNetworkSliceInfo)
+					addAllowedSnssai(param, allowedSnssaiElement, authorizedNetworkSliceInfo)
 				}
 			}
 
@@ -278,12 +310,14 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 	}
 
 	checkInvalidRequestedNssai := false
+	// Set when the UE's view of the Configured NSSAI is outdated and has to be updated
+	updateConfiguredNssai := false
 	if len(param.SliceInfoRequestForRegistration.GetRequestedNssai()) != 0 {
 		// Requested NSSAI is provided
 		// Verify which S-NSSAI(s) in the Requested NSSAI are permitted based on comparing the Subscribed S-NSSAI(s)
 
 		if param.Tai != nil &&
-			!util.CheckSupportedNssaiInPlmn(param.SliceInfoRequestForRegistration.GetRequestedNssai(), param.Tai.GetPlmnId()) {
+			!util.CheckSupportedNssaiInPlmn(param.config, param.SliceInfoRequestForRegistration.GetRequestedNssai(), param.Tai.GetPlmnId()) {
 			// Return ProblemDetails indicating S-NSSAI is not supported
 			// TODO: Based on TS 23.501 V15.2.0, if the Requested NSSAI includes an S-NSSAI that is not valid in the
 			//       Serving PLMN, the NSSF may derive the Configured NSSAI for Serving PLMN
@@ -297,11 +331,20 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 			return status
 		}
 
+		var mappingOfSnssaiFromConfig []models.MappingOfSnssai
+		if param.HomePlmnId != nil {
+			mappingOfSnssaiFromConfig = util.GetMappingOfPlmnFromConfig(param.config, *param.HomePlmnId)
+			if checkMappingOfNssaiProvidedByUe(param, mappingOfSnssaiFromConfig) {
+				// The UE's mapping is outdated, update UE's Configured NSSAI
+				updateConfiguredNssai = true
+			}
+		}
+
 		// Check if any Requested S-NSSAIs is present in Subscribed S-NSSAIs
 		checkIfRequestAllowed := false
 
 		for _, requestedSnssai := range param.SliceInfoRequestForRegistration.GetRequestedNssai() {
-			if param.Tai != nil && !util.CheckSupportedSnssaiInTa(requestedSnssai, *param.Tai) {
+			if param.Tai != nil && !util.CheckSupportedSnssaiInTa(param.config, requestedSnssai, *param.Tai, param.currentAccessType()) {
 				// Requested S-NSSAI does not supported in UE's current TA
 				// Add it to Rejected NSSAI in TA
 				rejectedNssaiInTa := append(authorizedNetworkSliceInfo.GetRejectedNssaiInTa(), requestedSnssai)
@@ -309,27 +352,38 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 				continue
 			}
 
+			if param.HomePlmnId != nil && param.Tai != nil &&
+				util.CheckRestrictedSnssaiInTa(param.config, requestedSnssai, *param.Tai, param.currentAccessType(), *param.HomePlmnId) {
+				// Requested S-NSSAI is restricted in UE's current TA by the roaming agreement with UE's HPLMN
+				// Add it to Rejected NSSAI in TA
+				logger.Nsselection.Infof("rejecting S-NSSAI %+v in TA %s: restricted for roaming UEs of HPLMN %s",
+					requestedSnssai, factory.TaiString(*param.Tai), factory.PlmnIdString(*param.HomePlmnId))
+				rejectedNssaiInTa := append(authorizedNetworkSliceInfo.GetRejectedNssaiInTa(), requestedSnssai)
+				authorizedNetworkSliceInfo.SetRejectedNssaiInTa(rejectedNssaiInTa)
+				continue
+			}
+
 			var mappingOfRequestedSnssai models.Snssai
-			// TODO: Compared with Restricted S-NSSAI list in configuration under roaming scenario
 			if param.HomePlmnId != nil && !util.CheckStandardSnssai(requestedSnssai) {
 				// Standard S-NSSAIs are supported to be commonly decided by all roaming partners
 				// Only non-standard S-NSSAIs are required to find mappings
-				targetMapping, found := util.FindMappingWithServingSnssai(requestedSnssai,
-					param.SliceInfoRequestForRegistration.GetMappingOfNssai())
-
+				// The mapping in NSSF configuration is authoritative, the one provided by UE is never trusted
+				targetMapping, found := util.FindMappingWithServingSnssai(requestedSnssai, mappingOfSnssaiFromConfig)
 				if !found {
-					// No mapping of Requested S-NSSAI to HPLMN S-NSSAI is provided by UE
-					// TODO: Search for local configuration if there is no provided mapping from UE, and update UE's
-					//       Configured NSSAI
 					checkInvalidRequestedNssai = true
 					rejectedNssaiInPlmn := append(authorizedNetworkSliceInfo.GetRejectedNssaiInPlmn(), requestedSnssai)
 					authorizedNetworkSliceInfo.SetRejectedNssaiInPlmn(rejectedNssaiInPlmn)
 					continue
-				} else {
-					// TODO: Check if mappings of S-NSSAIs are correct
-					//       If not, update UE's Configured NSSAI
-					mappingOfRequestedSnssai = targetMapping.GetHomeSnssai()
 				}
+				if _, found = util.FindMappingWithServingSnssai(requestedSnssai,
+					param.SliceInfoRequestForRegistration.GetMappingOfNssai()); !found {
+					// No mapping of Requested S-NSSAI to HPLMN S-NSSAI is provided by UE
+					// Use the one in local configuration, and update UE's Configured NSSAI
+					logger.Nsselection.Infof("no mapping of Requested S-NSSAI %+v provided by UE, using mapping to %+v in NSSF configuration",
+						requestedSnssai, targetMapping.GetHomeSnssai())
+					updateConfiguredNssai = true
+				}
+				mappingOfRequestedSnssai = targetMapping.GetHomeSnssai()
 			} else {
 				mappingOfRequestedSnssai = requestedSnssai
 			}
@@ -343,7 +397,7 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 
 					var allowedSnssaiElement models.AllowedSnssai
 					allowedSnssaiElement.AllowedSnssai = requestedSnssai
-					nsiInformationList := util.GetNsiInformationListFromConfig(requestedSnssai)
+					nsiInformationList := util.GetNsiInformationListFromConfig(param.config, requestedSnssai)
 					if nsiInformationList != nil {
 						// TODO: `NsiInformationList` should be slice in `AllowedSnssai` instead of pointer of slice
 						allowedSnssaiElement.NsiInformationList = append(allowedSnssaiElement.NsiInformationList,
@@ -354,15 +408,7 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 						allowedSnssaiElement.MappedHomeSnssai = &mappedHomeSnssai
 					}
 
-					// Default Access Type is set to 3GPP Access if no TAI is provided
-					// TODO: Depend on operator implementation, it may also return S-NSSAIs in all valid Access Type if
-					//       UE's Access Type could not be identified
-					accessType := models.ACCESSTYPE__3_GPP_ACCESS
-					if param.Tai != nil {
-						accessType = util.GetAccessTypeFromConfig(*param.Tai)
-					}
-
-					util.AddAllowedSnssai(allowedSnssaiElement, accessType, authorizedNetworkSliceInfo)
+					addAllowedSnssai(param, allowedSnssaiElement, authorizedNetworkSliceInfo)
 
 					checkIfRequestAllowed = true
 					break
@@ -390,17 +436,33 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 		useDefaultSubscribedSnssai(param, authorizedNetworkSliceInfo)
 	}
 
+	// S-NSSAIs in the Allowed NSSAI shall share a common NSSRG, the others are rejected in the TA
+	for _, snssai := range restrictAllowedNssaiToCommonNssrg(param, authorizedNetworkSliceInfo) {
+		logger.Nsselection.Infof("s-nssai %+v is rejected in TA, cause: %s", snssai, util.REJECTED_CAUSE_NSSRG)
+		stats.IncrementNssfRejectedSnssaisStats(util.REJECTED_CAUSE_NSSRG)
+		authorizedNetworkSliceInfo.RejectedNssaiInTa = append(authorizedNetworkSliceInfo.RejectedNssaiInTa, snssai)
+	}
+
+	// The Allowed NSSAI holds a limited number of S-NSSAIs, the ones of lower priority are rejected in the TA
+	// The Rejected NSSAI of the openapi models has no cause per S-NSSAI, so the cause is only logged and counted
+	for _, snssai := range util.TrimAllowedNssai(param.config, authorizedNetworkSliceInfo) {
+		logger.Nsselection.Warnf("s-nssai %+v is rejected in TA, cause: %s", snssai, util.REJECTED_CAUSE_ALLOWED_NSSAI_LIMIT)
+		stats.IncrementNssfRejectedSnssaisStats(util.REJECTED_CAUSE_ALLOWED_NSSAI_LIMIT)
+		authorizedNetworkSliceInfo.RejectedNssaiInTa = append(authorizedNetworkSliceInfo.RejectedNssaiInTa, snssai)
+	}
+
 	if param.Tai != nil &&
-		!util.CheckAllowedNssaiInAmfTa(authorizedNetworkSliceInfo.AllowedNssaiList, param.NfId, *param.Tai) {
-		util.AddAmfInformation(*param.Tai, authorizedNetworkSliceInfo)
+		!util.CheckAllowedNssaiInAmfTa(param.config, authorizedNetworkSliceInfo.AllowedNssaiList, param.NfId, *param.Tai) {
+		util.AddAmfInformation(param.config, *param.Tai, authorizedNetworkSliceInfo)
 	}
 
 	if param.SliceInfoRequestForRegistration.GetDefaultConfiguredSnssaiInd() {
 		// Default Configured NSSAI Indication is received from AMF
 		// Determine the Configured NSSAI based on the Default Configured NSSAI
 		useDefaultConfiguredNssai(param, authorizedNetworkSliceInfo)
-	} else if checkInvalidRequestedNssai {
-		// No Requested NSSAI is provided or the Requested NSSAI includes an S-NSSAI that is not valid
+	} else if checkInvalidRequestedNssai || updateConfiguredNssai {
+		// No Requested NSSAI is provided, the Requested NSSAI includes an S-NSSAI that is not valid or
+		// the UE does not have the mapping of a Requested S-NSSAI
 		// Determine the Configured NSSAI based on the subscription
 		// Configure available NSSAI for UE in its PLMN
 		// If TAI is not provided, then unable to check if S-NSSAIs is supported in the PLMN
@@ -408,6 +470,12 @@ func nsselectionForRegistration(param NsselectionQueryParameter,
 			setConfiguredNssai(param, authorizedNetworkSliceInfo)
 		}
 	}
+	restrictConfiguredNssaiToCommonNssrg(param, authorizedNetworkSliceInfo)
+
+	if param.SliceInfoRequestForRegistration.GetNsagSupported() {
+		// Provide the NSAGs of the S-NSSAIs the UE may use in the serving PLMN
+		setNsagInfos(param, authorizedNetworkSliceInfo)
+	}
 
 	status = http.StatusOK
 	return status
@@ -0,0 +1,233 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/openapi/v2"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+var (
+	testServingPlmnId = models.PlmnId{Mcc: "208", Mnc: "93"}
+	testHomePlmnId    = models.PlmnId{Mcc: "466", Mnc: "92"}
+	testServingTai    = models.Tai{PlmnId: testServingPlmnId, Tac: "000001"}
+	// Non-standard S-NSSAIs of the serving PLMN and the HPLMN they map to
+	testServingSnssai1 = models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
+	testServingSnssai2 = models.Snssai{Sst: 1, Sd: openapi.PtrString("000002")}
+	testHomeSnssai1    = models.Snssai{Sst: 1, Sd: openapi.PtrString("0000a1")}
+	testHomeSnssai2    = models.Snssai{Sst: 1, Sd: openapi.PtrString("0000a2")}
+)
+
+// setRoamingTestConfig configures a serving PLMN with one TA supporting both serving S-NSSAIs,
+// and the mapping of the HPLMN S-NSSAIs to them
+func setRoamingTestConfig(t *testing.T) *factory.Configuration {
+	t.Helper()
+	accessType := models.ACCESSTYPE__3_GPP_ACCESS
+	configuration := &factory.Configuration{
+		SupportedNssaiInPlmnList: factory.SupportedNssaiInPlmn{
+			testServingPlmnId: {
+				factory.SnssaiToKey(testServingSnssai1): {},
+				factory.SnssaiToKey(testServingSnssai2): {},
+			},
+		},
+		TaList: []factory.TaConfig{{
+			Tai:                 &testServingTai,
+			AccessType:          &accessType,
+			SupportedSnssaiList: []models.Snssai{testServingSnssai1, testServingSnssai2},
+		}},
+		MappingListFromPlmn: []factory.MappingFromPlmnConfig{{
+			HomePlmnId: &testHomePlmnId,
+			MappingOfSnssai: []models.MappingOfSnssai{
+				{ServingSnssai: testServingSnssai1, HomeSnssai: testHomeSnssai1},
+				{ServingSnssai: testServingSnssai2, HomeSnssai: testHomeSnssai2},
+			},
+		}},
+	}
+	setTestConfig(t, configuration)
+	return configuration
+}
+
+func newRoamingRegistrationParam(requestedNssai []models.Snssai, mappingOfNssai []models.MappingOfSnssai,
+	subscribedNssai ...models.Snssai,
+) NsselectionQueryParameter {
+	sliceInfo := models.NewSliceInfoForRegistration()
+	for _, snssai := range subscribedNssai {
+		sliceInfo.SubscribedNssai = append(sliceInfo.SubscribedNssai, *models.NewSubscribedSnssai(snssai))
+	}
+	sliceInfo.SetRequestedNssai(requestedNssai)
+	if len(mappingOfNssai) != 0 {
+		sliceInfo.SetMappingOfNssai(mappingOfNssai)
+	}
+	nfType := models.NFTYPE_AMF
+	homePlmnId := testHomePlmnId
+	tai := testServingTai
+	return NsselectionQueryParameter{
+		NfType:                          &nfType,
+		SliceInfoRequestForRegistration: sliceInfo,
+		HomePlmnId:                      &homePlmnId,
+		Tai:                             &tai,
+		config:                          factory.CurrentSnapshot(),
+	}
+}
+
+func allowedSnssaiKeys(authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo) []factory.SnssaiKey {
+	var keys []factory.SnssaiKey
+	for _, allowedNssai := range authorizedNetworkSliceInfo.AllowedNssaiList {
+		for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
+			keys = append(keys, factory.SnssaiToKey(allowedSnssai.AllowedSnssai))
+		}
+	}
+	return keys
+}
+
+func findConfiguredSnssai(authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo,
+	snssai models.Snssai,
+) (models.ConfiguredSnssai, bool) {
+	for _, configuredSnssai := range authorizedNetworkSliceInfo.ConfiguredNssai {
+		if factory.SnssaiToKey(configuredSnssai.ConfiguredSnssai) == factory.SnssaiToKey(snssai) {
+			return configuredSnssai, true
+		}
+	}
+	return models.ConfiguredSnssai{}, false
+}
+
+func TestRegistrationUsesConfiguredMappingWhenUeProvidesNone(t *testing.T) {
+	setRoamingTestConfig(t)
+	param := newRoamingRegistrationParam([]models.Snssai{testServingSnssai1}, nil, testHomeSnssai1)
+
+	response := models.NewAuthorizedNetworkSliceInfo()
+	if status := nsselectionForRegistration(param, response, models.NewProblemDetails()); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+
+	allowed := allowedSnssaiKeys(response)
+	if len(allowed) != 1 || allowed[0] != factory.SnssaiToKey(testServingSnssai1) {
+		t.Fatalf("expected requested S-NSSAI to be allowed, got %+v", allowed)
+	}
+	if len(response.RejectedNssaiInPlmn) != 0 {
+		t.Fatalf("expected nothing to be rejected, got %+v", response.RejectedNssaiInPlmn)
+	}
+	configuredSnssai, found := findConfiguredSnssai(response, testServingSnssai1)
+	if !found || configuredSnssai.MappedHomeSnssai == nil ||
+		factory.SnssaiToKey(*configuredSnssai.MappedHomeSnssai) != factory.SnssaiToKey(testHomeSnssai1) {
+		t.Fatalf("expected updated Configured NSSAI with mapped home S-NSSAI, got %+v", response.ConfiguredNssai)
+	}
+}
+
+func TestRegistrationRejectsUnmappedRequestedSnssai(t *testing.T) {
+	configuration := setRoamingTestConfig(t)
+	configuration.MappingListFromPlmn[0].MappingOfSnssai = configuration.MappingListFromPlmn[0].MappingOfSnssai[1:]
+	factory.PublishSnapshotLocked()
+	param := newRoamingRegistrationParam([]models.Snssai{testServingSnssai1}, nil, testHomeSnssai1)
+
+	response := models.NewAuthorizedNetworkSliceInfo()
+	nsselectionForRegistration(param, response, models.NewProblemDetails())
+
+	if len(response.RejectedNssaiInPlmn) != 1 {
+		t.Fatalf("expected requested S-NSSAI to be rejected, got %+v", response)
+	}
+}
+
+func TestRegistrationAuthorizesOnConfiguredMappingOnly(t *testing.T) {
+	setRoamingTestConfig(t)
+	staleMapping := []models.MappingOfSnssai{{ServingSnssai: testServingSnssai1, HomeSnssai: testHomeSnssai2}}
+
+	testCases := []struct {
+		name            string
+		subscribedNssai models.Snssai
+		expectAllowed   bool
+	}{
+		{
+			name:            "stale mapping of a subscribed S-NSSAI is corrected",
+			subscribedNssai: testHomeSnssai1,
+			expectAllowed:   true,
+		},
+		{
+			name:            "mapping claimed by UE does not authorize an S-NSSAI",
+			subscribedNssai: testHomeSnssai2,
+			expectAllowed:   false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			param := newRoamingRegistrationParam([]models.Snssai{testServingSnssai1}, staleMapping, tc.subscribedNssai)
+
+			response := models.NewAuthorizedNetworkSliceInfo()
+			nsselectionForRegistration(param, response, models.NewProblemDetails())
+
+			allowed := allowedSnssaiKeys(response)
+			isAllowed := len(allowed) == 1 && allowed[0] == factory.SnssaiToKey(testServingSnssai1)
+			if isAllowed != tc.expectAllowed {
+				t.Fatalf("expected allowed %v, got %+v", tc.expectAllowed, response)
+			}
+			if !tc.expectAllowed {
+				return
+			}
+			configuredSnssai, found := findConfiguredSnssai(response, testServingSnssai1)
+			if !found || factory.SnssaiToKey(*configuredSnssai.MappedHomeSnssai) != factory.SnssaiToKey(testHomeSnssai1) {
+				t.Fatalf("expected corrected Configured NSSAI, got %+v", response.ConfiguredNssai)
+			}
+		})
+	}
+}
+
+func TestRegistrationRejectsRestrictedSnssaiInTa(t *testing.T) {
+	configuration := setRoamingTestConfig(t)
+	configuration.TaList[0].RestrictedSnssaiList = []models.RestrictedSnssai{{
+		HomePlmnId: testHomePlmnId,
+		SNssaiList: []models.Snssai{testServingSnssai2},
+	}}
+	factory.PublishSnapshotLocked()
+	param := newRoamingRegistrationParam([]models.Snssai{testServingSnssai1, testServingSnssai2}, nil,
+		testHomeSnssai1, testHomeSnssai2)
+
+	response := models.NewAuthorizedNetworkSliceInfo()
+	nsselectionForRegistration(param, response, models.NewProblemDetails())
+
+	allowed := allowedSnssaiKeys(response)
+	if len(allowed) != 1 || allowed[0] != factory.SnssaiToKey(testServingSnssai1) {
+		t.Fatalf("expected only the unrestricted S-NSSAI to be allowed, got %+v", allowed)
+	}
+	if len(response.RejectedNssaiInTa) != 1 ||
+		factory.SnssaiToKey(response.RejectedNssaiInTa[0]) != factory.SnssaiToKey(testServingSnssai2) {
+		t.Fatalf("expected restricted S-NSSAI in Rejected NSSAI in TA, got %+v", response.RejectedNssaiInTa)
+	}
+}
+
+func TestRegistrationSelectsSnssaisOfCurrentAccessType(t *testing.T) {
+	configuration := setRoamingTestConfig(t)
+	configuration.TaList[0] = factory.TaConfig{
+		Tai: &testServingTai,
+		AccessTypeList: []factory.TaAccessTypeConfig{
+			{AccessType: models.ACCESSTYPE__3_GPP_ACCESS, SupportedSnssaiList: []models.Snssai{testServingSnssai1}},
+			{AccessType: models.ACCESSTYPE_NON_3_GPP_ACCESS, SupportedSnssaiList: []models.Snssai{testServingSnssai2}},
+		},
+	}
+	factory.PublishSnapshotLocked()
+	param := newRoamingRegistrationParam([]models.Snssai{testServingSnssai1, testServingSnssai2}, nil,
+		testHomeSnssai1, testHomeSnssai2)
+	param.SliceInfoRequestForRegistration.SetAllowedNssaiCurrentAccess(
+		*models.NewAllowedNssai(nil, models.ACCESSTYPE_NON_3_GPP_ACCESS))
+
+	response := models.NewAuthorizedNetworkSliceInfo()
+	if status := nsselectionForRegistration(param, response, models.NewProblemDetails()); status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+
+	if len(response.AllowedNssaiList) != 1 || response.AllowedNssaiList[0].AccessType != models.ACCESSTYPE_NON_3_GPP_ACCESS {
+		t.Fatalf("expected Allowed NSSAI of non-3GPP access only, got %+v", response.AllowedNssaiList)
+	}
+	allowed := allowedSnssaiKeys(response)
+	if len(allowed) != 1 || allowed[0] != factory.SnssaiToKey(testServingSnssai2) {
+		t.Fatalf("expected the S-NSSAI of non-3GPP access to be allowed, got %+v", allowed)
+	}
+	if len(response.RejectedNssaiInTa) != 1 ||
+		factory.SnssaiToKey(response.RejectedNssaiInTa[0]) != factory.SnssaiToKey(testServingSnssai1) {
+		t.Fatalf("expected the S-NSSAI of 3GPP access to be rejected in TA, got %+v", response.RejectedNssaiInTa)
+	}
+}
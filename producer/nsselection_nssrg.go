@@ -0,0 +1,214 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF NS Selection
+ *
+ * Network Slice Simultaneous Registration Group (NSSRG) handling, TS 23.501 clause 5.15.12
+ */
+
+package producer
+
+import (
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/features"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// nssrgInformation maps each Subscribed S-NSSAI to the NSSRGs it belongs to
+// Subscribed S-NSSAIs without NSSRG can be combined with any other S-NSSAI
+type nssrgInformation map[factory.SnssaiKey][]string
+
+func getNssrgInformation(param NsselectionQueryParameter) nssrgInformation {
+	nssrgInfo := make(nssrgInformation)
+	for _, subscribedSnssai := range param.SliceInfoRequestForRegistration.GetSubscribedNssai() {
+		if len(subscribedSnssai.SubscribedNsSrgList) != 0 {
+			nssrgInfo[factory.SnssaiToKey(subscribedSnssai.GetSubscribedSnssai())] = subscribedSnssai.SubscribedNsSrgList
+		}
+	}
+	return nssrgInfo
+}
+
+// inNssrg reports whether the S-NSSAI, identified by its HPLMN value, may be part of the NSSRG
+func (n nssrgInformation) inNssrg(homeSnssai factory.SnssaiKey, nssrg string) bool {
+	nssrgList, found := n[homeSnssai]
+	if !found {
+		return true
+	}
+	for _, item := range nssrgList {
+		if item == nssrg {
+			return true
+		}
+	}
+	return false
+}
+
+// selectCommonNssrg chooses the NSSRG to which the S-NSSAIs are restricted. An NSSRG is preferred if it
+// keeps more of the S-NSSAIs in the first priority list, then in the next one, and finally more S-NSSAIs
+// overall. Ties are broken by the order in which the NSSRGs appear in the subscription.
+func (n nssrgInformation) selectCommonNssrg(homeSnssais []factory.SnssaiKey, priorities ...[]factory.SnssaiKey) string {
+	var candidates []string
+	seen := make(map[string]struct{})
+	for _, homeSnssai := range homeSnssais {
+		for _, nssrg := range n[homeSnssai] {
+			if _, found := seen[nssrg]; !found {
+				seen[nssrg] = struct{}{}
+				candidates = append(candidates, nssrg)
+			}
+		}
+	}
+
+	countIn := func(nssrg string, snssais []factory.SnssaiKey) int {
+		count := 0
+		for _, snssai := range snssais {
+			if n.inNssrg(snssai, nssrg) {
+				count++
+			}
+		}
+		return count
+	}
+	score := func(nssrg string) []int {
+		result := make([]int, 0, len(priorities)+1)
+		for _, priority := range priorities {
+			result = append(result, countIn(nssrg, priority))
+		}
+		return append(result, countIn(nssrg, homeSnssais))
+	}
+
+	var selected string
+	var selectedScore []int
+	for _, candidate := range candidates {
+		candidateScore := score(candidate)
+		better := selectedScore == nil
+		for i := 0; !better && i < len(candidateScore); i++ {
+			if candidateScore[i] != selectedScore[i] {
+				better = candidateScore[i] > selectedScore[i]
+				break
+			}
+		}
+		if better {
+			selected, selectedScore = candidate, candidateScore
+		}
+	}
+	return selected
+}
+
+func homeSnssaiKey(snssai models.Snssai, mappedHomeSnssai *models.Snssai) factory.SnssaiKey {
+	if mappedHomeSnssai != nil {
+		return factory.SnssaiToKey(*mappedHomeSnssai)
+	}
+	return factory.SnssaiToKey(snssai)
+}
+
+func defaultSubscribedSnssais(param NsselectionQueryParameter) []factory.SnssaiKey {
+	var defaults []factory.SnssaiKey
+	for _, subscribedSnssai := range param.SliceInfoRequestForRegistration.GetSubscribedNssai() {
+		if subscribedSnssai.GetDefaultIndication() {
+			defaults = append(defaults, factory.SnssaiToKey(subscribedSnssai.GetSubscribedSnssai()))
+		}
+	}
+	return defaults
+}
+
+// restrictAllowedNssaiToCommonNssrg keeps, per Access Type, only the Allowed S-NSSAIs sharing a common NSSRG
+// The Allowed NSSAI is always restricted, whether or not the UE supports NSSRG. It returns the removed
+// S-NSSAIs which are not allowed in any other Access Type either, to be rejected.
+func restrictAllowedNssaiToCommonNssrg(param NsselectionQueryParameter,
+	authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo,
+) []models.Snssai {
+	nssrgInfo := getNssrgInformation(param)
+	if len(nssrgInfo) == 0 {
+		return nil
+	}
+	defaults := defaultSubscribedSnssais(param)
+
+	var removed []models.Snssai
+	for i := range authorizedNetworkSliceInfo.AllowedNssaiList {
+		allowedNssai := &authorizedNetworkSliceInfo.AllowedNssaiList[i]
+		homeSnssais := make([]factory.SnssaiKey, 0, len(allowedNssai.AllowedSnssaiList))
+		for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
+			homeSnssais = append(homeSnssais, homeSnssaiKey(allowedSnssai.AllowedSnssai, allowedSnssai.MappedHomeSnssai))
+		}
+		nssrg := nssrgInfo.selectCommonNssrg(homeSnssais, defaults)
+		if nssrg == "" {
+			continue
+		}
+
+		allowedSnssaiList := make([]models.AllowedSnssai, 0, len(allowedNssai.AllowedSnssaiList))
+		for j, allowedSnssai := range allowedNssai.AllowedSnssaiList {
+			if nssrgInfo.inNssrg(homeSnssais[j], nssrg) {
+				allowedSnssaiList = append(allowedSnssaiList, allowedSnssai)
+			} else {
+				logger.Nsselection.Infof("s-nssai %+v is not allowed together with NSSRG %s in %s",
+					allowedSnssai.AllowedSnssai, nssrg, allowedNssai.AccessType)
+				removed = append(removed, allowedSnssai.AllowedSnssai)
+			}
+		}
+		allowedNssai.AllowedSnssaiList = allowedSnssaiList
+	}
+
+	var rejected []models.Snssai
+	for _, snssai := range removed {
+		if !util.CheckSnssaiInNssai(snssai, rejected) && !allowedInAnyAccessType(snssai, authorizedNetworkSliceInfo) {
+			rejected = append(rejected, snssai)
+		}
+	}
+	return rejected
+}
+
+func allowedInAnyAccessType(snssai models.Snssai, authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo) bool {
+	for _, allowedNssai := range authorizedNetworkSliceInfo.AllowedNssaiList {
+		for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
+			if factory.SnssaiToKey(allowedSnssai.AllowedSnssai) == factory.SnssaiToKey(snssai) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// restrictConfiguredNssaiToCommonNssrg keeps only the Configured S-NSSAIs sharing a common NSSRG, unless
+// the UE supports NSSRG and the NSSRG information is not suppressed. The NSSRG is chosen so that the
+// Allowed NSSAI remains part of the Configured NSSAI.
+//
+// A UE supporting NSSRG receives its full Configured NSSAI. AuthorizedNetworkSliceInfo of the
+// openapi models in use has no `nssrgInfo` attribute, so the AMF provides the NSSRG information
+// to the UE from the `subscribedNsSrgList` of the subscription.
+func restrictConfiguredNssaiToCommonNssrg(param NsselectionQueryParameter,
+	authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo,
+) {
+	sliceInfo := param.SliceInfoRequestForRegistration
+	if param.featureNegotiated(features.NSSelectionNSSRG) &&
+		sliceInfo.GetUeSupNssrgInd() && !sliceInfo.GetSuppressNssrgInd() {
+		return
+	}
+	nssrgInfo := getNssrgInformation(param)
+	if len(nssrgInfo) == 0 || len(authorizedNetworkSliceInfo.ConfiguredNssai) == 0 {
+		return
+	}
+
+	var allowed []factory.SnssaiKey
+	for _, allowedNssai := range authorizedNetworkSliceInfo.AllowedNssaiList {
+		for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
+			allowed = append(allowed, homeSnssaiKey(allowedSnssai.AllowedSnssai, allowedSnssai.MappedHomeSnssai))
+		}
+	}
+	homeSnssais := make([]factory.SnssaiKey, 0, len(authorizedNetworkSliceInfo.ConfiguredNssai))
+	for _, configuredSnssai := range authorizedNetworkSliceInfo.ConfiguredNssai {
+		homeSnssais = append(homeSnssais, homeSnssaiKey(configuredSnssai.ConfiguredSnssai, configuredSnssai.MappedHomeSnssai))
+	}
+	nssrg := nssrgInfo.selectCommonNssrg(homeSnssais, allowed, defaultSubscribedSnssais(param))
+	if nssrg == "" {
+		return
+	}
+
+	configuredNssai := make([]models.ConfiguredSnssai, 0, len(authorizedNetworkSliceInfo.ConfiguredNssai))
+	for i, configuredSnssai := range authorizedNetworkSliceInfo.ConfiguredNssai {
+		if nssrgInfo.inNssrg(homeSnssais[i], nssrg) {
+			configuredNssai = append(configuredNssai, configuredSnssai)
+		}
+	}
+	authorizedNetworkSliceInfo.ConfiguredNssai = configuredNssai
+}
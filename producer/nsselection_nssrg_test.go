@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"testing"
+
+	"github.com/omec-project/nssf/features"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+func nssrgTestParam(ueSupNssrg, suppressNssrg bool, negotiatedFeatures string) NsselectionQueryParameter {
+	subscribed := func(sst int32, isDefault bool, nssrgs ...string) models.SubscribedSnssai {
+		subscribedSnssai := models.NewSubscribedSnssai(models.Snssai{Sst: sst})
+		subscribedSnssai.SetDefaultIndication(isDefault)
+		subscribedSnssai.SubscribedNsSrgList = nssrgs
+		return *subscribedSnssai
+	}
+	sliceInfo := models.NewSliceInfoForRegistration()
+	sliceInfo.SetSubscribedNssai([]models.SubscribedSnssai{
+		subscribed(1, true, "A"),
+		subscribed(2, false, "B"),
+		subscribed(3, false, "A", "B"),
+		subscribed(4, false),
+	})
+	sliceInfo.SetUeSupNssrgInd(ueSupNssrg)
+	sliceInfo.SetSuppressNssrgInd(suppressNssrg)
+	param := NsselectionQueryParameter{SliceInfoRequestForRegistration: sliceInfo}
+	param.negotiatedFeatures, _ = features.NSSelection.Negotiate(negotiatedFeatures)
+	return param
+}
+
+func sstList(allowedSnssaiList []models.AllowedSnssai) []int32 {
+	result := make([]int32, 0, len(allowedSnssaiList))
+	for _, allowedSnssai := range allowedSnssaiList {
+		result = append(result, allowedSnssai.AllowedSnssai.Sst)
+	}
+	return result
+}
+
+func TestRestrictAllowedNssaiToCommonNssrg(t *testing.T) {
+	param := nssrgTestParam(true, false, "2")
+	response := models.NewAuthorizedNetworkSliceInfo()
+	response.AllowedNssaiList = []models.AllowedNssai{{
+		AccessType: models.ACCESSTYPE__3_GPP_ACCESS,
+		AllowedSnssaiList: []models.AllowedSnssai{
+			{AllowedSnssai: models.Snssai{Sst: 2}},
+			{AllowedSnssai: models.Snssai{Sst: 3}},
+			{AllowedSnssai: models.Snssai{Sst: 1}},
+			{AllowedSnssai: models.Snssai{Sst: 4}},
+		},
+	}}
+
+	rejected := restrictAllowedNssaiToCommonNssrg(param, response)
+
+	// NSSRG A is selected because it includes the default S-NSSAI
+	got := sstList(response.AllowedNssaiList[0].AllowedSnssaiList)
+	if len(got) != 3 || got[0] != 3 || got[1] != 1 || got[2] != 4 {
+		t.Fatalf("unexpected Allowed NSSAI %v", got)
+	}
+	if len(rejected) != 1 || rejected[0].Sst != 2 {
+		t.Fatalf("expected S-NSSAI 2 to be rejected, got %+v", rejected)
+	}
+}
+
+func TestRestrictConfiguredNssaiToCommonNssrg(t *testing.T) {
+	configuredNssai := func() []models.ConfiguredSnssai {
+		return []models.ConfiguredSnssai{
+			{ConfiguredSnssai: models.Snssai{Sst: 1}},
+			{ConfiguredSnssai: models.Snssai{Sst: 2}},
+			{ConfiguredSnssai: models.Snssai{Sst: 3}},
+			{ConfiguredSnssai: models.Snssai{Sst: 4}},
+		}
+	}
+	testCases := []struct {
+		name          string
+		param         NsselectionQueryParameter
+		expectedCount int
+	}{
+		{name: "UE supports NSSRG", param: nssrgTestParam(true, false, "2"), expectedCount: 4},
+		{name: "UE does not support NSSRG", param: nssrgTestParam(false, false, "2"), expectedCount: 3},
+		{name: "NSSRG information suppressed", param: nssrgTestParam(true, true, "2"), expectedCount: 3},
+		{name: "NSSRG not negotiated with the AMF", param: nssrgTestParam(true, false, "0"), expectedCount: 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			response := models.NewAuthorizedNetworkSliceInfo()
+			response.ConfiguredNssai = configuredNssai()
+			restrictConfiguredNssaiToCommonNssrg(tc.param, response)
+			if len(response.ConfiguredNssai) != tc.expectedCount {
+				t.Fatalf("expected %d Configured S-NSSAIs, got %+v", tc.expectedCount, response.ConfiguredNssai)
+			}
+		})
+	}
+}
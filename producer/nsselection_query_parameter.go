@@ -5,6 +5,8 @@
 package producer
 
 import (
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/features"
 	"github.com/omec-project/openapi/v2/models"
 )
 
@@ -16,4 +18,23 @@ type NsselectionQueryParameter struct {
 	HomePlmnId                      *models.PlmnId                   `json:"home-plmn-id,omitempty"`
 	Tai                             *models.Tai                      `json:"tai,omitempty"`
 	SupportedFeatures               string                           `json:"supported-features,omitempty"`
+	// Features supported by both the consumer and the NSSF
+	negotiatedFeatures features.Set
+	// Configuration used to serve the request, so that it is consistent throughout the request
+	config *factory.Snapshot
+}
+
+// featureNegotiated reports whether the named Nnssf_NSSelection feature is supported by both sides
+func (p NsselectionQueryParameter) featureNegotiated(name string) bool {
+	return features.NSSelection.Includes(p.negotiatedFeatures, name)
+}
+
+// currentAccessType returns the Access Type of the UE, if the consumer indicates it with the Allowed NSSAI
+// of the current Access Type
+func (p NsselectionQueryParameter) currentAccessType() *models.AccessType {
+	if p.SliceInfoRequestForRegistration == nil || p.SliceInfoRequestForRegistration.AllowedNssaiCurrentAccess == nil {
+		return nil
+	}
+	accessType := p.SliceInfoRequestForRegistration.AllowedNssaiCurrentAccess.AccessType
+	return &accessType
 }
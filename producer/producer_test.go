@@ -0,0 +1,22 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"testing"
+
+	"github.com/omec-project/nssf/factory"
+)
+
+// setTestConfig replaces the NSSF configuration, and its snapshot, for the duration of the test
+func setTestConfig(t *testing.T, configuration *factory.Configuration) {
+	t.Helper()
+	originalFactoryConfig := factory.NssfConfig
+	t.Cleanup(func() {
+		factory.NssfConfig = originalFactoryConfig
+		factory.PublishSnapshotLocked()
+	})
+	factory.NssfConfig = factory.Config{Configuration: configuration}
+	factory.PublishSnapshotLocked()
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Producer
+ *
+ * Rejection of the requests of NF service consumers which exceeded their rate limit
+ */
+
+package producer
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+// throttle returns the 429 Too Many Requests response to the NF service consumer if it exceeded its rate limit
+// for the service
+func throttle(serviceName models.ServiceName, nfType, nfId string) *httpwrapper.Response {
+	problemDetails, retryAfter := sbi.ThrottleRequest(serviceName, nfType, nfId)
+	if problemDetails == nil {
+		return nil
+	}
+	header := http.Header{"Retry-After": []string{strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds()))))}}
+	return httpwrapper.NewResponse(http.StatusTooManyRequests, header, problemDetails)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Producer
+ *
+ * Limits of the size of the lists in the requests
+ */
+
+package producer
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/openapi/v2/utils"
+)
+
+// listLimit is a list of a request and the maximum number of its elements
+type listLimit struct {
+	// JSON pointer of the list in the request body, or name of the query parameter which contains it
+	param    string
+	size     int
+	limit    int
+	elements string
+}
+
+// oversizedLists returns the InvalidParams of the lists with more elements than their limit
+func oversizedLists(lists ...listLimit) []models.InvalidParam {
+	var invalidParams []models.InvalidParam
+	for _, list := range lists {
+		if list.size > list.limit {
+			reason := fmt.Sprintf("%d %s exceed the limit of %d", list.size, list.elements, list.limit)
+			invalidParams = append(invalidParams, models.InvalidParam{Param: list.param, Reason: &reason})
+		}
+	}
+	return invalidParams
+}
+
+// problemDetailsOversizedBody returns the ProblemDetails of a request body with lists exceeding their limit
+func problemDetailsOversizedBody(invalidParams []models.InvalidParam) *models.ProblemDetails {
+	problemDetails := utils.ProblemDetailsWithInvalidParams(util.INVALID_REQUEST, http.StatusBadRequest,
+		"[Request Body] lists exceed the limits of the NSSF", invalidParams)
+	problemDetails.SetCause(utils.CauseInvalidMsgFormat)
+	return problemDetails
+}
+
+// checkNssaiAvailabilityInfoLimits checks the sizes of the TA and S-NSSAI lists of the NSSAI availability of an AMF
+func checkNssaiAvailabilityInfoLimits(nssaiAvailabilityInfo models.NssaiAvailabilityInfo) *models.ProblemDetails {
+	limits := sbi.CurrentRequestLimits()
+	lists := []listLimit{{
+		param: "/supportedNssaiAvailabilityData", size: len(nssaiAvailabilityInfo.SupportedNssaiAvailabilityData),
+		limit: limits.MaxTaiListSize, elements: "TAs",
+	}}
+	for i, data := range nssaiAvailabilityInfo.SupportedNssaiAvailabilityData {
+		lists = append(lists, listLimit{
+			param: fmt.Sprintf("/supportedNssaiAvailabilityData/%d/supportedSnssaiList", i), size: len(data.SupportedSnssaiList),
+			limit: limits.MaxSnssaiListSize, elements: "S-NSSAIs",
+		})
+	}
+	if invalidParams := oversizedLists(lists...); invalidParams != nil {
+		return problemDetailsOversizedBody(invalidParams)
+	}
+	return nil
+}
+
+// checkPatchItemsLimit checks the number of operations of a JSON patch of the NSSAI availability of an AMF.
+// The operation beyond the limit is the invalid parameter.
+func checkPatchItemsLimit(patchItems []models.PatchItem) *models.ProblemDetails {
+	limit := sbi.CurrentRequestLimits().MaxPatchItems
+	if invalidParams := oversizedLists(listLimit{
+		param: fmt.Sprintf("/%d", limit), size: len(patchItems), limit: limit, elements: "patch items",
+	}); invalidParams != nil {
+		return problemDetailsOversizedBody(invalidParams)
+	}
+	return nil
+}
+
+// checkSubscriptionLimits checks the sizes of the TA lists of a subscription to NSSAI availability notifications
+func checkSubscriptionLimits(createData models.NssfEventSubscriptionCreateData) *models.ProblemDetails {
+	limit := sbi.CurrentRequestLimits().MaxTaiListSize
+	if invalidParams := oversizedLists(
+		listLimit{param: "/taiList", size: len(createData.TaiList), limit: limit, elements: "TAs"},
+		listLimit{param: "/taiRangeList", size: len(createData.TaiRangeList), limit: limit, elements: "TA ranges"},
+	); invalidParams != nil {
+		return problemDetailsOversizedBody(invalidParams)
+	}
+	return nil
+}
+
+// checkNsselectionQueryLimits checks the sizes of the S-NSSAI lists of the slice selection query parameters
+func checkNsselectionQueryLimits(param NsselectionQueryParameter) *models.ProblemDetails {
+	registration := param.SliceInfoRequestForRegistration
+	if registration == nil {
+		return nil
+	}
+	const queryParam = "slice-info-request-for-registration"
+	limit := sbi.CurrentRequestLimits().MaxSnssaiListSize
+	lists := []listLimit{
+		{param: queryParam, size: len(registration.RequestedNssai), limit: limit, elements: "S-NSSAIs of requestedNssai"},
+		{param: queryParam, size: len(registration.SubscribedNssai), limit: limit, elements: "S-NSSAIs of subscribedNssai"},
+		{param: queryParam, size: len(registration.SNssaiForMapping), limit: limit, elements: "S-NSSAIs of sNssaiForMapping"},
+		{param: queryParam, size: len(registration.MappingOfNssai), limit: limit, elements: "mappings of mappingOfNssai"},
+	}
+	if allowedNssai := registration.AllowedNssaiCurrentAccess; allowedNssai != nil {
+		lists = append(lists, listLimit{
+			param: queryParam, size: len(allowedNssai.AllowedSnssaiList), limit: limit,
+			elements: "S-NSSAIs of allowedNssaiCurrentAccess",
+		})
+	}
+	if invalidParams := oversizedLists(lists...); invalidParams != nil {
+		problemDetails := utils.ProblemDetailsWithInvalidParams(util.INVALID_REQUEST, http.StatusBadRequest,
+			"[Query Parameter] lists exceed the limits of the NSSF", invalidParams)
+		problemDetails.SetCause(sbi.CauseInvalidQueryParam)
+		return problemDetails
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package producer
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/openapi/v2/utils"
+	"github.com/omec-project/util/httpwrapper"
+)
+
+func TestNSSAIAvailabilityPutRejectsOversizedLists(t *testing.T) {
+	sbi.RequestLimits(&factory.RequestLimitsConfig{MaxSnssaiListSize: 2, MaxTaiListSize: 2})
+	t.Cleanup(func() { sbi.RequestLimits(nil) })
+
+	tai := models.Tai{PlmnId: models.PlmnId{Mcc: "208", Mnc: "93"}, Tac: "000001"}
+	snssais := []models.Snssai{{Sst: 1}, {Sst: 2}, {Sst: 3}}
+	nssaiAvailabilityInfo := models.NssaiAvailabilityInfo{SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+		{Tai: tai, SupportedSnssaiList: snssais[:1]},
+		{Tai: tai, SupportedSnssaiList: snssais},
+		{Tai: tai, SupportedSnssaiList: snssais[:2]},
+	}}
+	request := httpwrapper.NewRequest(&http.Request{Header: http.Header{}, URL: &url.URL{}}, nssaiAvailabilityInfo)
+	request.Params["nfId"] = "amf-1"
+
+	rsp := HandleNSSAIAvailabilityPut(request)
+	problemDetails, ok := rsp.Body.(*models.ProblemDetails)
+	if rsp.Status != http.StatusBadRequest || !ok || problemDetails.GetCause() != utils.CauseInvalidMsgFormat {
+		t.Fatalf("expected the oversized lists to be rejected, got %d %+v", rsp.Status, rsp.Body)
+	}
+	var params []string
+	for _, invalidParam := range problemDetails.InvalidParams {
+		params = append(params, invalidParam.Param)
+	}
+	if len(params) != 2 || params[0] != "/supportedNssaiAvailabilityData" ||
+		params[1] != "/supportedNssaiAvailabilityData/1/supportedSnssaiList" {
+		t.Errorf("expected every oversized list in the invalid params, got %v", params)
+	}
+}
+
+func TestNSSelectionGetRejectsOversizedRequestedNssai(t *testing.T) {
+	sbi.RequestLimits(&factory.RequestLimitsConfig{MaxSnssaiListSize: 1})
+	t.Cleanup(func() { sbi.RequestLimits(nil) })
+
+	query := url.Values{
+		"nf-type":                             {"AMF"},
+		"nf-id":                               {"9d2e5f4a-1b3c-4d5e-8f60-718293a4b5c6"},
+		"slice-info-request-for-registration": {`{"requestedNssai":[{"sst":1},{"sst":2}]}`},
+	}
+	_, problemDetails := NSSelectionGetProcedure(query)
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusBadRequest ||
+		problemDetails.GetCause() != sbi.CauseInvalidQueryParam || len(problemDetails.InvalidParams) != 1 ||
+		problemDetails.InvalidParams[0].Param != "slice-info-request-for-registration" {
+		t.Fatalf("expected the oversized requested NSSAI to be rejected, got %+v", problemDetails)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"net/http"
 
 	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/sbi"
 	"github.com/omec-project/openapi/v2/models"
 	"github.com/omec-project/openapi/v2/utils"
 	"github.com/omec-project/util/httpwrapper"
@@ -31,7 +32,12 @@ func HandleNSSAIAvailabilityPost(request *httpwrapper.Request) *httpwrapper.Resp
 
 	// TODO: If NF consumer is not authorized to update NSSAI availability, return ProblemDetails with code 403 Forbidden
 
-	response, problemDetails := NSSAIAvailabilityPostProcedure(createData)
+	if problemDetails := checkSubscriptionLimits(createData); problemDetails != nil {
+		return httpwrapper.NewResponse(http.StatusBadRequest, nil, problemDetails)
+	}
+
+	routingBinding := sbi.CallbackRoutingBinding(request.Header.Get(sbi.HeaderBinding))
+	response, problemDetails := NSSAIAvailabilityPostProcedure(createData, routingBinding)
 
 	if response != nil {
 		// TODO: Based on TS 29.531 5.3.2.3.1, add location header
@@ -0,0 +1,42 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF SBI
+ *
+ * Binding indications, TS 29.500 clause 6.12
+ */
+
+package sbi
+
+import (
+	"slices"
+	"strings"
+)
+
+// Parameters of a binding indication which identify the target, and are kept in the routing binding
+var routingBindingParameters = []string{
+	"bl", "nfinst", "nfset", "nfservinst", "nfserviceset", "servname", "backupamfinst", "backupnf",
+}
+
+// CallbackRoutingBinding returns the value of the 3gpp-Sbi-Routing-Binding header of the notifications of
+// a subscription, given the 3gpp-Sbi-Binding header of the request which created it. It returns an empty
+// string if the binding indication does not apply to notifications.
+func CallbackRoutingBinding(binding string) string {
+	var routingBinding []string
+	for parameter := range strings.SplitSeq(binding, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(parameter), "=")
+		if !found {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if name == "scope" && !slices.Contains(strings.Fields(value), "callback") {
+			return ""
+		}
+		if slices.Contains(routingBindingParameters, name) {
+			routingBinding = append(routingBinding, name+"="+value)
+		}
+	}
+	return strings.Join(routingBinding, "; ")
+}
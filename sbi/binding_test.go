@@ -0,0 +1,23 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package sbi
+
+import "testing"
+
+func TestCallbackRoutingBinding(t *testing.T) {
+	tests := []struct {
+		binding        string
+		routingBinding string
+	}{
+		{binding: "", routingBinding: ""},
+		{binding: "bl=nfset; nfset=set1.amfset.5gc.mnc093.mcc208", routingBinding: "bl=nfset; nfset=set1.amfset.5gc.mnc093.mcc208"},
+		{binding: "bl=nfinstance; nfinst=amf-1; scope=callback; recoverytime=2026-01-01T00:00:00Z", routingBinding: "bl=nfinstance; nfinst=amf-1"},
+		{binding: "bl=nfinstance; nfinst=amf-1; scope=other-service", routingBinding: ""},
+	}
+	for _, tt := range tests {
+		if routingBinding := CallbackRoutingBinding(tt.binding); routingBinding != tt.routingBinding {
+			t.Errorf("expected routing binding %q of binding %q, got %q", tt.routingBinding, tt.binding, routingBinding)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF SBI
+ *
+ * Custom HTTP headers of the Service Based Interfaces, TS 29.500 clause 5.2.3
+ */
+
+package sbi
+
+const (
+	HeaderTargetApiRoot  = "3gpp-Sbi-Target-apiRoot"
+	HeaderRoutingBinding = "3gpp-Sbi-Routing-Binding"
+	HeaderBinding        = "3gpp-Sbi-Binding"
+	HeaderProducerId     = "3gpp-Sbi-Producer-Id"
+	HeaderCallback       = "3gpp-Sbi-Callback"
+	HeaderLci            = "3gpp-Sbi-Lci"
+	HeaderOci            = "3gpp-Sbi-Oci"
+
+	// Prefix of the headers which carry the discovery parameters of TS 29.510 clause 6.2.3.2.3.1
+	HeaderDiscoveryPrefix          = "3gpp-Sbi-Discovery-"
+	HeaderDiscoveryTargetNfType    = HeaderDiscoveryPrefix + "target-nf-type"
+	HeaderDiscoveryRequesterNfType = HeaderDiscoveryPrefix + "requester-nf-type"
+	HeaderDiscoveryServiceNames    = HeaderDiscoveryPrefix + "service-names"
+)
+
+// Causes of the requests rejected by the overload control, the rate limits and the request limits,
+// TS 29.500 clause 5.2.7.2
+const (
+	CauseNfCongestionRisk  = "NF_CONGESTION_RISK"
+	CauseNfCongestion      = "NF_CONGESTION"
+	CauseInvalidQueryParam = "INVALID_QUERY_PARAM"
+)
+
+// Callback types of the 3gpp-Sbi-Callback header, TS 29.500 clause 6.10.3.3
+const (
+	CallbackNssaiAvailabilityNotification = "Nnssf_NSSAIAvailability_Notification"
+)
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF SBI
+ *
+ * Requests received through an SCP, TS 29.500 clause 6.10
+ */
+
+package sbi
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	nssfContext "github.com/omec-project/nssf/context"
+	"github.com/omec-project/nssf/logger"
+)
+
+// ViaScp reports whether the request was sent through an SCP, that is whether it carries headers
+// of the indirect communication
+func ViaScp(header http.Header) bool {
+	if header.Get(HeaderTargetApiRoot) != "" || header.Get(HeaderRoutingBinding) != "" {
+		return true
+	}
+	for key := range header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(key), http.CanonicalHeaderKey(HeaderDiscoveryPrefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IndirectCommunication accepts the requests which an SCP forwards to the NSSF. The response identifies
+// the NSSF in the 3gpp-Sbi-Producer-Id header, so that the SCP can route later requests to it.
+func IndirectCommunication() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ViaScp(c.Request.Header) {
+			logger.HandlerLog.Debugf("request %s %s received through an SCP, target apiRoot %s",
+				c.Request.Method, c.Request.URL.Path, c.GetHeader(HeaderTargetApiRoot))
+			c.Header(HeaderProducerId, "nfinst="+nssfContext.NSSF_Self().NfId)
+		}
+		c.Next()
+	}
+}
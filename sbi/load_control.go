@@ -0,0 +1,204 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF SBI
+ *
+ * Load control and overload control, TS 29.500 clauses 6.3 and 6.4
+ */
+
+package sbi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	nssfContext "github.com/omec-project/nssf/context"
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/logger"
+	stats "github.com/omec-project/nssf/metrics"
+	"github.com/omec-project/openapi/v2/utils"
+)
+
+// Default values of the loadControl configuration
+const (
+	defaultOverloadThreshold = 80
+	defaultRejectThreshold   = 100
+	defaultRetryAfter        = time.Second
+	defaultOverloadValidity  = 10 * time.Second
+)
+
+// Seconds over which the request rate is measured
+const loadWindow = 5
+
+// Format of the timestamps of the LCI and OCI headers, TS 29.500 clause 5.2.3.3
+const loadControlTimestampFormat = "Mon, 02 Jan 2006 15:04:05.000 GMT"
+
+// loadController measures the rate of the requests to the NSSF, and decides which ones are admitted
+type loadController struct {
+	capacity          int
+	overloadThreshold int
+	rejectThreshold   int
+	retryAfter        time.Duration
+	overloadValidity  time.Duration
+
+	mutex sync.Mutex
+	// Number of requests received in each of the last seconds, and the seconds they were received in
+	requests       [loadWindow]int
+	requestSeconds [loadWindow]int64
+	// Number of requests received while overloaded, to reject the share given by the reduction
+	overloadedRequests int
+}
+
+// admission is the decision of the load controller about a request
+type admission struct {
+	// Load in % of the capacity, possibly above 100
+	load int
+	// Overload-Reduction-Metric, 0 if the NSSF is not overloaded
+	reduction int
+	// Status of the rejection, 0 if the request is admitted
+	status int
+}
+
+var loadControl atomic.Pointer[loadController]
+
+func newLoadController(config factory.LoadControlConfig) *loadController {
+	l := &loadController{
+		capacity:          config.Capacity,
+		overloadThreshold: config.OverloadThreshold,
+		rejectThreshold:   config.RejectThreshold,
+		retryAfter:        time.Duration(config.RetryAfter) * time.Second,
+		overloadValidity:  time.Duration(config.OverloadValidity) * time.Second,
+	}
+	if l.overloadThreshold == 0 {
+		l.overloadThreshold = defaultOverloadThreshold
+	}
+	if l.rejectThreshold == 0 {
+		l.rejectThreshold = max(defaultRejectThreshold, l.overloadThreshold)
+	}
+	if l.retryAfter == 0 {
+		l.retryAfter = defaultRetryAfter
+	}
+	if l.overloadValidity == 0 {
+		l.overloadValidity = defaultOverloadValidity
+	}
+	return l
+}
+
+// loadLocked returns the request rate over the last seconds, in % of the capacity
+func (l *loadController) loadLocked(now time.Time) int {
+	second := now.Unix()
+	requests := 0
+	for i, requestSecond := range l.requestSeconds {
+		if requestSecond > second-loadWindow && requestSecond <= second {
+			requests += l.requests[i]
+		}
+	}
+	return requests * 100 / (l.capacity * loadWindow)
+}
+
+func (l *loadController) load(now time.Time) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.loadLocked(now)
+}
+
+// admit records the request, and decides whether it is admitted. Above the overload threshold, the share
+// of the requests given by the reduction is rejected, and above the reject threshold all requests are.
+func (l *loadController) admit(now time.Time) admission {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	second := now.Unix()
+	if i := second % loadWindow; l.requestSeconds[i] != second {
+		l.requestSeconds[i] = second
+		l.requests[i] = 1
+	} else {
+		l.requests[i]++
+	}
+
+	decision := admission{load: l.loadLocked(now)}
+	switch {
+	case decision.load > l.rejectThreshold:
+		decision.reduction = 100
+		decision.status = http.StatusServiceUnavailable
+	case decision.load > l.overloadThreshold:
+		decision.reduction = (decision.load - l.overloadThreshold) * 100 / decision.load
+		l.overloadedRequests++
+		if l.overloadedRequests*decision.reduction/100 != (l.overloadedRequests-1)*decision.reduction/100 {
+			decision.status = http.StatusTooManyRequests
+		}
+	default:
+		l.overloadedRequests = 0
+	}
+	return decision
+}
+
+func loadControlTimestamp(now time.Time) string {
+	return `"` + now.UTC().Format(loadControlTimestampFormat) + `"`
+}
+
+// lci returns the value of the 3gpp-Sbi-Lci header, TS 29.500 clause 5.2.3.2.18
+func lci(now time.Time, load int, nfId string) string {
+	return fmt.Sprintf("Timestamp: %s; Load-Metric: %d%%; NF-Instance: %s", loadControlTimestamp(now), min(load, 100), nfId)
+}
+
+// oci returns the value of the 3gpp-Sbi-Oci header, TS 29.500 clause 5.2.3.2.19
+func oci(now time.Time, validity time.Duration, reduction int, nfId string) string {
+	return fmt.Sprintf("Timestamp: %s; Period-of-Validity: %ds; Overload-Reduction-Metric: %d%%; NF-Instance: %s",
+		loadControlTimestamp(now), int(validity.Seconds()), reduction, nfId)
+}
+
+// LoadControl returns the middleware which signals the load of the NSSF in the responses, and rejects the
+// requests the NSSF is overloaded with. Callbacks are always admitted. Load control is disabled if not configured.
+func LoadControl(config *factory.LoadControlConfig) gin.HandlerFunc {
+	if config == nil {
+		loadControl.Store(nil)
+		return func(c *gin.Context) { c.Next() }
+	}
+	l := newLoadController(*config)
+	loadControl.Store(l)
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.Request.URL.Path, nssfContext.CallbackUriPrefix) {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		decision := l.admit(now)
+		nfId := nssfContext.NSSF_Self().NfId
+		c.Header(HeaderLci, lci(now, decision.load, nfId))
+		if decision.reduction != 0 {
+			c.Header(HeaderOci, oci(now, l.overloadValidity, decision.reduction, nfId))
+		}
+		if decision.status == 0 {
+			c.Next()
+			return
+		}
+
+		cause := CauseNfCongestionRisk
+		if decision.status == http.StatusServiceUnavailable {
+			cause = CauseNfCongestion
+		}
+		logger.HandlerLog.Warnf("request %s %s rejected, load %d%%", c.Request.Method, c.Request.URL.Path, decision.load)
+		stats.IncrementNssfRejectedRequestsStats(cause)
+		problemDetails := utils.ProblemDetails(http.StatusText(decision.status), decision.status,
+			fmt.Sprintf("the NSSF is overloaded, load %d%%", decision.load))
+		problemDetails.SetCause(cause)
+		c.Header("Retry-After", strconv.Itoa(int(l.retryAfter.Seconds())))
+		c.AbortWithStatusJSON(decision.status, problemDetails)
+	}
+}
+
+// CurrentLoad returns the load of the NSSF in %, between 0 and 100, if load control is enabled
+func CurrentLoad() (int32, bool) {
+	l := loadControl.Load()
+	if l == nil {
+		return 0, false
+	}
+	return int32(min(l.load(time.Now()), 100)), true
+}
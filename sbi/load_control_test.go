@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package sbi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	nssfContext "github.com/omec-project/nssf/context"
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+func TestLoadControllerAdmission(t *testing.T) {
+	// 50 requests in the window are a load of 100%
+	l := newLoadController(factory.LoadControlConfig{Capacity: 10})
+	now := time.Now()
+
+	rejected := map[int]int{}
+	for range 60 {
+		rejected[l.admit(now).status]++
+	}
+	if rejected[0] < 40 || rejected[http.StatusTooManyRequests] == 0 || rejected[http.StatusServiceUnavailable] != 10 {
+		t.Fatalf("expected requests to be admitted, then partly and fully rejected, got %+v", rejected)
+	}
+
+	if decision := l.admit(now.Add(loadWindow * time.Second)); decision.status != 0 || decision.load != 2 {
+		t.Fatalf("expected the request to be admitted once the load is measured again, got %+v", decision)
+	}
+}
+
+func TestLoadControlMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// 5 requests in the window are a load of 100%
+	router.Use(LoadControl(&factory.LoadControlConfig{Capacity: 1, OverloadThreshold: 50}))
+	t.Cleanup(func() { LoadControl(nil) })
+	router.GET("/nnssf-nsselection/v2/network-slice-information", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST(nssfContext.CallbackUriPrefix+nssfContext.NfStatusNotifyPath, func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	request := func(method, target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+		return recorder
+	}
+	for i := 1; i <= 5; i++ {
+		rsp := request(http.MethodGet, "/nnssf-nsselection/v2/network-slice-information")
+		if rsp.Code != http.StatusOK || !strings.Contains(rsp.Header().Get(HeaderLci), "Load-Metric: ") {
+			t.Fatalf("expected request %d to be admitted with its load, got %d %+v", i, rsp.Code, rsp.Header())
+		}
+		if overloaded := rsp.Header().Get(HeaderOci) != ""; overloaded != (i >= 3) {
+			t.Fatalf("expected request %d to signal an overload %v, got %+v", i, i >= 3, rsp.Header())
+		}
+	}
+
+	rsp := request(http.MethodGet, "/nnssf-nsselection/v2/network-slice-information")
+	var problemDetails models.ProblemDetails
+	if err := json.Unmarshal(rsp.Body.Bytes(), &problemDetails); err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Code != http.StatusServiceUnavailable || rsp.Header().Get("Retry-After") != "1" ||
+		problemDetails.GetCause() != CauseNfCongestion {
+		t.Fatalf("expected the request to be rejected, got %d %+v %+v", rsp.Code, rsp.Header(), problemDetails)
+	}
+	if load, enabled := CurrentLoad(); !enabled || load != 100 {
+		t.Fatalf("expected a load of 100%%, got %d", load)
+	}
+
+	if rsp = request(http.MethodPost, nssfContext.CallbackUriPrefix+nssfContext.NfStatusNotifyPath); rsp.Code != http.StatusNoContent {
+		t.Fatalf("expected the callback to be admitted, got %d", rsp.Code)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF SBI
+ *
+ * Token-bucket rate limits of the NF service consumers
+ */
+
+package sbi
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/logger"
+	stats "github.com/omec-project/nssf/metrics"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/openapi/v2/utils"
+)
+
+// Interval at which the buckets of the consumers which stopped sending requests are removed
+const rateLimitPruneInterval = time.Minute
+
+// rateLimitClass is the rate limit of a class of NF service consumers
+type rateLimitClass struct {
+	name   string
+	nfType models.NFType
+	nfIds  []string
+	// Tokens per second, and maximum number of tokens of a bucket
+	rate  float64
+	burst float64
+}
+
+func (c *rateLimitClass) matches(nfType, nfId string) bool {
+	return (c.nfType == "" || string(c.nfType) == nfType) && (len(c.nfIds) == 0 || slices.Contains(c.nfIds, nfId))
+}
+
+// tokenBucket is the bucket of an NF service consumer, from which each of its requests takes a token
+type tokenBucket struct {
+	class   *rateLimitClass
+	tokens  float64
+	updated time.Time
+	// Whether the last request of the consumer was rejected, to log once per rejected burst
+	throttled bool
+}
+
+// refill adds the tokens accumulated since the last update of the bucket
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.updated) {
+		b.tokens = min(b.class.burst, b.tokens+now.Sub(b.updated).Seconds()*b.class.rate)
+		b.updated = now
+	}
+}
+
+// rateLimiter holds the buckets of the NF service consumers, keyed by the service they consume, their NF type and
+// NF instance ID, so that the requests to one service do not exhaust the rate limit of the other
+type rateLimiter struct {
+	classes []rateLimitClass
+
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+	pruned  time.Time
+}
+
+var rateLimits atomic.Pointer[rateLimiter]
+
+func newRateLimiter(config []factory.RateLimitConfig) *rateLimiter {
+	r := &rateLimiter{buckets: map[string]*tokenBucket{}}
+	for _, rateLimit := range config {
+		burst := float64(rateLimit.Burst)
+		if burst == 0 {
+			burst = math.Ceil(rateLimit.Rate)
+		}
+		r.classes = append(r.classes, rateLimitClass{
+			name:   rateLimit.Name,
+			nfType: rateLimit.NfType,
+			nfIds:  rateLimit.NfIdList,
+			rate:   rateLimit.Rate,
+			burst:  burst,
+		})
+	}
+	return r
+}
+
+// allow takes a token of the bucket of the consumer of the service. If the bucket is empty, it returns the class
+// of the consumer and the time after which a token is available. Consumers which match no class are not limited.
+func (r *rateLimiter) allow(serviceName models.ServiceName, nfType, nfId string, now time.Time) (
+	class string, retryAfter time.Duration, allowed bool,
+) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.pruneLocked(now)
+
+	key := string(serviceName) + "/" + nfType + "/" + nfId
+	bucket, ok := r.buckets[key]
+	if !ok {
+		i := slices.IndexFunc(r.classes, func(c rateLimitClass) bool { return c.matches(nfType, nfId) })
+		if i < 0 {
+			return "", 0, true
+		}
+		bucket = &tokenBucket{class: &r.classes[i], tokens: r.classes[i].burst, updated: now}
+		r.buckets[key] = bucket
+	}
+
+	bucket.refill(now)
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		bucket.throttled = false
+		return bucket.class.name, 0, true
+	}
+	if !bucket.throttled {
+		logger.HandlerLog.Warnf("NF service consumer %s exceeded the rate limit of class %s, %v requests/s",
+			key, bucket.class.name, bucket.class.rate)
+		bucket.throttled = true
+	}
+	retryAfter = time.Duration((1 - bucket.tokens) / bucket.class.rate * float64(time.Second))
+	return bucket.class.name, retryAfter, false
+}
+
+// pruneLocked removes the buckets which are full, as they would be created again with the same tokens
+func (r *rateLimiter) pruneLocked(now time.Time) {
+	if now.Sub(r.pruned) < rateLimitPruneInterval {
+		return
+	}
+	r.pruned = now
+	for key, bucket := range r.buckets {
+		if bucket.refill(now); bucket.tokens >= bucket.class.burst {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// SetRateLimits configures the rate limits of the NF service consumers. Requests are not limited if none is configured.
+func SetRateLimits(config []factory.RateLimitConfig) {
+	if len(config) == 0 {
+		rateLimits.Store(nil)
+		return
+	}
+	rateLimits.Store(newRateLimiter(config))
+}
+
+// ThrottleRequest takes a token of the rate limit of the NF service consumer for the service. If the consumer
+// exceeded its rate limit, it returns the ProblemDetails rejecting the request, and the time after which the
+// consumer may retry. Each service has its own rate limit, with the rate and burst of the class of the consumer.
+func ThrottleRequest(serviceName models.ServiceName, nfType, nfId string) (*models.ProblemDetails, time.Duration) {
+	r := rateLimits.Load()
+	if r == nil {
+		return nil, 0
+	}
+	class, retryAfter, allowed := r.allow(serviceName, nfType, nfId, time.Now())
+	if allowed {
+		return nil, 0
+	}
+
+	stats.IncrementNssfThrottledRequestsStats(class, nfType)
+	problemDetails := utils.ProblemDetails(http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests,
+		fmt.Sprintf("the NF service consumer exceeded the rate limit of class %s", class))
+	problemDetails.SetCause(CauseNfCongestion)
+	return problemDetails, retryAfter
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package sbi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+func TestRateLimiterBucketsPerConsumer(t *testing.T) {
+	r := newRateLimiter([]factory.RateLimitConfig{
+		{Name: "lab-amf", NfType: models.NFTYPE_AMF, NfIdList: []string{"amf-lab"}, Rate: 100},
+		{Name: "amf", NfType: models.NFTYPE_AMF, Rate: 2, Burst: 3},
+	})
+	now := time.Now()
+
+	for i := 1; i <= 3; i++ {
+		if _, _, allowed := r.allow(models.SERVICENAME_NNSSF_NSSELECTION, "AMF", "amf-1", now); !allowed {
+			t.Fatalf("expected request %d of the burst to be allowed", i)
+		}
+	}
+	class, retryAfter, allowed := r.allow(models.SERVICENAME_NNSSF_NSSELECTION, "AMF", "amf-1", now)
+	if allowed || class != "amf" || retryAfter != 500*time.Millisecond {
+		t.Fatalf("expected the request after the burst to be throttled for 500ms, got %s %v %v", class, retryAfter, allowed)
+	}
+
+	// Other consumers have their own buckets, and consumers which match no class are not limited
+	for _, consumer := range [][2]string{{"AMF", "amf-2"}, {"AMF", "amf-lab"}, {"NSSF", "nssf-1"}} {
+		if _, _, allowed = r.allow(models.SERVICENAME_NNSSF_NSSELECTION, consumer[0], consumer[1], now); !allowed {
+			t.Fatalf("expected the request of %v not to be throttled", consumer)
+		}
+	}
+
+	// The consumer has its own bucket for each service
+	if _, _, allowed = r.allow(models.SERVICENAME_NNSSF_NSSAIAVAILABILITY, "AMF", "amf-1", now); !allowed {
+		t.Fatal("expected the request to the other service not to be throttled")
+	}
+
+	if _, _, allowed = r.allow(models.SERVICENAME_NNSSF_NSSELECTION, "AMF", "amf-1", now.Add(500*time.Millisecond)); !allowed {
+		t.Fatal("expected the request to be allowed once a token is refilled")
+	}
+	if _, _, allowed = r.allow(models.SERVICENAME_NNSSF_NSSELECTION, "AMF", "amf-1", now.Add(500*time.Millisecond)); allowed {
+		t.Fatal("expected the next request to be throttled again")
+	}
+
+	r.allow(models.SERVICENAME_NNSSF_NSSELECTION, "AMF", "amf-2", now.Add(rateLimitPruneInterval))
+	if len(r.buckets) != 1 {
+		t.Fatalf("expected the full buckets to be pruned, got %d buckets", len(r.buckets))
+	}
+}
+
+func TestThrottleRequest(t *testing.T) {
+	SetRateLimits([]factory.RateLimitConfig{{Name: "amf", NfType: models.NFTYPE_AMF, Rate: 0.1}})
+	t.Cleanup(func() { SetRateLimits(nil) })
+
+	if problemDetails, _ := ThrottleRequest(models.SERVICENAME_NNSSF_NSSELECTION, "AMF", "amf-1"); problemDetails != nil {
+		t.Fatalf("expected the first request to be allowed, got %+v", problemDetails)
+	}
+	problemDetails, retryAfter := ThrottleRequest(models.SERVICENAME_NNSSF_NSSELECTION, "AMF", "amf-1")
+	if problemDetails == nil || problemDetails.GetStatus() != http.StatusTooManyRequests ||
+		problemDetails.GetCause() != CauseNfCongestion || retryAfter <= 0 || retryAfter > 10*time.Second {
+		t.Fatalf("expected the second request to be throttled with NF_CONGESTION, got %+v %v", problemDetails, retryAfter)
+	}
+
+	SetRateLimits(nil)
+	if problemDetails, _ = ThrottleRequest(models.SERVICENAME_NNSSF_NSSELECTION, "AMF", "amf-1"); problemDetails != nil {
+		t.Fatalf("expected requests not to be limited without rate limits, got %+v", problemDetails)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF SBI
+ *
+ * Limits of the size of the SBI requests
+ */
+
+package sbi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/logger"
+	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/openapi/v2/utils"
+)
+
+// Default values of the requestLimits configuration
+const (
+	defaultMaxUriLength       = 8192
+	defaultMaxQueryParameters = 256
+	defaultMaxBodySize        = 1 << 20
+	defaultMaxSnssaiListSize  = 64
+	defaultMaxTaiListSize     = 1024
+	defaultMaxPatchItems      = 256
+)
+
+var requestLimits atomic.Pointer[factory.RequestLimitsConfig]
+
+func init() {
+	requestLimits.Store(newRequestLimits(nil))
+}
+
+// newRequestLimits returns the configured limits, with the default values of those not configured
+func newRequestLimits(config *factory.RequestLimitsConfig) *factory.RequestLimitsConfig {
+	limits := factory.RequestLimitsConfig{}
+	if config != nil {
+		limits = *config
+	}
+	if limits.MaxUriLength == 0 {
+		limits.MaxUriLength = defaultMaxUriLength
+	}
+	if limits.MaxQueryParameters == 0 {
+		limits.MaxQueryParameters = defaultMaxQueryParameters
+	}
+	if limits.MaxBodySize == 0 {
+		limits.MaxBodySize = defaultMaxBodySize
+	}
+	if limits.MaxSnssaiListSize == 0 {
+		limits.MaxSnssaiListSize = defaultMaxSnssaiListSize
+	}
+	if limits.MaxTaiListSize == 0 {
+		limits.MaxTaiListSize = defaultMaxTaiListSize
+	}
+	if limits.MaxPatchItems == 0 {
+		limits.MaxPatchItems = defaultMaxPatchItems
+	}
+	return &limits
+}
+
+// CurrentRequestLimits returns the limits of the SBI requests, with the default values of those not configured
+func CurrentRequestLimits() factory.RequestLimitsConfig {
+	return *requestLimits.Load()
+}
+
+// RequestLimits returns the middleware which rejects the requests whose URI, query or body exceed the limits.
+// Bodies without Content-Length are limited as they are read, see ProblemDetailsRequestBody.
+func RequestLimits(config *factory.RequestLimitsConfig) gin.HandlerFunc {
+	limits := newRequestLimits(config)
+	requestLimits.Store(limits)
+	return func(c *gin.Context) {
+		if problemDetails := checkRequestLimits(c.Request, limits); problemDetails != nil {
+			logger.HandlerLog.Warnf("request %s %.64s rejected: %s", c.Request.Method, c.Request.URL.Path,
+				problemDetails.GetDetail())
+			c.AbortWithStatusJSON(int(problemDetails.GetStatus()), problemDetails)
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxBodySize)
+		}
+		c.Next()
+	}
+}
+
+func checkRequestLimits(request *http.Request, limits *factory.RequestLimitsConfig) *models.ProblemDetails {
+	requestUri := request.RequestURI
+	if requestUri == "" {
+		requestUri = request.URL.RequestURI()
+	}
+	queryParameters := strings.FieldsFunc(request.URL.RawQuery, func(r rune) bool { return r == '&' })
+
+	if len(requestUri) > limits.MaxUriLength {
+		detail := fmt.Sprintf("the URI of %d bytes exceeds the limit of %d bytes", len(requestUri), limits.MaxUriLength)
+		var invalidParams []models.InvalidParam
+		// The longest query parameter is the likely cause
+		if longest := longestQueryParameter(queryParameters); longest != "" {
+			invalidParams = []models.InvalidParam{{Param: longest, Reason: &detail}}
+		}
+		return utils.ProblemDetailsWithInvalidParams(http.StatusText(http.StatusRequestURITooLong),
+			http.StatusRequestURITooLong, detail, invalidParams)
+	}
+
+	if len(queryParameters) > limits.MaxQueryParameters {
+		detail := fmt.Sprintf("%d query parameters exceed the limit of %d", len(queryParameters), limits.MaxQueryParameters)
+		// The first query parameter beyond the limit
+		invalidParams := []models.InvalidParam{{Param: queryParameterName(queryParameters[limits.MaxQueryParameters]), Reason: &detail}}
+		problemDetails := utils.ProblemDetailsWithInvalidParams(util.INVALID_REQUEST, http.StatusBadRequest, detail, invalidParams)
+		problemDetails.SetCause(CauseInvalidQueryParam)
+		return problemDetails
+	}
+
+	if request.ContentLength > limits.MaxBodySize {
+		return payloadTooLarge("Content-Length", limits.MaxBodySize)
+	}
+	return nil
+}
+
+func queryParameterName(queryParameter string) string {
+	name, _, _ := strings.Cut(queryParameter, "=")
+	return name
+}
+
+func longestQueryParameter(queryParameters []string) string {
+	longest := ""
+	for _, queryParameter := range queryParameters {
+		if len(queryParameter) > len(longest) {
+			longest = queryParameter
+		}
+	}
+	return queryParameterName(longest)
+}
+
+func payloadTooLarge(param string, limit int64) *models.ProblemDetails {
+	detail := fmt.Sprintf("the request body exceeds the limit of %d bytes", limit)
+	return utils.ProblemDetailsWithInvalidParams(http.StatusText(http.StatusRequestEntityTooLarge),
+		http.StatusRequestEntityTooLarge, detail, []models.InvalidParam{{Param: param, Reason: &detail}})
+}
+
+// ProblemDetailsRequestBody returns the ProblemDetails of an error reading the request body, 413 Payload
+// Too Large if the body exceeds the limit, and 500 Internal Server Error otherwise
+func ProblemDetailsRequestBody(err error) *models.ProblemDetails {
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return payloadTooLarge("body", maxBytesError.Limit)
+	}
+	return utils.ProblemDetailsSystemFailure(err.Error())
+}
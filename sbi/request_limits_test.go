@@ -0,0 +1,103 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package sbi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/openapi/v2/models"
+)
+
+func TestRequestLimitsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestLimits(&factory.RequestLimitsConfig{MaxUriLength: 128, MaxQueryParameters: 3, MaxBodySize: 16}))
+	t.Cleanup(func() { RequestLimits(nil) })
+	router.GET("/nnssf-nsselection/v2/network-slice-information", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.PUT("/nnssf-nssaiavailability/v1/nssai-availability/:nfId", func(c *gin.Context) {
+		if _, err := c.GetRawData(); err != nil {
+			problemDetails := ProblemDetailsRequestBody(err)
+			c.JSON(int(problemDetails.GetStatus()), problemDetails)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name          string
+		request       *http.Request
+		expectStatus  int
+		expectCause   string
+		expectInvalid string
+	}{
+		{
+			name:         "request within the limits",
+			request:      httptest.NewRequest(http.MethodGet, "/nnssf-nsselection/v2/network-slice-information?nf-type=AMF&nf-id=1", nil),
+			expectStatus: http.StatusOK,
+		},
+		{
+			name: "URI too long",
+			request: httptest.NewRequest(http.MethodGet,
+				"/nnssf-nsselection/v2/network-slice-information?nf-type=AMF&tai="+strings.Repeat("a", 100), nil),
+			expectStatus:  http.StatusRequestURITooLong,
+			expectInvalid: "tai",
+		},
+		{
+			name:          "too many query parameters",
+			request:       httptest.NewRequest(http.MethodGet, "/nnssf-nsselection/v2/network-slice-information?a=1&b=2&c=3&d=4&e=5", nil),
+			expectStatus:  http.StatusBadRequest,
+			expectCause:   CauseInvalidQueryParam,
+			expectInvalid: "d",
+		},
+		{
+			name: "body with too large Content-Length",
+			request: httptest.NewRequest(http.MethodPut, "/nnssf-nssaiavailability/v1/nssai-availability/amf-1",
+				strings.NewReader(strings.Repeat("a", 17))),
+			expectStatus:  http.StatusRequestEntityTooLarge,
+			expectInvalid: "Content-Length",
+		},
+		{
+			name: "body without Content-Length exceeding the limit",
+			request: func() *http.Request {
+				request := httptest.NewRequest(http.MethodPut, "/nnssf-nssaiavailability/v1/nssai-availability/amf-1",
+					io.MultiReader(strings.NewReader(strings.Repeat("a", 17))))
+				request.ContentLength = -1
+				return request
+			}(),
+			expectStatus:  http.StatusRequestEntityTooLarge,
+			expectInvalid: "body",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, tc.request)
+			if recorder.Code != tc.expectStatus {
+				t.Fatalf("expected status %d, got %d %s", tc.expectStatus, recorder.Code, recorder.Body.String())
+			}
+			if tc.expectStatus == http.StatusOK {
+				return
+			}
+			var problemDetails models.ProblemDetails
+			if err := json.Unmarshal(recorder.Body.Bytes(), &problemDetails); err != nil {
+				t.Fatal(err)
+			}
+			if problemDetails.GetCause() != tc.expectCause || len(problemDetails.InvalidParams) != 1 ||
+				problemDetails.InvalidParams[0].Param != tc.expectInvalid {
+				t.Errorf("expected cause %q and invalid param %q, got %+v", tc.expectCause, tc.expectInvalid, problemDetails)
+			}
+		})
+	}
+
+	if limits := CurrentRequestLimits(); limits.MaxQueryParameters != 3 || limits.MaxTaiListSize != defaultMaxTaiListSize {
+		t.Errorf("expected the configured limits and the default values of the others, got %+v", limits)
+	}
+}
@@ -14,7 +14,10 @@ import (
 	"path/filepath"
 	"sync"
 	"syscall"
+	"time"
 
+	"github.com/omec-project/nssf/admin"
+	"github.com/omec-project/nssf/callback"
 	nssfContext "github.com/omec-project/nssf/context"
 	"github.com/omec-project/nssf/factory"
 	"github.com/omec-project/nssf/logger"
@@ -23,6 +26,8 @@ import (
 	"github.com/omec-project/nssf/nssaiavailability"
 	"github.com/omec-project/nssf/nsselection"
 	"github.com/omec-project/nssf/polling"
+	"github.com/omec-project/nssf/producer"
+	"github.com/omec-project/nssf/sbi"
 	openapiLogger "github.com/omec-project/openapi/v2/logger"
 	"github.com/omec-project/openapi/v2/models"
 	"github.com/omec-project/util/http2_util"
@@ -97,27 +102,48 @@ func (nssf *NSSF) Start() {
 	logger.InitLog.Infoln("server started")
 
 	router := utilLogger.NewGinWithZap(logger.GinLog)
+	router.Use(sbi.IndirectCommunication(), sbi.LoadControl(factory.NssfConfig.Configuration.LoadControl),
+		sbi.RequestLimits(factory.NssfConfig.Configuration.RequestLimits))
+	sbi.SetRateLimits(factory.NssfConfig.Configuration.RateLimits)
 
 	nssaiavailability.AddService(router)
 	nsselection.AddService(router)
+	callback.AddService(router)
 
 	go metrics.InitMetrics()
 
+	if adminConfig := factory.NssfConfig.Configuration.Admin; adminConfig != nil && adminConfig.Enable {
+		go admin.StartServer(adminConfig)
+	}
+
 	self := nssfContext.NSSF_Self()
 	addr := fmt.Sprintf("%s:%d", self.BindingIPv4, self.SBIPort)
 
 	plmnConfigChan := make(chan []models.PlmnId, 1)
+	factory.ConfigLock.Lock()
+	if plmnList := factory.NssfConfig.Configuration.PlmnList; len(plmnList) != 0 {
+		// Register the PLMNs provisioned through the admin API without waiting for the webconsole
+		plmnConfigChan <- plmnList
+	}
+	producer.PlmnListChanged = func(plmnList []models.PlmnId) { plmnConfigChan <- plmnList }
+	factory.ConfigLock.Unlock()
 	ctx, cancelServices := context.WithCancel(context.Background())
 	var wg sync.WaitGroup
-	wg.Add(2)
+	wg.Add(3)
 	go func() {
 		defer wg.Done()
 		polling.StartPollingService(ctx, factory.NssfConfig.Configuration.WebuiUri, plmnConfigChan)
 	}()
+	nfregistration.AmfStatusSynchronizer = producer.SynchronizeAmfStatus
 	go func() {
 		defer wg.Done()
 		nfregistration.StartNfRegistrationService(ctx, plmnConfigChan)
 	}()
+	go func() {
+		defer wg.Done()
+		ttl := time.Duration(factory.NssfConfig.Configuration.AmfAvailabilityTtl) * time.Second
+		producer.StartAmfAvailabilityExpiry(ctx, ttl, nfregistration.AmfStatusSubscriptionActive)
+	}()
 
 	signalChannel := make(chan os.Signal, 1)
 	signal.Notify(signalChannel, os.Interrupt, syscall.SIGTERM)
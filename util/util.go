@@ -11,9 +11,10 @@
 package util
 
 import (
-	"encoding/json"
 	"fmt"
-	"reflect"
+	"math"
+	"slices"
+	"sort"
 
 	"github.com/omec-project/nssf/factory"
 	"github.com/omec-project/nssf/logger"
@@ -21,52 +22,62 @@ import (
 	"github.com/omec-project/openapi/v2/models"
 )
 
+// Causes of S-NSSAIs rejected by the NSSF
+// The openapi models have no attribute for the cause, so it is logged and counted in metrics
+const (
+	REJECTED_CAUSE_ALLOWED_NSSAI_LIMIT   = "ALLOWED_NSSAI_LIMIT_REACHED"
+	REJECTED_CAUSE_NOT_SUPPORTED_IN_PLMN = "SNSSAI_NOT_SUPPORTED_IN_PLMN"
+	REJECTED_CAUSE_NOT_AUTHORIZED        = "SNSSAI_NOT_AUTHORIZED"
+	REJECTED_CAUSE_NSSRG                 = "SNSSAI_NOT_IN_COMMON_NSSRG"
+)
+
+// RejectedSnssaiAvailability is an S-NSSAI whose availability, as provided by an AMF, is not authorized
+type RejectedSnssaiAvailability struct {
+	Snssai models.Snssai
+	// TAI of the NSSAI availability data the S-NSSAI was provided in
+	Tai   models.Tai
+	Cause string
+}
+
 // Title in Problem Details for NSSF HTTP APIs
 const (
 	INVALID_REQUEST       = "Invalid request message framing"
 	UNAUTHORIZED_CONSUMER = "Unauthorized NF service consumer"
 	UNSUPPORTED_RESOURCE  = "Unsupported request resources"
+	PRECONDITION_FAILED   = "Precondition failed"
 )
 
 // Check whether UE's Home PLMN is configured/supported
-func CheckSupportedHplmn(homePlmnId models.PlmnId) bool {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	for _, mappingFromPlmn := range factory.NssfConfig.Configuration.MappingListFromPlmn {
-		if *mappingFromPlmn.HomePlmnId == homePlmnId {
-			return true
-		}
+func CheckSupportedHplmn(cfg *factory.Snapshot, homePlmnId models.PlmnId) bool {
+	if _, found := cfg.MappingOfPlmn(homePlmnId); found {
+		return true
 	}
 	logger.Util.Warnf("no Home PLMN %+v in NSSF configuration", homePlmnId)
 	return false
 }
 
 // Check whether UE's current TA is configured/supported
-func CheckSupportedTa(tai models.Tai) bool {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	for _, taConfig := range factory.NssfConfig.Configuration.TaList {
-		if reflect.DeepEqual(*taConfig.Tai, tai) {
-			return true
-		}
-	}
-	e, err := json.Marshal(tai)
-	if err != nil {
-		logger.Util.Errorf("marshal error in CheckSupportedTa: %+v", err)
+func CheckSupportedTa(cfg *factory.Snapshot, tai models.Tai) bool {
+	if _, found := cfg.FindTa(tai); found {
+		return true
 	}
-	logger.Util.Warnf("no TA %s in NSSF configuration", e)
+	logger.Util.Warnf("no TA %s in NSSF configuration", factory.TaiString(tai))
 	return false
 }
 
+// Check whether the TAI is in the TAI range, TS 29.571 clause 5.4.4.32
+// A TAC range is either a range of TACs from start to end, or a regular expression matching the TAC.
+func CheckTaiInRange(tai models.Tai, taiRange models.TaiRange) bool {
+	return factory.NewTaiRangeMatcher(taiRange).Includes(tai)
+}
+
 // Check whether the given S-NSSAI is supported or not in PLMN
-func CheckSupportedSnssaiInPlmn(snssai models.Snssai, plmnId models.PlmnId) bool {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
+func CheckSupportedSnssaiInPlmn(cfg *factory.Snapshot, snssai models.Snssai, plmnId models.PlmnId) bool {
 	if CheckStandardSnssai(snssai) {
 		return true
 	}
 
-	supportedSnssaiList, found := factory.NssfConfig.Configuration.SupportedNssaiInPlmnList[plmnId]
+	supportedSnssaiList, found := cfg.SupportedNssaiInPlmn(plmnId)
 	if !found {
 		logger.Util.Warnf("no supported S-NSSAI list of PLMNID %+v in NSSF configuration", plmnId)
 		return false
@@ -76,11 +87,8 @@ func CheckSupportedSnssaiInPlmn(snssai models.Snssai, plmnId models.PlmnId) bool
 }
 
 // Check whether S-NSSAIs in NSSAI are supported or not in PLMN
-func CheckSupportedNssaiInPlmn(nssai []models.Snssai, plmnId models.PlmnId) bool {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-
-	supportedSnssaiList, found := factory.NssfConfig.Configuration.SupportedNssaiInPlmnList[plmnId]
+func CheckSupportedNssaiInPlmn(cfg *factory.Snapshot, nssai []models.Snssai, plmnId models.PlmnId) bool {
+	supportedSnssaiList, found := cfg.SupportedNssaiInPlmn(plmnId)
 	if !found {
 		logger.Util.Warnf("no supported S-NSSAI list of PLMNID %+v in NSSF configuration", plmnId)
 		return false
@@ -101,24 +109,21 @@ func CheckSupportedNssaiInPlmn(nssai []models.Snssai, plmnId models.PlmnId) bool
 }
 
 // Check whether S-NSSAI is supported or not at UE's current TA
-func CheckSupportedSnssaiInTa(snssai models.Snssai, tai models.Tai) bool {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
+// If the consumer indicates the UE's Access Type, only the S-NSSAIs of that Access Type are considered
+func CheckSupportedSnssaiInTa(cfg *factory.Snapshot, snssai models.Snssai, tai models.Tai, accessType *models.AccessType) bool {
 	targetSnssaiKey := factory.SnssaiToKey(snssai)
-	for _, taConfig := range factory.NssfConfig.Configuration.TaList {
-		if reflect.DeepEqual(*taConfig.Tai, tai) {
-			for _, supportedSnssai := range taConfig.SupportedSnssaiList {
-				if factory.SnssaiToKey(supportedSnssai) == targetSnssaiKey {
-					return true
-				}
+	accessTypeConfigs, _ := getTaAccessTypeConfigs(cfg, tai, accessType)
+	for _, accessTypeConfig := range accessTypeConfigs {
+		for _, supportedSnssai := range accessTypeConfig.SupportedSnssaiList {
+			if factory.SnssaiToKey(supportedSnssai) == targetSnssaiKey {
+				return true
 			}
-			return false
 		}
 	}
 	return false
 
 	// // Check supported S-NSSAI in AmfList instead of TaList
-	// for _, amfConfig := range factory.NssfConfig.Configuration.AmfList {
+	// for _, amfConfig := range cfg.AmfList() {
 	//     if checkSupportedNssaiAvailabilityData(snssai, tai, amfConfig.SupportedNssaiAvailabilityData) == true {
 	//         return true
 	//     }
@@ -127,42 +132,63 @@ func CheckSupportedSnssaiInTa(snssai models.Snssai, tai models.Tai) bool {
 }
 
 // Check whether S-NSSAI is in SupportedNssaiAvailabilityData under the given TAI
+// The entry of the TAI, or of its `taiList`, takes precedence over an entry with a TAI range including the TAI
 func CheckSupportedNssaiAvailabilityData(
 	snssai models.Snssai, tai models.Tai, s []models.SupportedNssaiAvailabilityData,
 ) bool {
-	for _, supportedNssaiAvailabilityData := range s {
-		if reflect.DeepEqual(supportedNssaiAvailabilityData.Tai, tai) &&
-			CheckSnssaiInNssai(snssai, supportedNssaiAvailabilityData.SupportedSnssaiList) {
-			return true
+	supportedNssaiAvailabilityData := findNssaiAvailabilityDataOfTai(tai, s)
+	return supportedNssaiAvailabilityData != nil &&
+		CheckSnssaiInNssai(snssai, supportedNssaiAvailabilityData.SupportedSnssaiList)
+}
+
+// findNssaiAvailabilityDataOfTai returns the entry of SupportedNssaiAvailabilityData applying to the TAI, if any
+func findNssaiAvailabilityDataOfTai(
+	tai models.Tai, s []models.SupportedNssaiAvailabilityData,
+) *models.SupportedNssaiAvailabilityData {
+	var rangeMatch *models.SupportedNssaiAvailabilityData
+	for i, supportedNssaiAvailabilityData := range s {
+		if checkSameTai(supportedNssaiAvailabilityData.Tai, tai) ||
+			checkTaiInList(tai, supportedNssaiAvailabilityData.TaiList) {
+			return &s[i]
+		}
+		if rangeMatch != nil {
+			continue
+		}
+		for _, taiRange := range supportedNssaiAvailabilityData.TaiRangeList {
+			if CheckTaiInRange(tai, taiRange) {
+				rangeMatch = &s[i]
+				break
+			}
 		}
 	}
-	return false
+	return rangeMatch
 }
 
 // Check whether S-NSSAI is supported or not by the AMF at UE's current TA
-func CheckSupportedSnssaiInAmfTa(snssai models.Snssai, nfId string, tai models.Tai) bool {
-	// Uncomment following lines if supported S-NSSAI lists of AMF Sets are independent of those of AMFs
-	// for _, amfSetConfig := range factory.NssfConfig.Configuration.AmfSetList {
-	//     if amfSetConfig.AmfList != nil && len(amfSetConfig.AmfList) != 0 && Contain(nfId, amfSetConfig.AmfList) {
-	//         return checkSupportedNssaiAvailabilityData(snssai, tai, amfSetConfig.SupportedNssaiAvailabilityData)
-	//     }
-	// }
+// An AMF which has not provided its own NSSAI availability supports the NSSAI availability of its AMF Sets.
+func CheckSupportedSnssaiInAmfTa(cfg *factory.Snapshot, snssai models.Snssai, nfId string, tai models.Tai) bool {
+	if amfConfig, found := cfg.Amf(nfId); found {
+		return CheckSupportedNssaiAvailabilityData(snssai, tai, amfConfig.SupportedNssaiAvailabilityData)
+	}
 
-	for _, amfConfig := range factory.NssfConfig.Configuration.AmfList {
-		if amfConfig.NfId == nfId {
-			return CheckSupportedNssaiAvailabilityData(snssai, tai, amfConfig.SupportedNssaiAvailabilityData)
+	amfSetIds := cfg.AmfSetIdsOfAmf(nfId)
+	for _, amfSetId := range amfSetIds {
+		if amfSetConfig, found := cfg.AmfSet(amfSetId); found &&
+			CheckSupportedNssaiAvailabilityData(snssai, tai, amfSetConfig.SupportedNssaiAvailabilityData) {
+			return true
 		}
 	}
-
-	logger.Util.Warnf("no AMF %s in NSSF configuration", nfId)
+	if len(amfSetIds) == 0 {
+		logger.Util.Warnf("no AMF %s in NSSF configuration", nfId)
+	}
 	return false
 }
 
 // Check whether all S-NSSAIs in Allowed NSSAI is supported by the AMF at UE's current TA
-func CheckAllowedNssaiInAmfTa(allowedNssaiList []models.AllowedNssai, nfId string, tai models.Tai) bool {
+func CheckAllowedNssaiInAmfTa(cfg *factory.Snapshot, allowedNssaiList []models.AllowedNssai, nfId string, tai models.Tai) bool {
 	for _, allowedNssai := range allowedNssaiList {
 		for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
-			if CheckSupportedSnssaiInAmfTa(allowedSnssai.AllowedSnssai, nfId, tai) {
+			if CheckSupportedSnssaiInAmfTa(cfg, allowedSnssai.AllowedSnssai, nfId, tai) {
 				continue
 			} else {
 				return false
@@ -193,146 +219,432 @@ func CheckSnssaiInNssai(targetSnssai models.Snssai, nssai []models.Snssai) bool
 }
 
 // Get S-NSSAI mappings of the given Home PLMN ID from configuration
-func GetMappingOfPlmnFromConfig(homePlmnId models.PlmnId) []models.MappingOfSnssai {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	for _, mappingFromPlmn := range factory.NssfConfig.Configuration.MappingListFromPlmn {
-		if *mappingFromPlmn.HomePlmnId == homePlmnId {
-			return mappingFromPlmn.MappingOfSnssai
+func GetMappingOfPlmnFromConfig(cfg *factory.Snapshot, homePlmnId models.PlmnId) []models.MappingOfSnssai {
+	mappingOfSnssai, _ := cfg.MappingOfPlmn(homePlmnId)
+	return mappingOfSnssai
+}
+
+// Get NSI information list of the given S-NSSAI from configuration
+func GetNsiInformationListFromConfig(cfg *factory.Snapshot, snssai models.Snssai) []models.NsiInformation {
+	return cfg.NsiInformationList(snssai)
+}
+
+// Get NSAG information of the given S-NSSAIs from configuration
+// Only NSAGs valid in the given TA are returned, or all of them if no TAI is given. S-NSSAIs which
+// are not part of `nssai` are left out of an NSAG, and NSAGs are ordered by priority.
+func GetNsagInfosFromConfig(cfg *factory.Snapshot, tai *models.Tai, nssai []models.Snssai) []models.NsagInfo {
+	var nsagConfigs []factory.NsagConfig
+	for _, nsagConfig := range cfg.NsagList() {
+		if tai == nil || len(nsagConfig.TaiList) == 0 || checkTaiInList(*tai, nsagConfig.TaiList) {
+			nsagConfigs = append(nsagConfigs, nsagConfig)
+		}
+	}
+	sort.SliceStable(nsagConfigs, func(i, j int) bool {
+		if nsagConfigs[i].Priority == nil || nsagConfigs[j].Priority == nil {
+			return nsagConfigs[i].Priority != nil
 		}
+		return *nsagConfigs[i].Priority < *nsagConfigs[j].Priority
+	})
+
+	var nsagInfos []models.NsagInfo
+	for _, nsagConfig := range nsagConfigs {
+		var snssaiList []models.Snssai
+		for _, snssai := range nsagConfig.SnssaiList {
+			if CheckSnssaiInNssai(snssai, nssai) {
+				snssaiList = append(snssaiList, snssai)
+			}
+		}
+		if len(snssaiList) == 0 {
+			continue
+		}
+		nsagInfo := models.NewNsagInfo([]int32{nsagConfig.NsagId}, snssaiList)
+		if len(nsagConfig.TaiList) != 0 {
+			nsagInfo.SetTaiList(append([]models.Tai(nil), nsagConfig.TaiList...))
+		}
+		nsagInfos = append(nsagInfos, *nsagInfo)
 	}
-	return nil
+	return nsagInfos
 }
 
-// Get NSI information list of the given S-NSSAI from configuration
-func GetNsiInformationListFromConfig(snssai models.Snssai) []models.NsiInformation {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	targetKey := factory.SnssaiToKey(snssai)
-	for _, nsiConfig := range factory.NssfConfig.Configuration.NsiList {
-		if factory.SnssaiToKey(*nsiConfig.Snssai) == targetKey {
-			return nsiConfig.NsiInformationList
+func checkTaiInList(tai models.Tai, taiList []models.Tai) bool {
+	for _, item := range taiList {
+		if checkSameTai(item, tai) {
+			return true
 		}
 	}
-	return nil
+	return false
+}
+
+func checkSameTai(a, b models.Tai) bool {
+	return factory.TaiToKey(a) == factory.TaiToKey(b)
 }
 
-// Get Access Type of the given TAI from configuraion
-func GetAccessTypeFromConfig(tai models.Tai) models.AccessType {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	for _, taConfig := range factory.NssfConfig.Configuration.TaList {
-		if reflect.DeepEqual(*taConfig.Tai, tai) {
-			return *taConfig.AccessType
+// Get the S-NSSAIs of the given TAI per Access Type from configuration
+// If the Access Type is indicated, only its S-NSSAIs are returned, unless it does not serve the TA.
+func getTaAccessTypeConfigs(cfg *factory.Snapshot, tai models.Tai, accessType *models.AccessType) ([]factory.TaAccessTypeConfig, bool) {
+	if taConfig, found := cfg.FindTa(tai); found {
+		accessTypeConfigs := taConfig.AccessTypeConfigs()
+		if accessType == nil {
+			return accessTypeConfigs, true
 		}
+		for _, accessTypeConfig := range accessTypeConfigs {
+			if accessTypeConfig.AccessType == *accessType {
+				return []factory.TaAccessTypeConfig{accessTypeConfig}, true
+			}
+		}
+		logger.Util.Warnf("TA %s is not served by %s in NSSF configuration, using all of its access types",
+			factory.TaiString(tai), *accessType)
+		return accessTypeConfigs, true
 	}
-	e, err := json.Marshal(tai)
-	if err != nil {
-		logger.Util.Errorf("marshal error in GetAccessTypeFromConfig: %+v", err)
+	logger.Util.Warnf("no TA %s in NSSF configuration", factory.TaiString(tai))
+	return nil, false
+}
+
+// Get Access Types of the Allowed NSSAI in which the S-NSSAI is allowed from configuration
+// If the TAI is provided, it is the Access Types serving the TA with the S-NSSAI, restricted to the Access Type
+// the consumer indicates. Otherwise, the indicated Access Type is used or, if the UE's Access Type could not be
+// identified, the `accessTypeWithoutTai` policy applies, which defaults to 3GPP Access.
+func GetAccessTypesFromConfig(cfg *factory.Snapshot, snssai models.Snssai, tai *models.Tai,
+	accessType *models.AccessType,
+) []models.AccessType {
+	if tai != nil {
+		accessTypeConfigs, found := getTaAccessTypeConfigs(cfg, *tai, accessType)
+		if !found {
+			return []models.AccessType{models.ACCESSTYPE__3_GPP_ACCESS}
+		}
+		var accessTypes, servingAccessTypes []models.AccessType
+		for _, accessTypeConfig := range accessTypeConfigs {
+			servingAccessTypes = append(servingAccessTypes, accessTypeConfig.AccessType)
+			if CheckSnssaiInNssai(snssai, accessTypeConfig.SupportedSnssaiList) {
+				accessTypes = append(accessTypes, accessTypeConfig.AccessType)
+			}
+		}
+		if len(accessTypes) == 0 {
+			return servingAccessTypes
+		}
+		return accessTypes
+	}
+	if accessType != nil {
+		return []models.AccessType{*accessType}
+	}
+
+	switch cfg.AccessTypeWithoutTai() {
+	case factory.AccessTypeWithoutTaiNon3gpp:
+		return []models.AccessType{models.ACCESSTYPE_NON_3_GPP_ACCESS}
+	case factory.AccessTypeWithoutTaiAll:
+		return []models.AccessType{models.ACCESSTYPE__3_GPP_ACCESS, models.ACCESSTYPE_NON_3_GPP_ACCESS}
+	default:
+		return []models.AccessType{models.ACCESSTYPE__3_GPP_ACCESS}
 	}
-	logger.Util.Warnf("no TA %s in NSSF configuration", e)
-	return models.ACCESSTYPE__3_GPP_ACCESS
 }
 
-// Get restricted S-NSSAI list of the given TAI from configuration
-func GetRestrictedSnssaiListFromConfig(tai models.Tai) []models.RestrictedSnssai {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	return getRestrictedSnssaiListFromConfigLocked(tai)
+// Get restricted S-NSSAI list of the given TAI from configuration, of all Access Types serving the TA
+func GetRestrictedSnssaiListFromConfig(cfg *factory.Snapshot, tai models.Tai) []models.RestrictedSnssai {
+	return getRestrictedSnssaiListOfAccessType(cfg, tai, nil)
 }
 
-func getRestrictedSnssaiListFromConfigLocked(tai models.Tai) []models.RestrictedSnssai {
-	for _, taConfig := range factory.NssfConfig.Configuration.TaList {
-		if reflect.DeepEqual(*taConfig.Tai, tai) {
-			if len(taConfig.RestrictedSnssaiList) != 0 {
-				return taConfig.RestrictedSnssaiList
-			} else {
-				return nil
+func getRestrictedSnssaiListOfAccessType(cfg *factory.Snapshot, tai models.Tai,
+	accessType *models.AccessType,
+) []models.RestrictedSnssai {
+	accessTypeConfigs, _ := getTaAccessTypeConfigs(cfg, tai, accessType)
+	var restrictedSnssaiList []models.RestrictedSnssai
+	for _, accessTypeConfig := range accessTypeConfigs {
+		restrictedSnssaiList = append(restrictedSnssaiList, accessTypeConfig.RestrictedSnssaiList...)
+	}
+	return restrictedSnssaiList
+}
+
+// Get supported S-NSSAI list of the TA, of all Access Types serving the TA
+func getSupportedSnssaiListOfTa(taConfig factory.TaConfig) []models.Snssai {
+	var supportedSnssaiList []models.Snssai
+	seen := make(map[factory.SnssaiKey]struct{})
+	for _, accessTypeConfig := range taConfig.AccessTypeConfigs() {
+		for _, snssai := range accessTypeConfig.SupportedSnssaiList {
+			if _, found := seen[factory.SnssaiToKey(snssai)]; !found {
+				seen[factory.SnssaiToKey(snssai)] = struct{}{}
+				supportedSnssaiList = append(supportedSnssaiList, snssai)
 			}
 		}
 	}
-	e, err := json.Marshal(tai)
-	if err != nil {
-		logger.Util.Errorf("marshal error in GetRestrictedSnssaiListFromConfig: %+v", err)
+	return supportedSnssaiList
+}
+
+// Check whether S-NSSAI is restricted at UE's current TA for a UE of the given Home PLMN
+// An entry of the restricted S-NSSAI list applies to UEs of its Home PLMNs, or to all roaming UEs
+// if `roamingRestriction` is set. UEs in their Home PLMN are never restricted.
+func CheckRestrictedSnssaiInTa(cfg *factory.Snapshot, snssai models.Snssai, tai models.Tai,
+	accessType *models.AccessType, homePlmnId models.PlmnId,
+) bool {
+	if homePlmnId == tai.PlmnId {
+		return false
+	}
+	for _, restrictedSnssai := range getRestrictedSnssaiListOfAccessType(cfg, tai, accessType) {
+		if !checkRestrictionAppliesToHplmn(restrictedSnssai, homePlmnId) {
+			continue
+		}
+		if CheckSnssaiInNssai(snssai, restrictedSnssai.SNssaiList) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRestrictionAppliesToHplmn(restrictedSnssai models.RestrictedSnssai, homePlmnId models.PlmnId) bool {
+	if restrictedSnssai.GetRoamingRestriction() || restrictedSnssai.HomePlmnId == homePlmnId {
+		return true
+	}
+	for _, plmnId := range restrictedSnssai.HomePlmnIdList {
+		if plmnId == homePlmnId {
+			return true
+		}
 	}
-	logger.Util.Warnf("no TA %s in NSSF configuration", e)
-	return nil
+	return false
 }
 
 // Get authorized NSSAI availability data of the given NF ID and TAI from configuration
-func AuthorizeOfAmfTaFromConfig(nfId string, tai models.Tai) (models.AuthorizedNssaiAvailabilityData, error) {
+func AuthorizeOfAmfTaFromConfig(cfg *factory.Snapshot, nfId string, tai models.Tai) (models.AuthorizedNssaiAvailabilityData, error) {
 	var authorizedNssaiAvailabilityData models.AuthorizedNssaiAvailabilityData
 	authorizedNssaiAvailabilityData.Tai = tai
 
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	for _, amfConfig := range factory.NssfConfig.Configuration.AmfList {
-		if amfConfig.NfId == nfId {
-			for _, supportedNssaiAvailabilityData := range amfConfig.SupportedNssaiAvailabilityData {
-				if reflect.DeepEqual(supportedNssaiAvailabilityData.Tai, tai) {
-					authorizedNssaiAvailabilityData.SupportedSnssaiList = supportedNssaiAvailabilityData.SupportedSnssaiList
-					authorizedNssaiAvailabilityData.RestrictedSnssaiList = getRestrictedSnssaiListFromConfigLocked(tai)
-
-					// TODO: Sort the returned slice
-					return authorizedNssaiAvailabilityData, nil
-				}
-			}
-			e, err1 := json.Marshal(tai)
-			if err1 != nil {
-				logger.Util.Errorf("marshal error in AuthorizeOfAmfTaFromConfig: %+v", err1)
-			}
-			err := fmt.Errorf("no supported S-NSSAI list by AMF %s under TAI %s in NSSF configuration", nfId, e)
-			return authorizedNssaiAvailabilityData, err
+	amfConfig, found := cfg.Amf(nfId)
+	if !found {
+		err := fmt.Errorf("no AMF configuration of %s", nfId)
+		return authorizedNssaiAvailabilityData, err
+	}
+	for _, supportedNssaiAvailabilityData := range amfConfig.SupportedNssaiAvailabilityData {
+		if checkSameTai(supportedNssaiAvailabilityData.Tai, tai) {
+			authorizedNssaiAvailabilityData.SupportedSnssaiList = supportedNssaiAvailabilityData.SupportedSnssaiList
+			authorizedNssaiAvailabilityData.RestrictedSnssaiList = GetRestrictedSnssaiListFromConfig(cfg, tai)
+
+			// TODO: Sort the returned slice
+			return authorizedNssaiAvailabilityData, nil
 		}
 	}
-	err := fmt.Errorf("no AMF configuration of %s", nfId)
+	err := fmt.Errorf("no supported S-NSSAI list by AMF %s under TAI %s in NSSF configuration", nfId, factory.TaiString(tai))
 	return authorizedNssaiAvailabilityData, err
 }
 
 // Get all authorized NSSAI availability data of the given NF ID from configuration
-func AuthorizeOfAmfFromConfig(nfId string) ([]models.AuthorizedNssaiAvailabilityData, error) {
+func AuthorizeOfAmfFromConfig(cfg *factory.Snapshot, nfId string) ([]models.AuthorizedNssaiAvailabilityData, error) {
 	var authorizedNssaiAvailabilityDataList []models.AuthorizedNssaiAvailabilityData
 
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	for _, amfConfig := range factory.NssfConfig.Configuration.AmfList {
-		if amfConfig.NfId == nfId {
-			for _, supportedNssaiAvailabilityData := range amfConfig.SupportedNssaiAvailabilityData {
-				var authorizedNssaiAvailabilityData models.AuthorizedNssaiAvailabilityData
-				authorizedNssaiAvailabilityData.Tai = supportedNssaiAvailabilityData.Tai
-				authorizedNssaiAvailabilityData.SupportedSnssaiList = supportedNssaiAvailabilityData.SupportedSnssaiList
-				authorizedNssaiAvailabilityData.RestrictedSnssaiList = getRestrictedSnssaiListFromConfigLocked(authorizedNssaiAvailabilityData.Tai)
-
-				authorizedNssaiAvailabilityDataList = append(authorizedNssaiAvailabilityDataList, authorizedNssaiAvailabilityData)
+	amfConfig, found := cfg.Amf(nfId)
+	if !found {
+		err := fmt.Errorf("no AMF configuration of %s", nfId)
+		return authorizedNssaiAvailabilityDataList, err
+	}
+	for _, supportedNssaiAvailabilityData := range amfConfig.SupportedNssaiAvailabilityData {
+		var authorizedNssaiAvailabilityData models.AuthorizedNssaiAvailabilityData
+		authorizedNssaiAvailabilityData.Tai = supportedNssaiAvailabilityData.Tai
+		authorizedNssaiAvailabilityData.SupportedSnssaiList = supportedNssaiAvailabilityData.SupportedSnssaiList
+		authorizedNssaiAvailabilityData.RestrictedSnssaiList = GetRestrictedSnssaiListFromConfig(cfg, authorizedNssaiAvailabilityData.Tai)
+
+		authorizedNssaiAvailabilityDataList = append(authorizedNssaiAvailabilityDataList, authorizedNssaiAvailabilityData)
+	}
+	return authorizedNssaiAvailabilityDataList, nil
+}
+
+// Get the NSSAI availability of the TA provided by the AMFs and AMF Sets serving it
+// The supported S-NSSAIs are the ones which an available AMF, or an AMF Set, supports in the TA.
+func AuthorizeOfTaFromAmfs(cfg *factory.Snapshot, tai models.Tai) models.AuthorizedNssaiAvailabilityData {
+	var authorizedNssaiAvailabilityData models.AuthorizedNssaiAvailabilityData
+	authorizedNssaiAvailabilityData.Tai = tai
+	authorizedNssaiAvailabilityData.SupportedSnssaiList = []models.Snssai{}
+
+	addSupportedSnssais := func(s []models.SupportedNssaiAvailabilityData) {
+		supportedNssaiAvailabilityData := findNssaiAvailabilityDataOfTai(tai, s)
+		if supportedNssaiAvailabilityData == nil {
+			return
+		}
+		for _, snssai := range supportedNssaiAvailabilityData.SupportedSnssaiList {
+			if !CheckSnssaiInNssai(snssai, authorizedNssaiAvailabilityData.SupportedSnssaiList) {
+				authorizedNssaiAvailabilityData.SupportedSnssaiList = append(
+					authorizedNssaiAvailabilityData.SupportedSnssaiList, snssai)
 			}
-			return authorizedNssaiAvailabilityDataList, nil
 		}
 	}
-	err := fmt.Errorf("no AMF configuration of %s", nfId)
-	return authorizedNssaiAvailabilityDataList, err
+	for _, amfConfig := range cfg.AmfList() {
+		if !amfConfig.Suspended {
+			addSupportedSnssais(amfConfig.SupportedNssaiAvailabilityData)
+		}
+	}
+	for _, amfSetConfig := range cfg.AmfSetList() {
+		addSupportedSnssais(amfSetConfig.SupportedNssaiAvailabilityData)
+	}
+	authorizedNssaiAvailabilityData.RestrictedSnssaiList = GetRestrictedSnssaiListFromConfig(cfg, tai)
+	return authorizedNssaiAvailabilityData
 }
 
-// Get authorized NSSAI availability data of the given TAI list from configuration
-func AuthorizeOfTaListFromConfig(taiList []models.Tai) []models.AuthorizedNssaiAvailabilityData {
+// Get authorized NSSAI availability data of the given TAI list and TAI ranges from configuration
+// TAI ranges are resolved to the TAs known to the NSSF, i.e. the TAs configured in the TA list and the TAs
+// for which AMFs or AMF Sets provided NSSAI availability
+func AuthorizeOfTaListFromConfig(cfg *factory.Snapshot, taiList []models.Tai,
+	taiRangeList []models.TaiRange,
+) []models.AuthorizedNssaiAvailabilityData {
 	var authorizedNssaiAvailabilityDataList []models.AuthorizedNssaiAvailabilityData
 
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
-	for _, taConfig := range factory.NssfConfig.Configuration.TaList {
-		for _, tai := range taiList {
-			if reflect.DeepEqual(*taConfig.Tai, tai) {
-				var authorizedNssaiAvailabilityData models.AuthorizedNssaiAvailabilityData
-				authorizedNssaiAvailabilityData.Tai = tai
-				authorizedNssaiAvailabilityData.SupportedSnssaiList = taConfig.SupportedSnssaiList
-				authorizedNssaiAvailabilityData.RestrictedSnssaiList = getRestrictedSnssaiListFromConfigLocked(tai)
-
-				authorizedNssaiAvailabilityDataList = append(authorizedNssaiAvailabilityDataList, authorizedNssaiAvailabilityData)
+	matchers := make([]factory.TaiRangeMatcher, 0, len(taiRangeList))
+	for _, taiRange := range taiRangeList {
+		matchers = append(matchers, factory.NewTaiRangeMatcher(taiRange))
+	}
+	tais := append([]models.Tai(nil), taiList...)
+	for _, tai := range cfg.KnownTais() {
+		if checkTaiInList(tai, tais) {
+			continue
+		}
+		for _, matcher := range matchers {
+			if matcher.Includes(tai) {
+				tais = append(tais, tai)
+				break
 			}
 		}
 	}
+
+	for _, tai := range tais {
+		if taConfig, found := cfg.FindTa(tai); found {
+			var authorizedNssaiAvailabilityData models.AuthorizedNssaiAvailabilityData
+			authorizedNssaiAvailabilityData.Tai = tai
+			authorizedNssaiAvailabilityData.SupportedSnssaiList = getSupportedSnssaiListOfTa(taConfig)
+			authorizedNssaiAvailabilityData.RestrictedSnssaiList = GetRestrictedSnssaiListFromConfig(cfg, tai)
+
+			authorizedNssaiAvailabilityDataList = append(authorizedNssaiAvailabilityDataList, authorizedNssaiAvailabilityData)
+		}
+	}
 	return authorizedNssaiAvailabilityDataList
 }
 
+// Authorize the NSSAI availability provided by the AMF, per TA and per S-NSSAI
+// An S-NSSAI is authorized in the TAs of a NSSAI availability data if it is supported in their PLMNs and,
+// when an NSSAI availability policy is configured, if a rule applies to the AMF, to each of the TAs and to
+// the S-NSSAI. A TAI range is only covered by a rule for all TAs or a rule with the same TAI range.
+// It returns the NSSAI availability data with the authorized S-NSSAIs only, and the rejected S-NSSAIs.
+func AuthorizeNssaiAvailabilityData(cfg *factory.Snapshot, nfId string, s []models.SupportedNssaiAvailabilityData) (
+	[]models.SupportedNssaiAvailabilityData, []RejectedSnssaiAvailability,
+) {
+	var rules []factory.NssaiAvailabilityRule
+	for _, rule := range cfg.NssaiAvailabilityPolicy() {
+		if checkRuleAppliesToAmf(rule, nfId, cfg.AmfSetIdsOfAmf(nfId)) {
+			rules = append(rules, rule)
+		}
+	}
+	policyConfigured := len(cfg.NssaiAvailabilityPolicy()) != 0
+
+	authorized := make([]models.SupportedNssaiAvailabilityData, 0, len(s))
+	var rejected []RejectedSnssaiAvailability
+	for _, supportedNssaiAvailabilityData := range s {
+		authorizedData := supportedNssaiAvailabilityData
+		authorizedData.SupportedSnssaiList = make([]models.Snssai, 0, len(supportedNssaiAvailabilityData.SupportedSnssaiList))
+		for _, snssai := range supportedNssaiAvailabilityData.SupportedSnssaiList {
+			cause := ""
+			if !checkSnssaiSupportedInPlmnsOfData(cfg, snssai, supportedNssaiAvailabilityData) {
+				cause = REJECTED_CAUSE_NOT_SUPPORTED_IN_PLMN
+			} else if policyConfigured && !checkRulesAuthorizeSnssai(rules, snssai, supportedNssaiAvailabilityData) {
+				cause = REJECTED_CAUSE_NOT_AUTHORIZED
+			}
+			if cause != "" {
+				rejected = append(rejected, RejectedSnssaiAvailability{
+					Snssai: snssai,
+					Tai:    supportedNssaiAvailabilityData.Tai,
+					Cause:  cause,
+				})
+				continue
+			}
+			authorizedData.SupportedSnssaiList = append(authorizedData.SupportedSnssaiList, snssai)
+		}
+		authorized = append(authorized, authorizedData)
+	}
+	return authorized, rejected
+}
+
+func checkSnssaiSupportedInPlmnsOfData(cfg *factory.Snapshot, snssai models.Snssai,
+	supportedNssaiAvailabilityData models.SupportedNssaiAvailabilityData,
+) bool {
+	if !CheckSupportedSnssaiInPlmn(cfg, snssai, supportedNssaiAvailabilityData.Tai.PlmnId) {
+		return false
+	}
+	for _, tai := range supportedNssaiAvailabilityData.TaiList {
+		if !CheckSupportedSnssaiInPlmn(cfg, snssai, tai.PlmnId) {
+			return false
+		}
+	}
+	for _, taiRange := range supportedNssaiAvailabilityData.TaiRangeList {
+		if !CheckSupportedSnssaiInPlmn(cfg, snssai, taiRange.PlmnId) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkRuleAppliesToAmf(rule factory.NssaiAvailabilityRule, nfId string, amfSetIds []string) bool {
+	if len(rule.AmfIdList) == 0 && len(rule.AmfSetIdList) == 0 {
+		return true
+	}
+	if slices.Contains(rule.AmfIdList, nfId) {
+		return true
+	}
+	for _, amfSetId := range amfSetIds {
+		if slices.Contains(rule.AmfSetIdList, amfSetId) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRulesAuthorizeSnssai(rules []factory.NssaiAvailabilityRule, snssai models.Snssai,
+	supportedNssaiAvailabilityData models.SupportedNssaiAvailabilityData,
+) bool {
+	var applicableRules []factory.NssaiAvailabilityRule
+	for _, rule := range rules {
+		if len(rule.SnssaiList) == 0 || CheckSnssaiInNssai(snssai, rule.SnssaiList) {
+			applicableRules = append(applicableRules, rule)
+		}
+	}
+	tais := append([]models.Tai{supportedNssaiAvailabilityData.Tai}, supportedNssaiAvailabilityData.TaiList...)
+	for _, tai := range tais {
+		if !slices.ContainsFunc(applicableRules, func(rule factory.NssaiAvailabilityRule) bool {
+			return checkRuleAppliesToTai(rule, tai)
+		}) {
+			return false
+		}
+	}
+	for _, taiRange := range supportedNssaiAvailabilityData.TaiRangeList {
+		if !slices.ContainsFunc(applicableRules, func(rule factory.NssaiAvailabilityRule) bool {
+			return (len(rule.TaiList) == 0 && len(rule.TaiRangeList) == 0) ||
+				slices.ContainsFunc(rule.TaiRangeList, func(item models.TaiRange) bool { return checkSameTaiRange(item, taiRange) })
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkRuleAppliesToTai(rule factory.NssaiAvailabilityRule, tai models.Tai) bool {
+	if len(rule.TaiList) == 0 && len(rule.TaiRangeList) == 0 {
+		return true
+	}
+	if checkTaiInList(tai, rule.TaiList) {
+		return true
+	}
+	for _, taiRange := range rule.TaiRangeList {
+		if CheckTaiInRange(tai, taiRange) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkSameTaiRange(a, b models.TaiRange) bool {
+	return a.PlmnId == b.PlmnId && a.GetNid() == b.GetNid() && slices.Equal(tacRangeStrings(a), tacRangeStrings(b))
+}
+
+func tacRangeStrings(taiRange models.TaiRange) []string {
+	tacRanges := make([]string, 0, len(taiRange.TacRangeList))
+	for _, tacRange := range taiRange.TacRangeList {
+		tacRanges = append(tacRanges, factory.TacRangeString(tacRange))
+	}
+	return tacRanges
+}
+
 // Find target S-NSSAI mapping with serving S-NSSAIs from mapping of S-NSSAI(s)
 func FindMappingWithServingSnssai(
 	snssai models.Snssai, mappings []models.MappingOfSnssai,
@@ -355,20 +667,24 @@ func FindMappingWithHomeSnssai(snssai models.Snssai, mappings []models.MappingOf
 	return models.MappingOfSnssai{}, false
 }
 
-// Add Allowed S-NSSAI to Authorized Network Slice Info
-func AddAllowedSnssai(allowedSnssai models.AllowedSnssai, accessType models.AccessType,
+// Add Allowed S-NSSAI to Authorized Network Slice Info, in the Allowed NSSAI of each of the Access Types
+// The number of S-NSSAIs is not limited here, see TrimAllowedNssai
+func AddAllowedSnssai(allowedSnssai models.AllowedSnssai, accessTypes []models.AccessType,
+	authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo,
+) {
+	for _, accessType := range accessTypes {
+		addAllowedSnssaiOfAccessType(allowedSnssai, accessType, authorizedNetworkSliceInfo)
+	}
+}
+
+func addAllowedSnssaiOfAccessType(allowedSnssai models.AllowedSnssai, accessType models.AccessType,
 	authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo,
 ) {
 	hitAllowedNssai := false
-	allowedNssaiNum := 8
 	for i := range authorizedNetworkSliceInfo.AllowedNssaiList {
 		if authorizedNetworkSliceInfo.AllowedNssaiList[i].AccessType == accessType {
 			hitAllowedNssai = true
-			if len(authorizedNetworkSliceInfo.AllowedNssaiList[i].AllowedSnssaiList) == allowedNssaiNum {
-				logger.Util.Infoln("unable to add a new Allowed S-NSSAI since already eight S-NSSAIs in Allowed NSSAI")
-			} else {
-				authorizedNetworkSliceInfo.AllowedNssaiList[i].AllowedSnssaiList = append(authorizedNetworkSliceInfo.AllowedNssaiList[i].AllowedSnssaiList, allowedSnssai)
-			}
+			authorizedNetworkSliceInfo.AllowedNssaiList[i].AllowedSnssaiList = append(authorizedNetworkSliceInfo.AllowedNssaiList[i].AllowedSnssaiList, allowedSnssai)
 			break
 		}
 	}
@@ -382,10 +698,55 @@ func AddAllowedSnssai(allowedSnssai models.AllowedSnssai, accessType models.Acce
 	}
 }
 
+// Trim the Allowed NSSAI of each Access Type to the configured limit, keeping the S-NSSAIs of highest priority
+// S-NSSAIs without configured priority come after the others, in the order in which they were allowed.
+// It returns the trimmed S-NSSAIs which are not allowed in any other Access Type either.
+func TrimAllowedNssai(cfg *factory.Snapshot, authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo) []models.Snssai {
+	limit := cfg.AllowedNssaiLimit()
+	rank := func(snssai models.Snssai) int64 {
+		if priority, found := cfg.SnssaiPriority(snssai); found {
+			return int64(priority)
+		}
+		return math.MaxInt64
+	}
+
+	var trimmed []models.Snssai
+	for i := range authorizedNetworkSliceInfo.AllowedNssaiList {
+		allowedSnssaiList := authorizedNetworkSliceInfo.AllowedNssaiList[i].AllowedSnssaiList
+		if len(allowedSnssaiList) <= limit {
+			continue
+		}
+		sort.SliceStable(allowedSnssaiList, func(a, b int) bool {
+			return rank(allowedSnssaiList[a].AllowedSnssai) < rank(allowedSnssaiList[b].AllowedSnssai)
+		})
+		for _, allowedSnssai := range allowedSnssaiList[limit:] {
+			trimmed = append(trimmed, allowedSnssai.AllowedSnssai)
+		}
+		authorizedNetworkSliceInfo.AllowedNssaiList[i].AllowedSnssaiList = allowedSnssaiList[:limit]
+	}
+
+	var rejected []models.Snssai
+	for _, snssai := range trimmed {
+		if !CheckSnssaiInNssai(snssai, rejected) && !checkSnssaiInAllowedNssaiList(snssai, authorizedNetworkSliceInfo.AllowedNssaiList) {
+			rejected = append(rejected, snssai)
+		}
+	}
+	return rejected
+}
+
+func checkSnssaiInAllowedNssaiList(snssai models.Snssai, allowedNssaiList []models.AllowedNssai) bool {
+	for _, allowedNssai := range allowedNssaiList {
+		for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
+			if factory.SnssaiToKey(allowedSnssai.AllowedSnssai) == factory.SnssaiToKey(snssai) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Add AMF information to Authorized Network Slice Info
-func AddAmfInformation(tai models.Tai, authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo) {
-	factory.ConfigLock.RLock()
-	defer factory.ConfigLock.RUnlock()
+func AddAmfInformation(cfg *factory.Snapshot, tai models.Tai, authorizedNetworkSliceInfo *models.AuthorizedNetworkSliceInfo) {
 	if len(authorizedNetworkSliceInfo.AllowedNssaiList) == 0 {
 		return
 	}
@@ -393,10 +754,10 @@ func AddAmfInformation(tai models.Tai, authorizedNetworkSliceInfo *models.Author
 	// Check if any AMF can serve the UE
 	// That is, whether NSSAI of all Allowed S-NSSAIs is a subset of NSSAI supported by AMF
 
-	// Find AMF Set that could serve UE from AMF Set list in configuration
+	// Find AMF Set that could serve UE from AMF Set list, with the NSSAI availability aggregated from its members
 	// Simply use the first applicable AMF set
 	// TODO: Policies of AMF selection (e.g. load balance between AMF instances)
-	for _, amfSetConfig := range factory.NssfConfig.Configuration.AmfSetList {
+	for _, amfSetConfig := range cfg.AmfSetList() {
 		hitAllowedNssai := true
 		for _, allowedNssai := range authorizedNetworkSliceInfo.AllowedNssaiList {
 			for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
@@ -419,12 +780,22 @@ func AddAmfInformation(tai models.Tai, authorizedNetworkSliceInfo *models.Author
 			// Add AMF Set to Authorized Network Slice Info
 			if len(amfSetConfig.AmfList) != 0 {
 				// List of candidate AMF(s) provided in configuration
-				authorizedNetworkSliceInfo.CandidateAmfList = append(authorizedNetworkSliceInfo.CandidateAmfList, amfSetConfig.AmfList...)
+				candidateAmfList := amfSetConfig.AmfList
+				if cfg.AmfSetReported(amfSetConfig.AmfSetId) {
+					// Only the members which provided their NSSAI availability and are not suspended are candidates
+					candidateAmfList = availableAmfs(cfg, amfSetConfig.AmfList)
+					if len(candidateAmfList) == 0 {
+						continue
+					}
+				}
+				authorizedNetworkSliceInfo.CandidateAmfList = append(authorizedNetworkSliceInfo.CandidateAmfList, candidateAmfList...)
 			} else {
 				// TODO: Possibly querying the NRF
 				authorizedNetworkSliceInfo.TargetAmfSet = openapi.PtrString(amfSetConfig.AmfSetId)
 				// The API URI of the NRF may be included if target AMF Set is included
-				authorizedNetworkSliceInfo.NrfAmfSet = openapi.PtrString(amfSetConfig.NrfAmfSet)
+				if amfSetConfig.NrfAmfSet != "" {
+					authorizedNetworkSliceInfo.NrfAmfSet = openapi.PtrString(amfSetConfig.NrfAmfSet)
+				}
 			}
 			return
 		}
@@ -433,7 +804,10 @@ func AddAmfInformation(tai models.Tai, authorizedNetworkSliceInfo *models.Author
 	// No AMF Set in configuration can serve the UE
 	// Find all candidate AMFs that could serve UE from AMF list in configuration
 	hitAmf := false
-	for _, amfConfig := range factory.NssfConfig.Configuration.AmfList {
+	for _, amfConfig := range cfg.AmfList() {
+		if amfConfig.Suspended {
+			continue
+		}
 		hitAllowedNssai := true
 		for _, allowedNssai := range authorizedNetworkSliceInfo.AllowedNssaiList {
 			for _, allowedSnssai := range allowedNssai.AllowedSnssaiList {
@@ -463,3 +837,14 @@ func AddAmfInformation(tai models.Tai, authorizedNetworkSliceInfo *models.Author
 		logger.Util.Warnln("no candidate AMF or AMF Set can serve the UE")
 	}
 }
+
+// availableAmfs returns the AMFs which provided their NSSAI availability and are not suspended
+func availableAmfs(cfg *factory.Snapshot, nfIds []string) []string {
+	var available []string
+	for _, nfId := range nfIds {
+		if amfConfig, found := cfg.Amf(nfId); found && amfConfig.Version != 0 && !amfConfig.Suspended {
+			available = append(available, nfId)
+		}
+	}
+	return available
+}
@@ -6,6 +6,8 @@
 package util
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/omec-project/nssf/factory"
@@ -13,6 +15,11 @@ import (
 	"github.com/omec-project/openapi/v2/models"
 )
 
+// testSnapshot returns a snapshot of the configuration set up by the test
+func testSnapshot() *factory.Snapshot {
+	return factory.NewSnapshot(factory.NssfConfig.Configuration)
+}
+
 func TestCheckSupportedSnssaiInPlmn(t *testing.T) {
 	plmn1 := models.PlmnId{Mcc: "001", Mnc: "01"}
 	plmn2 := models.PlmnId{Mcc: "002", Mnc: "02"}
@@ -76,7 +83,7 @@ func TestCheckSupportedSnssaiInPlmn(t *testing.T) {
 				},
 			}
 
-			result := CheckSupportedSnssaiInPlmn(tc.snssai, tc.plmnId)
+			result := CheckSupportedSnssaiInPlmn(testSnapshot(), tc.snssai, tc.plmnId)
 			if result != tc.expected {
 				t.Errorf("Expected CheckSupportedSnssaiInPlmn to be `%v`, got `%v`", tc.expected, result)
 			}
@@ -98,7 +105,7 @@ func TestCheckSupportedSnssaiInPlmn_EmptyConfig(t *testing.T) {
 	plmn := models.PlmnId{Mcc: "001", Mnc: "01"}
 	snssai := models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
 
-	result := CheckSupportedSnssaiInPlmn(snssai, plmn)
+	result := CheckSupportedSnssaiInPlmn(testSnapshot(), snssai, plmn)
 	if result != false {
 		t.Errorf("Expected CheckSupportedSnssaiInPlmn to be false, got `%v`", result)
 	}
@@ -119,7 +126,7 @@ func TestCheckSupportedSnssaiInPlmn_EmptySupportedNssaiButExistingPlmn(t *testin
 		},
 	}
 
-	result := CheckSupportedSnssaiInPlmn(snssai, plmn)
+	result := CheckSupportedSnssaiInPlmn(testSnapshot(), snssai, plmn)
 	if result != false {
 		t.Errorf("Expected CheckSupportedSnssaiInPlmn to be false, got `%v`", result)
 	}
@@ -149,7 +156,7 @@ func TestCheckSupportedSnssaiInTa_UsesValueEqualityForSd(t *testing.T) {
 		},
 	}
 
-	if !CheckSupportedSnssaiInTa(requestSnssai, tai) {
+	if !CheckSupportedSnssaiInTa(testSnapshot(), requestSnssai, tai, nil) {
 		t.Fatal("expected S-NSSAI with equal SST/SD values to be supported in TA")
 	}
 }
@@ -215,7 +222,7 @@ func TestCheckSupportedNssaiInPlmn(t *testing.T) {
 				},
 			}
 
-			result := CheckSupportedNssaiInPlmn(tc.nssai, tc.plmnId)
+			result := CheckSupportedNssaiInPlmn(testSnapshot(), tc.nssai, tc.plmnId)
 			if result != tc.expected {
 				t.Errorf("Expected CheckSupportedNssaiInPlmn to be `%v`, got `%v`", tc.expected, result)
 			}
@@ -237,7 +244,7 @@ func TestCheckSupportedNssaiInPlmn_EmptyConfig(t *testing.T) {
 	plmn := models.PlmnId{Mcc: "001", Mnc: "01"}
 	snssai := []models.Snssai{{Sst: 1, Sd: openapi.PtrString("000001")}}
 
-	result := CheckSupportedNssaiInPlmn(snssai, plmn)
+	result := CheckSupportedNssaiInPlmn(testSnapshot(), snssai, plmn)
 	if result != false {
 		t.Errorf("Expected CheckSupportedNssaiInPlmn to be false, got `%v`", result)
 	}
@@ -258,7 +265,7 @@ func TestCheckSupportedNssaiInPlmn_EmptySupportedNssaiButExistingPlmn(t *testing
 		},
 	}
 
-	result := CheckSupportedNssaiInPlmn(snssai, plmn)
+	result := CheckSupportedNssaiInPlmn(testSnapshot(), snssai, plmn)
 	if result != false {
 		t.Errorf("Expected CheckSupportedNssaiInPlmn to be false, got `%v`", result)
 	}
@@ -283,7 +290,7 @@ func TestGetNsiInformationListFromConfig_UsesValueEqualityForSd(t *testing.T) {
 		},
 	}
 
-	result := GetNsiInformationListFromConfig(requestSnssai)
+	result := GetNsiInformationListFromConfig(testSnapshot(), requestSnssai)
 	if len(result) != len(expected) {
 		t.Fatalf("expected %d NSI info entries, got %d", len(expected), len(result))
 	}
@@ -291,3 +298,603 @@ func TestGetNsiInformationListFromConfig_UsesValueEqualityForSd(t *testing.T) {
 		t.Fatalf("expected NRF ID %q, got %q", expected[0].GetNrfId(), result[0].GetNrfId())
 	}
 }
+
+func TestGetNsagInfosFromConfig(t *testing.T) {
+	originalFactoryConfig := factory.NssfConfig
+	defer func() {
+		factory.NssfConfig = originalFactoryConfig
+	}()
+
+	plmnId := models.PlmnId{Mcc: "001", Mnc: "01"}
+	tai1 := models.Tai{PlmnId: plmnId, Tac: "000001"}
+	tai2 := models.Tai{PlmnId: plmnId, Tac: "000002"}
+	snssai1 := models.Snssai{Sst: 1, Sd: openapi.PtrString("010203")}
+	snssai2 := models.Snssai{Sst: 2}
+	snssai3 := models.Snssai{Sst: 3}
+
+	factory.NssfConfig = factory.Config{
+		Configuration: &factory.Configuration{
+			NsagList: []factory.NsagConfig{
+				{NsagId: 1, SnssaiList: []models.Snssai{snssai1, snssai3}},
+				{NsagId: 2, SnssaiList: []models.Snssai{snssai2}, Priority: openapi.PtrInt32(1), TaiList: []models.Tai{tai1}},
+				{NsagId: 3, SnssaiList: []models.Snssai{snssai1}, TaiList: []models.Tai{tai2}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		tai         *models.Tai
+		expectedIds []int32
+	}{
+		{name: "TA in scope of NSAG 2", tai: &tai1, expectedIds: []int32{2, 1}},
+		{name: "TA in scope of NSAG 3", tai: &tai2, expectedIds: []int32{1, 3}},
+		{name: "no TAI", tai: nil, expectedIds: []int32{2, 1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsagInfos := GetNsagInfosFromConfig(testSnapshot(), tt.tai, []models.Snssai{snssai1, snssai2})
+			if len(nsagInfos) != len(tt.expectedIds) {
+				t.Fatalf("expected %d NSAGs, got %+v", len(tt.expectedIds), nsagInfos)
+			}
+			for i, nsagInfo := range nsagInfos {
+				if nsagInfo.NsagIds[0] != tt.expectedIds[i] {
+					t.Fatalf("expected NSAG %d at position %d, got %+v", tt.expectedIds[i], i, nsagInfos)
+				}
+				for _, snssai := range nsagInfo.SnssaiList {
+					if snssai.Sst == snssai3.Sst {
+						t.Fatalf("S-NSSAI outside of the given NSSAI must not be included: %+v", nsagInfo)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNsagOfSnpnDoesNotApplyToOtherNid(t *testing.T) {
+	originalFactoryConfig := factory.NssfConfig
+	defer func() {
+		factory.NssfConfig = originalFactoryConfig
+	}()
+
+	plmnId := models.PlmnId{Mcc: "001", Mnc: "01"}
+	nsagTai := models.Tai{PlmnId: plmnId, Tac: "000001", Nid: openapi.PtrString("00112233445")}
+	otherNidTai := models.Tai{PlmnId: plmnId, Tac: "000001", Nid: openapi.PtrString("00112233446")}
+	snssai := models.Snssai{Sst: 1}
+
+	factory.NssfConfig = factory.Config{
+		Configuration: &factory.Configuration{
+			NsagList: []factory.NsagConfig{{NsagId: 1, SnssaiList: []models.Snssai{snssai}, TaiList: []models.Tai{nsagTai}}},
+		},
+	}
+
+	if !checkTaiInList(nsagTai, []models.Tai{nsagTai}) {
+		t.Fatal("expected the TAI to be in the list")
+	}
+	if checkTaiInList(otherNidTai, []models.Tai{nsagTai}) {
+		t.Fatal("expected a TAI of another NID not to be in the list")
+	}
+	if nsagInfos := GetNsagInfosFromConfig(testSnapshot(), &otherNidTai, []models.Snssai{snssai}); len(nsagInfos) != 0 {
+		t.Fatalf("expected the NSAG of another NID not to apply, got %+v", nsagInfos)
+	}
+	if nsagInfos := GetNsagInfosFromConfig(testSnapshot(), &nsagTai, []models.Snssai{snssai}); len(nsagInfos) != 1 {
+		t.Fatalf("expected the NSAG of the NID to apply, got %+v", nsagInfos)
+	}
+}
+
+func TestCheckRestrictedSnssaiInTa(t *testing.T) {
+	originalFactoryConfig := factory.NssfConfig
+	defer func() {
+		factory.NssfConfig = originalFactoryConfig
+	}()
+
+	servingPlmn := models.PlmnId{Mcc: "208", Mnc: "93"}
+	partnerPlmn := models.PlmnId{Mcc: "466", Mnc: "92"}
+	listedPlmn := models.PlmnId{Mcc: "310", Mnc: "410"}
+	otherPlmn := models.PlmnId{Mcc: "001", Mnc: "01"}
+	tai := models.Tai{PlmnId: servingPlmn, Tac: "000001"}
+	restrictedForPartner := models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
+	restrictedForRoamers := models.Snssai{Sst: 1, Sd: openapi.PtrString("000002")}
+	unrestricted := models.Snssai{Sst: 1, Sd: openapi.PtrString("000003")}
+
+	accessType := models.ACCESSTYPE__3_GPP_ACCESS
+	factory.NssfConfig = factory.Config{
+		Configuration: &factory.Configuration{
+			TaList: []factory.TaConfig{{
+				Tai:                 &tai,
+				AccessType:          &accessType,
+				SupportedSnssaiList: []models.Snssai{restrictedForPartner, restrictedForRoamers, unrestricted},
+				RestrictedSnssaiList: []models.RestrictedSnssai{
+					{
+						HomePlmnId:     partnerPlmn,
+						HomePlmnIdList: []models.PlmnId{listedPlmn},
+						SNssaiList:     []models.Snssai{restrictedForPartner},
+					},
+					{
+						HomePlmnId:         servingPlmn,
+						RoamingRestriction: openapi.PtrBool(true),
+						SNssaiList:         []models.Snssai{restrictedForRoamers},
+					},
+				},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		snssai     models.Snssai
+		homePlmnId models.PlmnId
+		expected   bool
+	}{
+		{name: "restricted for the HPLMN", snssai: restrictedForPartner, homePlmnId: partnerPlmn, expected: true},
+		{name: "restricted for an HPLMN of the list", snssai: restrictedForPartner, homePlmnId: listedPlmn, expected: true},
+		{name: "not restricted for another HPLMN", snssai: restrictedForPartner, homePlmnId: otherPlmn, expected: false},
+		{name: "restricted for all roaming UEs", snssai: restrictedForRoamers, homePlmnId: otherPlmn, expected: true},
+		{name: "UE in its HPLMN is not restricted", snssai: restrictedForRoamers, homePlmnId: servingPlmn, expected: false},
+		{name: "unrestricted S-NSSAI", snssai: unrestricted, homePlmnId: partnerPlmn, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := CheckRestrictedSnssaiInTa(testSnapshot(), tt.snssai, tai, nil, tt.homePlmnId); result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAddAllowedSnssaiForAccessTypesWithoutTai(t *testing.T) {
+	originalFactoryConfig := factory.NssfConfig
+	defer func() {
+		factory.NssfConfig = originalFactoryConfig
+	}()
+
+	snssai := models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
+	tests := []struct {
+		name     string
+		policy   string
+		expected []models.AccessType
+	}{
+		{name: "default policy", policy: "", expected: []models.AccessType{models.ACCESSTYPE__3_GPP_ACCESS}},
+		{
+			name:     "3GPP access only",
+			policy:   factory.AccessTypeWithoutTai3gpp,
+			expected: []models.AccessType{models.ACCESSTYPE__3_GPP_ACCESS},
+		},
+		{
+			name:     "non-3GPP access only",
+			policy:   factory.AccessTypeWithoutTaiNon3gpp,
+			expected: []models.AccessType{models.ACCESSTYPE_NON_3_GPP_ACCESS},
+		},
+		{
+			name:     "all access types",
+			policy:   factory.AccessTypeWithoutTaiAll,
+			expected: []models.AccessType{models.ACCESSTYPE__3_GPP_ACCESS, models.ACCESSTYPE_NON_3_GPP_ACCESS},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory.NssfConfig = factory.Config{
+				Configuration: &factory.Configuration{AccessTypeWithoutTai: tt.policy},
+			}
+
+			authorizedNetworkSliceInfo := models.NewAuthorizedNetworkSliceInfo()
+			AddAllowedSnssai(*models.NewAllowedSnssai(snssai), GetAccessTypesFromConfig(testSnapshot(), snssai, nil, nil),
+				authorizedNetworkSliceInfo)
+
+			if len(authorizedNetworkSliceInfo.AllowedNssaiList) != len(tt.expected) {
+				t.Fatalf("expected Allowed NSSAI for %v, got %+v", tt.expected, authorizedNetworkSliceInfo.AllowedNssaiList)
+			}
+			for i, allowedNssai := range authorizedNetworkSliceInfo.AllowedNssaiList {
+				if allowedNssai.AccessType != tt.expected[i] || len(allowedNssai.AllowedSnssaiList) != 1 {
+					t.Errorf("unexpected Allowed NSSAI %+v, expected one S-NSSAI in %s", allowedNssai, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSnssaisOfTaPerAccessType(t *testing.T) {
+	originalFactoryConfig := factory.NssfConfig
+	defer func() {
+		factory.NssfConfig = originalFactoryConfig
+	}()
+
+	servingPlmn := models.PlmnId{Mcc: "208", Mnc: "93"}
+	tai := models.Tai{PlmnId: servingPlmn, Tac: "000001"}
+	common := models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
+	gnbOnly := models.Snssai{Sst: 1, Sd: openapi.PtrString("000002")}
+	n3iwfOnly := models.Snssai{Sst: 1, Sd: openapi.PtrString("000003")}
+	factory.NssfConfig = factory.Config{
+		Configuration: &factory.Configuration{
+			TaList: []factory.TaConfig{{
+				Tai: &tai,
+				AccessTypeList: []factory.TaAccessTypeConfig{
+					{
+						AccessType:          models.ACCESSTYPE__3_GPP_ACCESS,
+						SupportedSnssaiList: []models.Snssai{common, gnbOnly},
+					},
+					{
+						AccessType:          models.ACCESSTYPE_NON_3_GPP_ACCESS,
+						SupportedSnssaiList: []models.Snssai{common, n3iwfOnly},
+					},
+				},
+			}},
+		},
+	}
+	threeGpp := models.ACCESSTYPE__3_GPP_ACCESS
+	nonThreeGpp := models.ACCESSTYPE_NON_3_GPP_ACCESS
+
+	tests := []struct {
+		name        string
+		snssai      models.Snssai
+		accessType  *models.AccessType
+		supported   bool
+		accessTypes []models.AccessType
+	}{
+		{
+			name:        "S-NSSAI of both access types",
+			snssai:      common,
+			supported:   true,
+			accessTypes: []models.AccessType{threeGpp, nonThreeGpp},
+		},
+		{
+			name:        "S-NSSAI of the indicated access type",
+			snssai:      common,
+			accessType:  &nonThreeGpp,
+			supported:   true,
+			accessTypes: []models.AccessType{nonThreeGpp},
+		},
+		{
+			name:        "S-NSSAI of one access type",
+			snssai:      n3iwfOnly,
+			supported:   true,
+			accessTypes: []models.AccessType{nonThreeGpp},
+		},
+		{
+			name:       "S-NSSAI of another access type than indicated",
+			snssai:     gnbOnly,
+			accessType: &nonThreeGpp,
+			supported:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if supported := CheckSupportedSnssaiInTa(testSnapshot(), tt.snssai, tai, tt.accessType); supported != tt.supported {
+				t.Fatalf("expected supported %v, got %v", tt.supported, supported)
+			}
+			if !tt.supported {
+				return
+			}
+			accessTypes := GetAccessTypesFromConfig(testSnapshot(), tt.snssai, &tai, tt.accessType)
+			if !reflect.DeepEqual(accessTypes, tt.accessTypes) {
+				t.Errorf("expected access types %v, got %v", tt.accessTypes, accessTypes)
+			}
+		})
+	}
+
+	authorized := AuthorizeOfTaListFromConfig(testSnapshot(), []models.Tai{tai}, nil)
+	if len(authorized) != 1 || len(authorized[0].SupportedSnssaiList) != 3 {
+		t.Errorf("expected the S-NSSAIs of all access types to be authorized once, got %+v", authorized)
+	}
+}
+
+func TestTrimAllowedNssai(t *testing.T) {
+	originalFactoryConfig := factory.NssfConfig
+	defer func() {
+		factory.NssfConfig = originalFactoryConfig
+	}()
+
+	snssais := make([]models.Snssai, 4)
+	for i := range snssais {
+		snssais[i] = models.Snssai{Sst: 1, Sd: openapi.PtrString(fmt.Sprintf("00000%d", i+1))}
+	}
+	factory.NssfConfig = factory.Config{
+		Configuration: &factory.Configuration{
+			AllowedNssai: &factory.AllowedNssaiConfig{
+				Limit: 2,
+				PriorityList: []factory.SnssaiPriorityConfig{
+					{Snssai: snssais[3], Priority: 1},
+					{Snssai: snssais[2], Priority: 2},
+				},
+			},
+		},
+	}
+
+	authorizedNetworkSliceInfo := models.NewAuthorizedNetworkSliceInfo()
+	AddAllowedSnssai(*models.NewAllowedSnssai(snssais[0]),
+		[]models.AccessType{models.ACCESSTYPE__3_GPP_ACCESS, models.ACCESSTYPE_NON_3_GPP_ACCESS}, authorizedNetworkSliceInfo)
+	for _, snssai := range snssais[1:] {
+		AddAllowedSnssai(*models.NewAllowedSnssai(snssai), []models.AccessType{models.ACCESSTYPE__3_GPP_ACCESS},
+			authorizedNetworkSliceInfo)
+	}
+
+	rejected := TrimAllowedNssai(testSnapshot(), authorizedNetworkSliceInfo)
+
+	var allowed []factory.SnssaiKey
+	for _, allowedSnssai := range authorizedNetworkSliceInfo.AllowedNssaiList[0].AllowedSnssaiList {
+		allowed = append(allowed, factory.SnssaiToKey(allowedSnssai.AllowedSnssai))
+	}
+	expected := []factory.SnssaiKey{factory.SnssaiToKey(snssais[3]), factory.SnssaiToKey(snssais[2])}
+	if !reflect.DeepEqual(allowed, expected) {
+		t.Errorf("expected the S-NSSAIs of highest priority %v to be kept, got %v", expected, allowed)
+	}
+	// The first S-NSSAI is trimmed in 3GPP access, but still allowed in non-3GPP access
+	if len(rejected) != 1 || factory.SnssaiToKey(rejected[0]) != factory.SnssaiToKey(snssais[1]) {
+		t.Errorf("expected %+v to be rejected, got %+v", snssais[1], rejected)
+	}
+}
+
+func TestMostSpecificTaConfigApplies(t *testing.T) {
+	originalFactoryConfig := factory.NssfConfig
+	defer func() {
+		factory.NssfConfig = originalFactoryConfig
+	}()
+
+	servingPlmn := models.PlmnId{Mcc: "208", Mnc: "93"}
+	otherPlmn := models.PlmnId{Mcc: "466", Mnc: "92"}
+	plmnSnssai := models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
+	rangeSnssai := models.Snssai{Sst: 1, Sd: openapi.PtrString("000002")}
+	taSnssai := models.Snssai{Sst: 1, Sd: openapi.PtrString("000003")}
+	accessType := models.ACCESSTYPE__3_GPP_ACCESS
+	ta := models.Tai{PlmnId: servingPlmn, Tac: "000010"}
+	factory.NssfConfig = factory.Config{
+		Configuration: &factory.Configuration{
+			TaList: []factory.TaConfig{
+				{
+					PlmnId:              &servingPlmn,
+					AccessType:          &accessType,
+					SupportedSnssaiList: []models.Snssai{plmnSnssai},
+				},
+				{
+					TaiRange: &models.TaiRange{
+						PlmnId: servingPlmn,
+						TacRangeList: []models.TacRange{
+							{Start: openapi.PtrString("000001"), End: openapi.PtrString("0000ff")},
+							{Pattern: openapi.PtrString("1[0-9A-Fa-f]{5}")},
+						},
+					},
+					AccessType:          &accessType,
+					SupportedSnssaiList: []models.Snssai{rangeSnssai},
+				},
+				{
+					Tai:                 &ta,
+					AccessType:          &accessType,
+					SupportedSnssaiList: []models.Snssai{taSnssai},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		tai       models.Tai
+		supported bool
+		expected  models.Snssai
+	}{
+		{name: "TA entry", tai: ta, supported: true, expected: taSnssai},
+		{name: "TAC within start and end", tai: models.Tai{PlmnId: servingPlmn, Tac: "0000A0"}, supported: true, expected: rangeSnssai},
+		{name: "TAC matching pattern", tai: models.Tai{PlmnId: servingPlmn, Tac: "1000ab"}, supported: true, expected: rangeSnssai},
+		{name: "PLMN entry", tai: models.Tai{PlmnId: servingPlmn, Tac: "000100"}, supported: true, expected: plmnSnssai},
+		{name: "TAI of another PLMN", tai: models.Tai{PlmnId: otherPlmn, Tac: "000010"}, supported: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if supported := CheckSupportedTa(testSnapshot(), tt.tai); supported != tt.supported {
+				t.Fatalf("expected supported TA %v, got %v", tt.supported, supported)
+			}
+			if !tt.supported {
+				return
+			}
+			for _, snssai := range []models.Snssai{plmnSnssai, rangeSnssai, taSnssai} {
+				expected := factory.SnssaiToKey(snssai) == factory.SnssaiToKey(tt.expected)
+				if supported := CheckSupportedSnssaiInTa(testSnapshot(), snssai, tt.tai, nil); supported != expected {
+					t.Errorf("expected S-NSSAI %+v supported %v, got %v", snssai, expected, supported)
+				}
+			}
+			authorized := AuthorizeOfTaListFromConfig(testSnapshot(), []models.Tai{tt.tai}, nil)
+			if len(authorized) != 1 || !reflect.DeepEqual(authorized[0].SupportedSnssaiList, []models.Snssai{tt.expected}) {
+				t.Errorf("expected authorized S-NSSAI %+v, got %+v", tt.expected, authorized)
+			}
+		})
+	}
+}
+
+func TestAuthorizeNssaiAvailabilityData(t *testing.T) {
+	plmnId := models.PlmnId{Mcc: "208", Mnc: "93"}
+	tai1 := models.Tai{PlmnId: plmnId, Tac: "000001"}
+	tai2 := models.Tai{PlmnId: plmnId, Tac: "000002"}
+	snssai1 := models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
+	snssai2 := models.Snssai{Sst: 1, Sd: openapi.PtrString("000002")}
+	unknownSnssai := models.Snssai{Sst: 1, Sd: openapi.PtrString("0000ff")}
+	supportedNssai := factory.SupportedNssaiInPlmn{
+		plmnId: {factory.SnssaiToKey(snssai1): {}, factory.SnssaiToKey(snssai2): {}},
+	}
+	availability := []models.SupportedNssaiAvailabilityData{
+		{Tai: tai1, SupportedSnssaiList: []models.Snssai{snssai1, snssai2, unknownSnssai}},
+		{Tai: tai2, SupportedSnssaiList: []models.Snssai{snssai1, snssai2}},
+	}
+
+	tests := []struct {
+		name       string
+		policy     []factory.NssaiAvailabilityRule
+		nfId       string
+		authorized [][]models.Snssai
+		rejected   []RejectedSnssaiAvailability
+	}{
+		{
+			name:       "without policy, S-NSSAIs supported in the PLMN are authorized",
+			nfId:       "amf-1",
+			authorized: [][]models.Snssai{{snssai1, snssai2}, {snssai1, snssai2}},
+			rejected:   []RejectedSnssaiAvailability{{Snssai: unknownSnssai, Tai: tai1, Cause: REJECTED_CAUSE_NOT_SUPPORTED_IN_PLMN}},
+		},
+		{
+			name: "rules of the AMF Set apply per TA and per S-NSSAI",
+			policy: []factory.NssaiAvailabilityRule{
+				{AmfSetIdList: []string{"set-1"}, SnssaiList: []models.Snssai{snssai1}},
+				{AmfIdList: []string{"amf-1"}, TaiList: []models.Tai{tai2}, SnssaiList: []models.Snssai{snssai2}},
+			},
+			nfId:       "amf-1",
+			authorized: [][]models.Snssai{{snssai1}, {snssai1, snssai2}},
+			rejected: []RejectedSnssaiAvailability{
+				{Snssai: snssai2, Tai: tai1, Cause: REJECTED_CAUSE_NOT_AUTHORIZED},
+				{Snssai: unknownSnssai, Tai: tai1, Cause: REJECTED_CAUSE_NOT_SUPPORTED_IN_PLMN},
+			},
+		},
+		{
+			name:       "rules of other AMFs do not apply",
+			policy:     []factory.NssaiAvailabilityRule{{AmfIdList: []string{"amf-2"}}},
+			nfId:       "amf-1",
+			authorized: [][]models.Snssai{{}, {}},
+			rejected: []RejectedSnssaiAvailability{
+				{Snssai: snssai1, Tai: tai1, Cause: REJECTED_CAUSE_NOT_AUTHORIZED},
+				{Snssai: snssai2, Tai: tai1, Cause: REJECTED_CAUSE_NOT_AUTHORIZED},
+				{Snssai: unknownSnssai, Tai: tai1, Cause: REJECTED_CAUSE_NOT_SUPPORTED_IN_PLMN},
+				{Snssai: snssai1, Tai: tai2, Cause: REJECTED_CAUSE_NOT_AUTHORIZED},
+				{Snssai: snssai2, Tai: tai2, Cause: REJECTED_CAUSE_NOT_AUTHORIZED},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := factory.NewSnapshot(&factory.Configuration{
+				SupportedNssaiInPlmnList: supportedNssai,
+				AmfSetList:               []factory.AmfSetConfig{{AmfSetId: "set-1", AmfList: []string{"amf-1"}}},
+				NssaiAvailabilityPolicy:  tt.policy,
+			})
+			authorized, rejected := AuthorizeNssaiAvailabilityData(cfg, tt.nfId, availability)
+			if len(authorized) != len(tt.authorized) {
+				t.Fatalf("expected %d NSSAI availability data, got %+v", len(tt.authorized), authorized)
+			}
+			for i := range authorized {
+				if !reflect.DeepEqual(authorized[i].SupportedSnssaiList, tt.authorized[i]) {
+					t.Errorf("expected authorized S-NSSAIs %+v in %s, got %+v", tt.authorized[i],
+						factory.TaiString(authorized[i].Tai), authorized[i].SupportedSnssaiList)
+				}
+			}
+			if !reflect.DeepEqual(rejected, tt.rejected) {
+				t.Errorf("expected rejected %+v, got %+v", tt.rejected, rejected)
+			}
+		})
+	}
+}
+
+func TestAmfSetNssaiAvailabilityIsAggregatedFromMembers(t *testing.T) {
+	plmnId := models.PlmnId{Mcc: "208", Mnc: "93"}
+	tai := models.Tai{PlmnId: plmnId, Tac: "000001"}
+	snssai1 := models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
+	snssai2 := models.Snssai{Sst: 1, Sd: openapi.PtrString("000002")}
+	snssai3 := models.Snssai{Sst: 1, Sd: openapi.PtrString("000003")}
+	cfg := factory.NewSnapshot(&factory.Configuration{
+		AmfSetList: []factory.AmfSetConfig{
+			{
+				AmfSetId: "set-1",
+				AmfList:  []string{"amf-1", "amf-2"},
+				// Stale NSSAI availability of the configuration file
+				SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+					{Tai: tai, SupportedSnssaiList: []models.Snssai{snssai3}},
+				},
+			},
+		},
+		AmfList: []factory.AmfConfig{
+			{
+				NfId:    "amf-1",
+				Version: 1,
+				SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+					{Tai: tai, SupportedSnssaiList: []models.Snssai{snssai1}},
+				},
+			},
+			{
+				NfId:     "amf-3",
+				AmfSetId: "set-2",
+				Version:  2,
+				SupportedNssaiAvailabilityData: []models.SupportedNssaiAvailabilityData{
+					{Tai: tai, SupportedSnssaiList: []models.Snssai{snssai2}},
+				},
+			},
+		},
+	})
+
+	allowedNssai := func(snssai models.Snssai) []models.AllowedNssai {
+		return []models.AllowedNssai{{
+			AllowedSnssaiList: []models.AllowedSnssai{{AllowedSnssai: snssai}},
+			AccessType:        models.ACCESSTYPE__3_GPP_ACCESS,
+		}}
+	}
+	if !CheckAllowedNssaiInAmfTa(cfg, allowedNssai(snssai1), "amf-2", tai) {
+		t.Errorf("expected amf-2 to support the S-NSSAI its AMF Set member provided")
+	}
+	if CheckAllowedNssaiInAmfTa(cfg, allowedNssai(snssai3), "amf-2", tai) {
+		t.Errorf("expected the NSSAI availability of the configuration file to be replaced by the one of the members")
+	}
+
+	info := &models.AuthorizedNetworkSliceInfo{AllowedNssaiList: allowedNssai(snssai2)}
+	AddAmfInformation(cfg, tai, info)
+	if info.GetTargetAmfSet() != "set-2" || info.NrfAmfSet != nil || len(info.CandidateAmfList) != 0 {
+		t.Errorf("expected the AMF Set of amf-3 to be selected, got %+v", info)
+	}
+
+	info = &models.AuthorizedNetworkSliceInfo{AllowedNssaiList: allowedNssai(snssai1)}
+	AddAmfInformation(cfg, tai, info)
+	if !reflect.DeepEqual(info.CandidateAmfList, []string{"amf-1"}) {
+		t.Errorf("expected the member of set-1 which provided its NSSAI availability to be the candidate, got %+v", info)
+	}
+}
+
+func TestAmfSetWithoutAvailableMembersIsNotSelected(t *testing.T) {
+	tai := models.Tai{PlmnId: models.PlmnId{Mcc: "208", Mnc: "93"}, Tac: "000001"}
+	snssai := models.Snssai{Sst: 1, Sd: openapi.PtrString("000001")}
+	availability := []models.SupportedNssaiAvailabilityData{{Tai: tai, SupportedSnssaiList: []models.Snssai{snssai}}}
+	amfSetList := []factory.AmfSetConfig{{
+		AmfSetId: "set-1",
+		AmfList:  []string{"amf-1", "amf-2", "amf-3"},
+		// NSSAI availability of the configuration file
+		SupportedNssaiAvailabilityData: availability,
+	}}
+	tests := []struct {
+		name             string
+		amfList          []factory.AmfConfig
+		removedAmfSetIds []string
+		candidates       []string
+	}{
+		{
+			name:       "no member provided its NSSAI availability",
+			candidates: []string{"amf-1", "amf-2", "amf-3"},
+		},
+		{
+			name: "suspended and unknown members are not candidates",
+			amfList: []factory.AmfConfig{
+				{NfId: "amf-1", Version: 1, SupportedNssaiAvailabilityData: availability},
+				{NfId: "amf-2", Version: 1, Suspended: true, SupportedNssaiAvailabilityData: availability},
+			},
+			candidates: []string{"amf-1"},
+		},
+		{
+			name: "all members suspended",
+			amfList: []factory.AmfConfig{
+				{NfId: "amf-1", Version: 1, Suspended: true, SupportedNssaiAvailabilityData: availability},
+				{NfId: "amf-2", Version: 1, Suspended: true, SupportedNssaiAvailabilityData: availability},
+			},
+		},
+		{
+			name:             "all members removed",
+			removedAmfSetIds: []string{"set-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := factory.NewSnapshot(&factory.Configuration{
+				AmfSetList:       amfSetList,
+				AmfList:          tt.amfList,
+				RemovedAmfSetIds: tt.removedAmfSetIds,
+			})
+			info := &models.AuthorizedNetworkSliceInfo{AllowedNssaiList: []models.AllowedNssai{{
+				AllowedSnssaiList: []models.AllowedSnssai{{AllowedSnssai: snssai}},
+				AccessType:        models.ACCESSTYPE__3_GPP_ACCESS,
+			}}}
+			AddAmfInformation(cfg, tai, info)
+			if !reflect.DeepEqual(info.CandidateAmfList, tt.candidates) || info.TargetAmfSet != nil {
+				t.Errorf("expected candidate AMFs %+v, got %+v", tt.candidates, info)
+			}
+		})
+	}
+}
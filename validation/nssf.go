@@ -0,0 +1,147 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Validation
+ *
+ * Schemas of the data types of the NSSF services, TS 29.531 and the common data types of TS 29.571
+ */
+
+package validation
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/omec-project/nssf/factory"
+	"github.com/omec-project/nssf/sbi"
+	"github.com/omec-project/nssf/util"
+	"github.com/omec-project/openapi/v2/models"
+	"github.com/omec-project/openapi/v2/utils"
+)
+
+// Common data types, TS 29.571
+var (
+	snssai = object(map[string]*Schema{
+		"sst": integer(0, 255),
+		"sd":  matching(factory.SdPattern),
+	}, "sst")
+	plmnId = object(map[string]*Schema{
+		"mcc": matching(factory.MccPattern),
+		"mnc": matching(factory.MncPattern),
+	}, "mcc", "mnc")
+	tai = object(map[string]*Schema{
+		"plmnId": plmnId,
+		"tac":    matching(factory.TacPattern),
+		"nid":    matching(factory.NidPattern),
+	}, "plmnId", "tac")
+	tacRange = object(map[string]*Schema{
+		"start":   matching(factory.TacPattern),
+		"end":     matching(factory.TacPattern),
+		"pattern": str(),
+	})
+	taiRange = object(map[string]*Schema{
+		"plmnId":       plmnId,
+		"tacRangeList": array(tacRange, 1),
+		"nid":          matching(factory.NidPattern),
+	}, "plmnId", "tacRangeList")
+	supportedFeatures = pattern(`^[A-Fa-f0-9]*$`)
+	amfSetId          = pattern(`^[0-9]{3}-[0-9]{2,3}-[A-Fa-f0-9]{2}-[0-3][A-Fa-f0-9]{2}$`)
+	amfId             = pattern(`^[A-Fa-f0-9]{6}$`)
+	nfInstanceId      = pattern(`^[A-Fa-f0-9]{8}-[A-Fa-f0-9]{4}-[A-Fa-f0-9]{4}-[A-Fa-f0-9]{4}-[A-Fa-f0-9]{12}$`)
+)
+
+// Data types of the Nnssf_NSSelection service, TS 29.531 clause 6.1.6
+var (
+	subscribedSnssai = object(map[string]*Schema{
+		"subscribedSnssai":    snssai,
+		"defaultIndication":   boolean(),
+		"subscribedNsSrgList": array(str(), 1),
+	}, "subscribedSnssai")
+	allowedSnssai = object(map[string]*Schema{
+		"allowedSnssai":    snssai,
+		"mappedHomeSnssai": snssai,
+	}, "allowedSnssai")
+	allowedNssai = object(map[string]*Schema{
+		"allowedSnssaiList": array(allowedSnssai, 1),
+		"accessType":        enum(models.AllowedAccessTypeEnumValues),
+	}, "allowedSnssaiList", "accessType")
+	mappingOfSnssai = object(map[string]*Schema{
+		"servingSnssai": snssai,
+		"homeSnssai":    snssai,
+	}, "servingSnssai", "homeSnssai")
+
+	SliceInfoForRegistration = object(map[string]*Schema{
+		"subscribedNssai":            array(subscribedSnssai, 1),
+		"allowedNssaiCurrentAccess":  allowedNssai,
+		"allowedNssaiOtherAccess":    allowedNssai,
+		"sNssaiForMapping":           array(snssai, 1),
+		"requestedNssai":             array(snssai, 1),
+		"defaultConfiguredSnssaiInd": boolean(),
+		"mappingOfNssai":             array(mappingOfSnssai, 1),
+		"requestMapping":             boolean(),
+		"ueSupNssrgInd":              boolean(),
+		"suppressNssrgInd":           boolean(),
+		"nsagSupported":              boolean(),
+	})
+	SliceInfoForPDUSession = object(map[string]*Schema{
+		"sNssai":            snssai,
+		"roamingIndication": enum(models.AllowedRoamingIndicationEnumValues),
+		"homeSnssai":        snssai,
+	}, "sNssai", "roamingIndication")
+	NfType            = enum(models.AllowedNFTypeEnumValues)
+	NfInstanceId      = nfInstanceId
+	PlmnId            = plmnId
+	Tai               = tai
+	SupportedFeatures = supportedFeatures
+)
+
+// Data types of the Nnssf_NSSAIAvailability service, TS 29.531 clause 6.2.6
+var (
+	supportedNssaiAvailabilityData = object(map[string]*Schema{
+		"tai":                 tai,
+		"supportedSnssaiList": array(snssai, 1),
+		"taiList":             array(tai, 1),
+		"taiRangeList":        array(taiRange, 1),
+	}, "tai", "supportedSnssaiList")
+
+	NssaiAvailabilityInfo = object(map[string]*Schema{
+		"supportedNssaiAvailabilityData": array(supportedNssaiAvailabilityData, 1),
+		"supportedFeatures":              supportedFeatures,
+		"amfSetId":                       amfSetId,
+	}, "supportedNssaiAvailabilityData")
+	PatchDocument = array(object(map[string]*Schema{
+		"op":   enum(models.AllowedPatchOperationEnumValues),
+		"path": str(),
+		"from": str(),
+	}, "op", "path"), 1)
+	NssfEventSubscriptionCreateData = object(map[string]*Schema{
+		"nfNssaiAvailabilityUri": format(formatUri),
+		"taiList":                array(tai, 1),
+		"event":                  enum(models.AllowedNssfEventTypeEnumValues),
+		"additionalEvents":       array(enum(models.AllowedNssfEventTypeEnumValues), 1),
+		"expiry":                 format(formatDateTime),
+		"amfSetId":               amfSetId,
+		"taiRangeList":           array(taiRange, 1),
+		"amfId":                  amfId,
+		"supportedFeatures":      supportedFeatures,
+		"allAmfSetTaiInd":        boolean(),
+		"validityTimeSubList":    array(snssai, 1),
+	}, "nfNssaiAvailabilityUri", "event")
+)
+
+// ProblemDetailsInvalidBody returns the ProblemDetails of a request body which does not match its schema
+func ProblemDetailsInvalidBody(invalidParams []models.InvalidParam) *models.ProblemDetails {
+	problemDetails := utils.ProblemDetailsWithInvalidParams(util.INVALID_REQUEST, http.StatusBadRequest,
+		fmt.Sprintf("[Request Body] %d invalid parameters", len(invalidParams)), invalidParams)
+	problemDetails.SetCause(utils.CauseInvalidMsgFormat)
+	return problemDetails
+}
+
+// ProblemDetailsInvalidQuery returns the ProblemDetails of query parameters which do not match their schema
+func ProblemDetailsInvalidQuery(invalidParams []models.InvalidParam) *models.ProblemDetails {
+	problemDetails := utils.ProblemDetailsWithInvalidParams(util.INVALID_REQUEST, http.StatusBadRequest,
+		fmt.Sprintf("[Query Parameter] %d invalid parameters", len(invalidParams)), invalidParams)
+	problemDetails.SetCause(sbi.CauseInvalidQueryParam)
+	return problemDetails
+}
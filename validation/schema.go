@@ -0,0 +1,333 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+ * NSSF Validation
+ *
+ * Validation of the requests against the OpenAPI schemas of their data types
+ */
+
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omec-project/openapi/v2/models"
+)
+
+// Types of the schemas
+const (
+	typeObject  = "object"
+	typeArray   = "array"
+	typeString  = "string"
+	typeInteger = "integer"
+	typeBoolean = "boolean"
+)
+
+// Formats of the string schemas
+const (
+	formatUri      = "uri"
+	formatDateTime = "date-time"
+)
+
+// Schema is the subset of an OpenAPI schema which the requests to the NSSF are validated against.
+// Properties which are not in the schema are not validated, as 3GPP data types are extensible.
+type Schema struct {
+	typ string
+	// Object properties, and those which are required
+	properties map[string]*Schema
+	required   []string
+	// Array items, and their minimum number
+	items    *Schema
+	minItems int
+	// String constraints
+	pattern *regexp.Regexp
+	enum    []string
+	format  string
+	// Integer constraints
+	minimum, maximum int64
+}
+
+func object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{typ: typeObject, properties: properties, required: required}
+}
+
+func array(items *Schema, minItems int) *Schema {
+	return &Schema{typ: typeArray, items: items, minItems: minItems}
+}
+
+func str() *Schema {
+	return &Schema{typ: typeString}
+}
+
+func pattern(expr string) *Schema {
+	return &Schema{typ: typeString, pattern: regexp.MustCompile(expr)}
+}
+
+func matching(re *regexp.Regexp) *Schema {
+	return &Schema{typ: typeString, pattern: re}
+}
+
+func format(format string) *Schema {
+	return &Schema{typ: typeString, format: format}
+}
+
+func enum[T ~string](values []T) *Schema {
+	s := &Schema{typ: typeString}
+	for _, value := range values {
+		s.enum = append(s.enum, string(value))
+	}
+	return s
+}
+
+func integer(minimum, maximum int64) *Schema {
+	return &Schema{typ: typeInteger, minimum: minimum, maximum: maximum}
+}
+
+func boolean() *Schema {
+	return &Schema{typ: typeBoolean}
+}
+
+// validator collects the violations of a value of a request
+type validator struct {
+	// Whether the scalar values are strings, as those of query parameters
+	query bool
+	// Name of the invalid parameter at the given path of the value
+	paramName     func(path []string) string
+	invalidParams []models.InvalidParam
+}
+
+func (v *validator) fail(path []string, format string, args ...any) {
+	reason := fmt.Sprintf(format, args...)
+	v.invalidParams = append(v.invalidParams, models.InvalidParam{Param: v.paramName(path), Reason: &reason})
+}
+
+func (v *validator) validate(value any, s *Schema, path []string) {
+	switch s.typ {
+	case typeObject:
+		v.validateObject(value, s, path)
+	case typeArray:
+		v.validateArray(value, s, path)
+	case typeString:
+		v.validateString(value, s, path)
+	case typeInteger:
+		v.validateInteger(value, s, path)
+	case typeBoolean:
+		switch value := value.(type) {
+		case bool:
+		case string:
+			if !v.query || (value != "true" && value != "false") {
+				v.fail(path, "must be a boolean")
+			}
+		default:
+			v.fail(path, "must be a boolean")
+		}
+	}
+}
+
+func (v *validator) validateObject(value any, s *Schema, path []string) {
+	properties, ok := value.(map[string]any)
+	if !ok {
+		v.fail(path, "must be an object")
+		return
+	}
+	for _, name := range s.required {
+		if _, found := properties[name]; !found {
+			v.fail(append(slices.Clip(path), name), "is required")
+		}
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if property, found := s.properties[name]; found {
+			v.validate(properties[name], property, append(slices.Clip(path), name))
+		}
+	}
+}
+
+func (v *validator) validateArray(value any, s *Schema, path []string) {
+	var indexes []string
+	var items []any
+	switch value := value.(type) {
+	case []any:
+		for i, item := range value {
+			indexes = append(indexes, strconv.Itoa(i))
+			items = append(items, item)
+		}
+	case map[string]any:
+		// Exploded query parameters give the index of each item
+		if !v.query {
+			v.fail(path, "must be an array")
+			return
+		}
+		for index := range value {
+			if _, err := strconv.Atoi(index); err != nil {
+				v.fail(append(slices.Clip(path), index), "must be an array index")
+				return
+			}
+			indexes = append(indexes, index)
+		}
+		sort.Slice(indexes, func(i, j int) bool {
+			a, _ := strconv.Atoi(indexes[i])
+			b, _ := strconv.Atoi(indexes[j])
+			return a < b
+		})
+		for _, index := range indexes {
+			items = append(items, value[index])
+		}
+	default:
+		v.fail(path, "must be an array")
+		return
+	}
+	if len(items) < s.minItems {
+		v.fail(path, "must contain at least %d items", s.minItems)
+	}
+	for i, item := range items {
+		v.validate(item, s.items, append(slices.Clip(path), indexes[i]))
+	}
+}
+
+func (v *validator) validateString(value any, s *Schema, path []string) {
+	text, ok := value.(string)
+	if !ok {
+		v.fail(path, "must be a string")
+		return
+	}
+	if s.pattern != nil && !s.pattern.MatchString(text) {
+		v.fail(path, "must match the pattern %s", s.pattern)
+	}
+	if s.enum != nil && !slices.Contains(s.enum, text) {
+		v.fail(path, "must be one of %s", strings.Join(s.enum, ", "))
+	}
+	switch s.format {
+	case formatUri:
+		if uri, err := url.Parse(text); err != nil || !uri.IsAbs() || uri.Host == "" {
+			v.fail(path, "must be an absolute URI")
+		}
+	case formatDateTime:
+		if _, err := time.Parse(time.RFC3339, text); err != nil {
+			v.fail(path, "must be a date-time as in RFC 3339")
+		}
+	}
+}
+
+func (v *validator) validateInteger(value any, s *Schema, path []string) {
+	var text string
+	switch value := value.(type) {
+	case json.Number:
+		text = value.String()
+	case string:
+		if v.query {
+			text = value
+		}
+	}
+	number, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		v.fail(path, "must be an integer")
+		return
+	}
+	if number < s.minimum || number > s.maximum {
+		v.fail(path, "must be between %d and %d", s.minimum, s.maximum)
+	}
+}
+
+// jsonPointer returns the JSON pointer of the path, RFC 6901
+func jsonPointer(path []string) string {
+	var pointer strings.Builder
+	for _, name := range path {
+		pointer.WriteString("/" + strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1"))
+	}
+	return pointer.String()
+}
+
+// ValidateBody validates the JSON body of a request against the schema, and returns every violation with the
+// JSON pointer of the invalid parameter. A body which is not JSON is left to the decoder, which reports the error.
+func ValidateBody(body []byte, s *Schema) []models.InvalidParam {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil
+	}
+	v := validator{paramName: jsonPointer}
+	v.validate(value, s, nil)
+	return v.invalidParams
+}
+
+// ValidateQueryParameter validates the query parameter against the schema, whether it is JSON encoded or exploded
+// in deepObject style, and returns every violation. Invalid parameters are named in deepObject style,
+// e.g. tai[plmnId][mcc]. A JSON encoded parameter which is not JSON is left to the parser, which reports the error.
+func ValidateQueryParameter(query url.Values, name string, s *Schema) []models.InvalidParam {
+	v := validator{
+		query: true,
+		paramName: func(path []string) string {
+			var param strings.Builder
+			param.WriteString(name)
+			for _, segment := range path {
+				param.WriteString("[" + segment + "]")
+			}
+			return param.String()
+		},
+	}
+	if values, found := query[name]; found && len(values) != 0 {
+		if s.typ != typeObject && s.typ != typeArray {
+			v.validate(values[0], s, nil)
+			return v.invalidParams
+		}
+		var value any
+		decoder := json.NewDecoder(strings.NewReader(values[0]))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil
+		}
+		// Scalars of a JSON encoded parameter are typed as in a body
+		v.query = false
+		v.validate(value, s, nil)
+		return v.invalidParams
+	}
+
+	if value, found := explodedQueryParameter(query, name); found {
+		v.validate(value, s, nil)
+	}
+	return v.invalidParams
+}
+
+// explodedQueryParameter returns the value of a query parameter exploded in deepObject style, e.g. tai[tac]=0001,
+// as nested maps of the string values
+func explodedQueryParameter(query url.Values, name string) (any, bool) {
+	root := map[string]any{}
+	found := false
+	for key, values := range query {
+		segments, ok := strings.CutPrefix(key, name+"[")
+		if !ok || !strings.HasSuffix(segments, "]") || len(values) == 0 {
+			continue
+		}
+		found = true
+		path := strings.Split(strings.TrimSuffix(segments, "]"), "][")
+		node := root
+		for _, segment := range path[:len(path)-1] {
+			child, isMap := node[segment].(map[string]any)
+			if !isMap {
+				child = map[string]any{}
+				node[segment] = child
+			}
+			node = child
+		}
+		if _, isMap := node[path[len(path)-1]].(map[string]any); !isMap {
+			node[path[len(path)-1]] = values[0]
+		}
+	}
+	return root, found
+}
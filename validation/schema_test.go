@@ -0,0 +1,143 @@
+// Copyright (c) 2026 Intel Corporation
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/omec-project/openapi/v2/models"
+)
+
+func invalidParamNames(invalidParams []models.InvalidParam) []string {
+	var names []string
+	for _, invalidParam := range invalidParams {
+		names = append(names, invalidParam.Param)
+	}
+	return names
+}
+
+func TestValidateBodyReturnsEveryViolation(t *testing.T) {
+	body := `{
+		"supportedNssaiAvailabilityData": [
+			{"tai": {"plmnId": {"mcc": "208", "mnc": "93"}, "tac": "000001"}, "supportedSnssaiList": [{"sst": 1, "sd": "010203"}]},
+			{"tai": {"plmnId": {"mcc": "20", "mnc": "9a"}, "tac": "1"}, "supportedSnssaiList": [{"sst": 256}, {"sd": "xyz"}]},
+			{"supportedSnssaiList": []}
+		],
+		"amfSetId": "not-an-amf-set"
+	}`
+	expected := []string{
+		"/amfSetId",
+		"/supportedNssaiAvailabilityData/1/supportedSnssaiList/0/sst",
+		"/supportedNssaiAvailabilityData/1/supportedSnssaiList/1/sst",
+		"/supportedNssaiAvailabilityData/1/supportedSnssaiList/1/sd",
+		"/supportedNssaiAvailabilityData/1/tai/plmnId/mcc",
+		"/supportedNssaiAvailabilityData/1/tai/plmnId/mnc",
+		"/supportedNssaiAvailabilityData/1/tai/tac",
+		"/supportedNssaiAvailabilityData/2/tai",
+		"/supportedNssaiAvailabilityData/2/supportedSnssaiList",
+	}
+	if names := invalidParamNames(ValidateBody([]byte(body), NssaiAvailabilityInfo)); !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected invalid params %v, got %v", expected, names)
+	}
+
+	if invalidParams := ValidateBody([]byte(`{"supportedNssaiAvailabilityData": `), NssaiAvailabilityInfo); invalidParams != nil {
+		t.Errorf("expected a body which is not JSON to be left to the decoder, got %+v", invalidParams)
+	}
+}
+
+func TestValidateBodyChecksEnumsAndFormats(t *testing.T) {
+	body := `{"nfNssaiAvailabilityUri": "/notify", "event": "UNKNOWN", "expiry": "tomorrow"}`
+	expected := []string{"/event", "/expiry", "/nfNssaiAvailabilityUri"}
+	if names := invalidParamNames(ValidateBody([]byte(body), NssfEventSubscriptionCreateData)); !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected invalid params %v, got %v", expected, names)
+	}
+
+	patch := `[{"op": "add", "path": "/0/supportedSnssaiList/-", "value": {"sst": 1}}, {"op": "append"}]`
+	expected = []string{"/1/path", "/1/op"}
+	if names := invalidParamNames(ValidateBody([]byte(patch), PatchDocument)); !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected invalid params %v, got %v", expected, names)
+	}
+}
+
+func TestValidateQueryParameter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    url.Values
+		param    string
+		schema   *Schema
+		expected []string
+	}{
+		{
+			name: "JSON encoded parameter",
+			query: url.Values{"slice-info-request-for-pdu-session": {
+				`{"sNssai": {"sst": 1, "sd": "zz"}, "roamingIndication": "ROAMING"}`,
+			}},
+			param:  "slice-info-request-for-pdu-session",
+			schema: SliceInfoForPDUSession,
+			expected: []string{
+				"slice-info-request-for-pdu-session[roamingIndication]",
+				"slice-info-request-for-pdu-session[sNssai][sd]",
+			},
+		},
+		{
+			name: "exploded parameter",
+			query: url.Values{
+				"slice-info-request-for-registration[requestedNssai][0][sst]": {"1"},
+				"slice-info-request-for-registration[requestedNssai][2][sst]": {"one"},
+				"slice-info-request-for-registration[requestedNssai][2][sd]":  {"0102"},
+				"slice-info-request-for-registration[requestMapping]":         {"yes"},
+			},
+			param:  "slice-info-request-for-registration",
+			schema: SliceInfoForRegistration,
+			expected: []string{
+				"slice-info-request-for-registration[requestMapping]",
+				"slice-info-request-for-registration[requestedNssai][2][sd]",
+				"slice-info-request-for-registration[requestedNssai][2][sst]",
+			},
+		},
+		{
+			name:     "exploded parameter without required properties",
+			query:    url.Values{"tai[plmnId][mcc]": {"208"}},
+			param:    "tai",
+			schema:   Tai,
+			expected: []string{"tai[tac]", "tai[plmnId][mnc]"},
+		},
+		{
+			name:     "enumerated parameter",
+			query:    url.Values{"nf-type": {"AFM"}},
+			param:    "nf-type",
+			schema:   NfType,
+			expected: []string{"nf-type"},
+		},
+		{
+			name:     "NF instance ID which is not a UUID",
+			query:    url.Values{"nf-id": {"amf-1"}},
+			param:    "nf-id",
+			schema:   NfInstanceId,
+			expected: []string{"nf-id"},
+		},
+		{
+			name:   "valid parameter",
+			query:  url.Values{"home-plmn-id": {`{"mcc": "208", "mnc": "093"}`}},
+			param:  "home-plmn-id",
+			schema: PlmnId,
+		},
+		{
+			name:   "absent parameter",
+			query:  url.Values{"nf-type": {"AMF"}},
+			param:  "tai",
+			schema: Tai,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			names := invalidParamNames(ValidateQueryParameter(tc.query, tc.param, tc.schema))
+			if !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("expected invalid params %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}